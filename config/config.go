@@ -1,8 +1,11 @@
 package config
 
 import (
+	"fmt"
 	"log"
 	"os"
+	"reflect"
+	"time"
 
 	"gopkg.in/yaml.v2"
 )
@@ -12,10 +15,35 @@ var Cfg *Config
 
 // Config 应用配置结构
 type Config struct {
-	App      App      `yaml:"app"`
-	Database Database `yaml:"database"`
-	Redis    Redis    `yaml:"redis"`
-	Tracing  Tracing  `yaml:"tracing"`
+	// Version 配置结构版本（当前为 CurrentVersion），加载时由 migrateConfig 升级旧版本
+	Version    int        `yaml:"version"`
+	App        App        `yaml:"app"`
+	Database   Database   `yaml:"database"`
+	Redis      Redis      `yaml:"redis"`
+	Cache      Cache      `yaml:"cache"`
+	Background Background `yaml:"background"`
+	Metrics    Metrics    `yaml:"metrics"`
+	Log        Log        `yaml:"log"`
+	Tracing    Tracing    `yaml:"tracing"`
+	// Services 下游服务配置（服务名 -> 配置），如 serviceC
+	Services map[string]Service `yaml:"services"`
+	// HTTPClient 出站 HTTP 客户端配置
+	HTTPClient HTTPClient `yaml:"httpClient"`
+	// Health 健康检查配置
+	Health Health `yaml:"health"`
+	// Webhook 用户生命周期事件 webhook 配置
+	Webhook Webhook `yaml:"webhook"`
+}
+
+// Webhook 用户生命周期事件 webhook 配置
+// 用户创建、更新成功后异步向所有地址 POST 事件（请求体使用 secret 做 HMAC-SHA256 签名）
+type Webhook struct {
+	URLs   []string `yaml:"urls"`                    // 投递地址，为空时不启用
+	Secret string   `yaml:"secret" sensitive:"true"` // 签名密钥（X-Webhook-Signature: sha256=<hex>）
+	// SecretFile 签名密钥文件路径（Docker/K8s secrets），配置后覆盖 secret
+	SecretFile string        `yaml:"secretFile"`
+	Retries    int           `yaml:"retries"` // 投递失败后的重试次数（网络错误、5xx、429），默认3次
+	Timeout    time.Duration `yaml:"timeout"` // 单次投递超时，默认5s
 }
 
 // App 应用基础配置
@@ -25,6 +53,93 @@ type App struct {
 	GRPCPort    string `yaml:"grpcPort"`
 	GatewayPort string `yaml:"gatewayPort"`
 	Mode        string `yaml:"mode"`
+	// Middlewares 启用的全局中间件（按顺序），可选值：logger、tracing、contextLogger、requestTimeout
+	// 恢复中间件始终最先注册，不受此配置影响；未配置时默认为 [logger, tracing, contextLogger, requestTimeout]
+	Middlewares []string `yaml:"middlewares"`
+	TLS         TLS      `yaml:"tls"`
+	HTTP2       HTTP2    `yaml:"http2"`
+	// RecoveryMessage panic 时返回的错误消息，{trace_id} 替换为追踪ID（作为排查问题的参考编号）
+	RecoveryMessage string `yaml:"recoveryMessage"`
+	// ResponseCodeConvention 统一响应体 code 约定：http（默认，成功为 200）、zero（成功为 0，与服务C一致），错误码不受影响
+	ResponseCodeConvention string `yaml:"responseCodeConvention"`
+	// LegacyErrorStatus 兼容旧行为：错误响应也返回 HTTP 200（错误码仅在响应体中），迁移完成后应关闭
+	LegacyErrorStatus bool `yaml:"legacyErrorStatus"`
+	// TrimTrailingSlash 路由匹配前去除请求路径末尾的斜杠（根路径除外），默认关闭（使用 gin 的重定向行为）
+	TrimTrailingSlash bool `yaml:"trimTrailingSlash"`
+	// AdminToken 访问 /debug/pprof、/admin 和缓存预热等管理接口所需的 Bearer Token，为空时仅 debug 模式放行，其他模式拒绝访问
+	// v1 之前为 pprofToken，旧配置加载时自动迁移
+	AdminToken string `yaml:"adminToken" sensitive:"true"`
+	// AdminTokenFile AdminToken 的文件路径（Docker/K8s secrets），配置后覆盖 adminToken
+	AdminTokenFile string `yaml:"adminTokenFile"`
+	// DemoDownstream 内置的演示下游服务（模拟服务C），默认关闭
+	DemoDownstream DemoDownstream `yaml:"demoDownstream"`
+	// MaxRequestTimeout 请求头 X-Request-Timeout 传入的超时预算上限（默认 30s）
+	MaxRequestTimeout time.Duration `yaml:"maxRequestTimeout"`
+	// JSONFieldAliases JSON 请求体字段别名（别名 -> 规范字段名，如 user_name: name），用于客户端迁移期兼容
+	JSONFieldAliases map[string]string `yaml:"jsonFieldAliases"`
+	// CreateBulkhead 创建用户接口的并发限制（舱壁隔离），避免写入高峰拖垮其他接口
+	CreateBulkhead Bulkhead `yaml:"createBulkhead"`
+	// Maintenance 维护模式：开启时 /api 下的写接口返回 503，读接口不受影响（可通过 PUT /admin/maintenance 运行时切换）
+	Maintenance Maintenance `yaml:"maintenance"`
+	// Server HTTP 服务器超时和请求头大小限制（防御 slowloris 等慢速攻击）
+	Server Server `yaml:"server"`
+	// SecurityHeaders 安全响应头（面向浏览器的部署开启）
+	SecurityHeaders SecurityHeaders `yaml:"securityHeaders"`
+	// RequireTenant 多租户部署：/api 下的请求必须携带 X-Tenant-ID，缺失时返回 400，默认关闭
+	RequireTenant bool `yaml:"requireTenant"`
+}
+
+// SecurityHeaders 安全响应头配置，各响应头的值为空时不设置
+type SecurityHeaders struct {
+	Enabled                 bool   `yaml:"enabled"`                 // 是否启用，默认关闭
+	ContentTypeOptions      string `yaml:"contentTypeOptions"`      // X-Content-Type-Options，如 nosniff
+	FrameOptions            string `yaml:"frameOptions"`            // X-Frame-Options，如 DENY
+	ReferrerPolicy          string `yaml:"referrerPolicy"`          // Referrer-Policy，如 no-referrer
+	ContentSecurityPolicy   string `yaml:"contentSecurityPolicy"`   // Content-Security-Policy
+	StrictTransportSecurity string `yaml:"strictTransportSecurity"` // Strict-Transport-Security（仅 HTTPS 请求设置），如 max-age=31536000; includeSubDomains
+}
+
+// Server HTTP 服务器配置，未配置（0）的字段使用安全的默认值
+type Server struct {
+	ReadHeaderTimeout time.Duration `yaml:"readHeaderTimeout"` // 读取请求头超时，默认5s
+	ReadTimeout       time.Duration `yaml:"readTimeout"`       // 读取整个请求（含请求体）超时，默认30s
+	WriteTimeout      time.Duration `yaml:"writeTimeout"`      // 写响应超时，应大于请求超时预算和 pprof 采样时长，默认60s
+	IdleTimeout       time.Duration `yaml:"idleTimeout"`       // keep-alive 空闲连接超时，默认120s
+	MaxHeaderBytes    int           `yaml:"maxHeaderBytes"`    // 请求头最大字节数，默认1MB
+	MaxURLBytes       int           `yaml:"maxURLBytes"`       // 请求 URL（路径 + 查询参数）最大字节数，超过时返回 414，默认8KB，-1 表示不限制
+	MaxQueryBytes     int           `yaml:"maxQueryBytes"`     // 查询参数最大字节数，超过时返回 414，默认4KB，-1 表示不限制
+	ShutdownTimeout   time.Duration `yaml:"shutdownTimeout"`   // 收到 SIGINT/SIGTERM 后等待进行中请求完成的最长时间，默认15s
+}
+
+// Maintenance 维护模式配置
+type Maintenance struct {
+	Enabled    bool          `yaml:"enabled"`    // 启动时是否处于维护模式，默认关闭
+	RetryAfter time.Duration `yaml:"retryAfter"` // 写请求被拒绝时 Retry-After 响应头的值，默认30s
+}
+
+// Bulkhead 路由并发限制配置
+type Bulkhead struct {
+	MaxConcurrent int           `yaml:"maxConcurrent"` // 最大并发请求数，0 表示不限制
+	Wait          time.Duration `yaml:"wait"`          // 并发已满时最多等待的时间，超时返回 503，0 表示立即拒绝
+}
+
+// DemoDownstream 演示下游服务配置
+// 开启后在独立端口提供 /api/calculate 和 /api/process，使调用服务C的链路开箱即用
+type DemoDownstream struct {
+	Enabled bool   `yaml:"enabled"` // 是否启动，默认关闭
+	Port    string `yaml:"port"`    // 监听端口，默认8081（与服务C默认地址一致）
+}
+
+// HTTP2 HTTP/2 配置
+type HTTP2 struct {
+	H2C bool `yaml:"h2c"` // 是否支持明文 HTTP/2（h2c），用于前置代理使用 h2c 转发的场景
+}
+
+// TLS HTTPS 配置（由应用直接终止 TLS 时使用）
+type TLS struct {
+	Enabled  bool   `yaml:"enabled"`                  // 是否启用 HTTPS
+	CertFile string `yaml:"certFile"`                 // 证书文件路径
+	KeyFile  string `yaml:"keyFile" sensitive:"true"` // 私钥文件路径
 }
 
 // Database 数据库配置
@@ -34,35 +149,219 @@ type Database struct {
 
 // Mysql MySQL配置
 type Mysql struct {
-	Host         string `yaml:"host"`
-	Port         int    `yaml:"port"`
-	Username     string `yaml:"username"`
-	Password     string `yaml:"password"`
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Socket   string `yaml:"socket"` // Unix 套接字路径（如 /cloudsql/project:region:instance），配置后忽略 host 和 port
+	Username string `yaml:"username"`
+	Password string `yaml:"password" sensitive:"true"`
+	// PasswordFile 密码文件路径（Docker/K8s secrets），配置后覆盖 password
+	PasswordFile string `yaml:"passwordFile"`
 	Database     string `yaml:"database"`
 	Charset      string `yaml:"charset"`
 	ParseTime    bool   `yaml:"parseTime"`
 	Loc          string `yaml:"loc"`
-	MaxIdleConns int    `yaml:"maxIdleConns"`
-	MaxOpenConns int    `yaml:"maxOpenConns"`
+	// TLS 连接加密方式：空或 false 不启用；true、skip-verify、preferred 使用驱动内置配置；
+	// custom 使用 TLSCAFile 指定的 CA 证书校验服务端
+	TLS             string        `yaml:"tls"`
+	TLSCAFile       string        `yaml:"tlsCAFile"` // 自定义 CA 证书路径（TLS 为 custom 时必填）
+	MaxIdleConns    int           `yaml:"maxIdleConns"`
+	MaxOpenConns    int           `yaml:"maxOpenConns"`
+	ConnMaxLifetime time.Duration `yaml:"connMaxLifetime"` // 连接最大生存时间（如 1h），默认1小时
+	ConnMaxIdleTime time.Duration `yaml:"connMaxIdleTime"` // 连接最大空闲时间（如 10m），0 表示不限制，避免故障切换后持有失效连接
+	QueryTimeout    time.Duration `yaml:"queryTimeout"`    // 逻辑层单次数据库操作超时（请求截止时间更早时以请求为准），0 表示不限制
+	SlowThreshold   time.Duration `yaml:"slowThreshold"`   // 慢SQL阈值（如 200ms），超过时输出带 trace_id 的警告日志，默认1s
+	// DeadlockRetries 事务遇到死锁（1213）或锁等待超时（1205）时的重试次数（未配置时默认2次，0 表示不重试）
+	DeadlockRetries *int          `yaml:"deadlockRetries"`
+	DeadlockBackoff time.Duration `yaml:"deadlockBackoff"` // 死锁重试首次等待时间（之后每次翻倍），默认20ms
+	// CreateIfNotExists 启动时是否连接系统数据库执行 CREATE DATABASE IF NOT EXISTS（未配置时默认开启）
+	// 数据库用户没有 CREATE 权限的托管环境应设为 false，直接连接目标数据库
+	CreateIfNotExists *bool `yaml:"createIfNotExists"`
+	AutoMigrate       bool  `yaml:"autoMigrate"`   // 启动时是否执行 AutoMigrate（默认关闭，表结构以 create_tables.sql 为准）
+	MigrateDryRun     bool  `yaml:"migrateDryRun"` // 仅打印 AutoMigrate 计划执行的 DDL 后退出，不修改表结构
+	// Warmup 启动时预热连接池：建立 maxIdleConns 个 MySQL 连接，并为默认 Redis 实例的每个连接池槽位建立连接
+	Warmup bool `yaml:"warmup"`
 }
 
 // Redis Redis配置
 type Redis struct {
-	Addr     string `yaml:"addr"`
-	Password string `yaml:"password"`
-	DB       int    `yaml:"db"`
-	PoolSize int    `yaml:"poolSize"`
+	Addr         string `yaml:"addr"`
+	Password     string `yaml:"password" sensitive:"true"`
+	PasswordFile string `yaml:"passwordFile"` // 密码文件路径（Docker/K8s secrets），配置后覆盖 password
+	DB           int    `yaml:"db"`
+	PoolSize     int    `yaml:"poolSize"`
+	// Instances 额外的命名 Redis 实例（如限流、分布式锁专用），顶层配置为 default 实例
+	Instances []RedisInstance `yaml:"instances"`
+	// HealthCheckInterval 默认实例后台健康探测间隔（默认5s），探测失败期间读请求跳过缓存
+	HealthCheckInterval time.Duration `yaml:"healthCheckInterval"`
+}
+
+// RedisInstance 命名 Redis 实例配置
+type RedisInstance struct {
+	Name         string `yaml:"name"` // 实例名称（不能为 default），通过 database.Redis(name) 获取
+	Addr         string `yaml:"addr"`
+	Password     string `yaml:"password" sensitive:"true"`
+	PasswordFile string `yaml:"passwordFile"` // 密码文件路径，配置后覆盖 password
+	DB           int    `yaml:"db"`
+	PoolSize     int    `yaml:"poolSize"`
+}
+
+// Cache 缓存配置
+type Cache struct {
+	UserCountTTL int          `yaml:"userCountTTL"` // 用户统计缓存过期时间（秒），默认60秒
+	Local        LocalCache   `yaml:"local"`        // 进程内 LRU 缓存（位于 Redis 之前）
+	HTTPMaxAge   int          `yaml:"httpMaxAge"`   // 用户读取接口 Cache-Control 的 max-age（秒），默认60秒
+	MaxValueSize int          `yaml:"maxValueSize"` // 单个缓存值的最大字节数，超过时跳过缓存，默认64KB
+	RefreshAhead RefreshAhead `yaml:"refreshAhead"` // 热点用户缓存提前刷新
+	Warm         CacheWarm    `yaml:"warm"`         // 用户缓存预热（POST /api/user/cache/warm）
+	Coalesce     bool         `yaml:"coalesce"`     // 合并同一用户的并发查询（缓存击穿时只回源一次），默认关闭
+}
+
+// CacheWarm 用户缓存预热配置
+type CacheWarm struct {
+	BatchSize int `yaml:"batchSize"` // 每批加载并写入的用户数，默认500
+	TTLJitter int `yaml:"ttlJitter"` // 过期时间随机抖动上限（秒），避免预热的键同时过期，默认300秒
+}
+
+// RefreshAhead 缓存提前刷新配置
+// 命中 Redis 缓存且剩余过期时间低于阈值时，后台异步从数据库重新加载，避免热点数据过期后集中回源
+type RefreshAhead struct {
+	Enabled   bool `yaml:"enabled"`   // 是否启用，默认关闭
+	Threshold int  `yaml:"threshold"` // 剩余过期时间阈值（秒），默认300秒
+}
+
+// LocalCache 进程内 LRU 缓存配置
+// 每个实例独立缓存，其他实例的更新无法及时感知，需使用较短的过期时间
+type LocalCache struct {
+	Enabled bool `yaml:"enabled"` // 是否启用，默认关闭
+	Size    int  `yaml:"size"`    // 最大条目数，默认1000
+	TTL     int  `yaml:"ttl"`     // 过期时间（秒），默认10秒
+}
+
+// Background 后台任务配置（缓存写入等异步操作）
+type Background struct {
+	Workers   int `yaml:"workers"`   // 并发 worker 数，默认4
+	QueueSize int `yaml:"queueSize"` // 队列长度，队列满时丢弃任务，默认1000
+	// TaskTimeout 单个任务的执行超时（如 30s），超时后取消任务上下文，0 表示不限制
+	TaskTimeout time.Duration `yaml:"taskTimeout"`
+}
+
+// Metrics Prometheus 指标配置
+type Metrics struct {
+	Enabled bool `yaml:"enabled"` // 是否启用指标（启用后暴露 /metrics 接口）
+}
+
+// Service 下游服务配置
+type Service struct {
+	BaseURL string        `yaml:"baseURL"` // API 基础URL
+	Timeout time.Duration `yaml:"timeout"` // 单次调用超时（如 2s），与请求上下文的截止时间取较早者；0 表示仅使用全局客户端超时
+	Retries int           `yaml:"retries"` // 失败重试次数（网络错误、5xx、429），0 表示使用 httpClient 全局配置
+	// Fallback 降级配置：下游故障或熔断时返回最近一次成功的结果
+	Fallback ServiceFallback `yaml:"fallback"`
+	// Memoize 纯函数接口结果缓存（目前用于服务C计算接口），仅在确认接口无副作用且结果只取决于输入时开启
+	Memoize ServiceMemoize `yaml:"memoize"`
+}
+
+// ServiceMemoize 下游接口结果缓存配置（Redis）
+type ServiceMemoize struct {
+	Enabled bool          `yaml:"enabled"` // 是否启用，默认关闭
+	TTL     time.Duration `yaml:"ttl"`     // 结果缓存时间，应较短，默认1m
+}
+
+// ServiceFallback 下游服务降级配置
+type ServiceFallback struct {
+	Enabled bool          `yaml:"enabled"` // 是否启用，默认关闭
+	Size    int           `yaml:"size"`    // 缓存的最近成功结果数（按接口+参数），默认1000
+	TTL     time.Duration `yaml:"ttl"`     // 最近成功结果的有效期，过期后不再用于降级，默认10m
+}
+
+// HTTPClient 出站 HTTP 客户端配置
+type HTTPClient struct {
+	RetryCount      int           `yaml:"retryCount"`      // 失败重试次数，0 表示不重试；POST 等非幂等请求需显式标记才会重试
+	RetryMinBackoff time.Duration `yaml:"retryMinBackoff"` // 重试最小退避间隔，默认100ms
+	RetryMaxBackoff time.Duration `yaml:"retryMaxBackoff"` // 重试最大退避间隔，默认2s
+	Dump            bool          `yaml:"dump"`            // 记录出站请求/响应完整报文（敏感头脱敏），仅在 debug 模式下生效
+	UserAgent       string        `yaml:"userAgent"`       // User-Agent，默认为 <app.name>/<版本号>
+	// Headers 所有出站请求默认携带的公共请求头（请求级设置的同名头优先）
+	Headers map[string]string `yaml:"headers" sensitive:"true"`
+	// RetryBudget 全局重试预算（所有客户端共享），限制重试总量，避免故障期间的重试风暴
+	RetryBudget RetryBudget `yaml:"retryBudget"`
+}
+
+// RetryBudget 重试预算配置（令牌桶）
+type RetryBudget struct {
+	Rate  float64 `yaml:"rate"`  // 每秒允许的重试次数，0 表示不限制
+	Burst int     `yaml:"burst"` // 最多累积的重试次数（突发上限），默认等于 rate
+}
+
+// Health 健康检查配置
+type Health struct {
+	Dependencies []HealthDependency `yaml:"dependencies"` // 下游依赖检查（如 ServiceC）
+	Watchdog     Watchdog           `yaml:"watchdog"`     // 存活检查看门狗
+	StartupCheck StartupCheck       `yaml:"startupCheck"` // 启动诊断
+}
+
+// StartupCheck 启动诊断配置
+// 初始化完成后输出一条汇总生效配置和 MySQL、Redis 连接状态的结构化日志
+type StartupCheck struct {
+	FailFast bool          `yaml:"failFast"` // 依赖不可用时是否直接退出，默认关闭（仅输出 WARN 日志）
+	Timeout  time.Duration `yaml:"timeout"`  // 单个依赖探测超时，默认3s
+}
+
+// Watchdog 存活检查看门狗配置
+// 后台定期更新心跳，心跳超过阈值未更新时存活检查返回 503（用于发现死锁、调度停滞）
+type Watchdog struct {
+	Enabled   bool          `yaml:"enabled"`   // 是否启用，默认关闭
+	Interval  time.Duration `yaml:"interval"`  // 心跳间隔，默认1s
+	Threshold time.Duration `yaml:"threshold"` // 心跳超时阈值，默认10s
+}
+
+// HealthDependency 下游依赖健康检查配置
+type HealthDependency struct {
+	Name     string        `yaml:"name"`     // 依赖名称
+	URL      string        `yaml:"url"`      // 健康检查地址（GET，2xx 视为健康）
+	Timeout  time.Duration `yaml:"timeout"`  // 检查超时，默认1s
+	Required bool          `yaml:"required"` // 是否为硬依赖：true 时不可用则就绪检查失败；默认软依赖，仅标记为 degraded
+}
+
+// Log 日志配置
+type Log struct {
+	SlowRequestThreshold time.Duration `yaml:"slowRequestThreshold"` // 慢请求阈值（如 500ms），超过时输出 WARN 日志；0 表示不启用
+	// AccessLogSampling 访问日志跟随追踪采样决定：未采样的请求只输出精简日志（方法、路径、状态码、耗时），5xx 始终完整输出
+	AccessLogSampling bool `yaml:"accessLogSampling"`
+	// RedactFields 记录请求体（失败请求记录、出站报文）时额外脱敏的 JSON 字段路径，如 email、user.phone、items.card
+	// password、token 等敏感字段始终脱敏
+	RedactFields []string `yaml:"redactFields"`
+	// RequestCapture 调试模式下记录最近失败的请求（GET /debug/requests），便于复现问题
+	RequestCapture RequestCapture `yaml:"requestCapture"`
+}
+
+// RequestCapture 失败请求记录配置（仅在 app.mode 为 debug 时生效）
+type RequestCapture struct {
+	Enabled      bool `yaml:"enabled"`      // 是否启用
+	Size         int  `yaml:"size"`         // 最多保留的失败请求条数，默认50
+	MaxBodyBytes int  `yaml:"maxBodyBytes"` // 每条记录保留的请求体最大字节数，默认4096
 }
 
 // Tracing 追踪配置
 type Tracing struct {
-	Enabled      bool    `yaml:"enabled"`       // 总开关：是否启用追踪
-	Endpoint     string  `yaml:"endpoint"`      // Jaeger OTLP gRPC 端点
-	ServiceName  string  `yaml:"serviceName"`   // 服务名称
-	SampleRate   float64 `yaml:"sampleRate"`    // 采样率：0.0-1.0，1.0表示100%采样，0.1表示10%采样
-	BatchSize    int     `yaml:"batchSize"`     // 批量大小：每次批量导出的span数量
-	BatchTimeout int     `yaml:"batchTimeout"`  // 批量超时（秒）：超过此时间即使未达到批量大小也会导出
-	Cleanup      func()  `yaml:"-"`             // 用于关闭追踪提供者
+	Enabled      bool    `yaml:"enabled"`      // 总开关：是否启用追踪
+	Endpoint     string  `yaml:"endpoint"`     // Jaeger OTLP gRPC 端点
+	ServiceName  string  `yaml:"serviceName"`  // 服务名称
+	SampleRate   float64 `yaml:"sampleRate"`   // 采样率：0.0-1.0，1.0表示100%采样，0.1表示10%采样
+	BatchSize    int     `yaml:"batchSize"`    // 批量大小：每次批量导出的span数量
+	BatchTimeout int     `yaml:"batchTimeout"` // 批量超时（秒）：超过此时间即使未达到批量大小也会导出
+	SpanName     string  `yaml:"spanName"`     // span 命名策略：route（路由模板，默认）、method_route（方法+路由模板）
+	// DegradeAfter 导出持续失败多久后停止创建请求 span（如 10m），0 表示不降级
+	DegradeAfter time.Duration `yaml:"degradeAfter"`
+	// AllowForceTrace 是否接受 X-Force-Trace: 1 请求头强制采样（不受采样率限制）
+	AllowForceTrace bool `yaml:"allowForceTrace"`
+	// FailFast 启动时追踪端点不可连接则退出（默认仅输出警告，服务照常运行）
+	FailFast bool `yaml:"failFast"`
+	// StrictSpans 严格模式：pkg.RequireSpan 找不到有效 span 时 panic（CI/测试环境开启，尽早发现未传递 context），默认仅输出警告
+	StrictSpans bool `yaml:"strictSpans"`
+	// ConnectTimeout 启动时探测追踪端点的超时，默认2s
+	ConnectTimeout time.Duration `yaml:"connectTimeout"`
+	Cleanup        func()        `yaml:"-"` // 用于关闭追踪提供者
 }
 
 // LoadConfig 从配置文件加载配置
@@ -72,11 +371,22 @@ func LoadConfigWithPath(path string) (*Config, error) {
 		return nil, err
 	}
 
+	// 将旧版本的配置升级到当前版本（如重命名的字段），版本高于程序支持的版本时返回错误
+	data, err = migrateConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("迁移配置失败: %w", err)
+	}
+
 	var config Config
 	if err := yaml.Unmarshal(data, &config); err != nil {
 		return nil, err
 	}
 
+	// 从文件读取敏感配置（如 database.mysql.passwordFile），覆盖配置文件中的值
+	if err := resolveSecretFiles(reflect.ValueOf(&config)); err != nil {
+		return nil, err
+	}
+
 	return &config, nil
 }
 
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"fmt"
+	"log"
+
+	"gopkg.in/yaml.v2"
+)
+
+// CurrentVersion 当前配置结构版本（配置文件顶层 version 字段，未配置时视为 0）
+const CurrentVersion = 1
+
+// migration 配置迁移步骤，在 yaml 解析后的原始结构上升级到下一个版本，返回需要提示的变更
+type migration func(raw map[interface{}]interface{}) []string
+
+// migrations 配置迁移步骤：migrations[i] 将版本 i 升级到 i+1
+var migrations = []migration{
+	migrateV0ToV1,
+}
+
+// migrateConfig 将旧版本的配置升级到 CurrentVersion 并输出变更提示
+// 版本高于 CurrentVersion（配置来自更新的程序版本）时返回错误，避免新字段被静默忽略
+func migrateConfig(data []byte) ([]byte, error) {
+	raw := map[interface{}]interface{}{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	version := 0
+	if v, ok := raw["version"]; ok {
+		n, ok := v.(int)
+		if !ok || n < 0 {
+			return nil, fmt.Errorf("配置版本 %v 不合法", v)
+		}
+		version = n
+	}
+	if version > CurrentVersion {
+		return nil, fmt.Errorf("配置版本 %d 高于程序支持的版本 %d，请升级程序或使用对应版本的配置", version, CurrentVersion)
+	}
+	if version == CurrentVersion {
+		return data, nil
+	}
+
+	for ; version < CurrentVersion; version++ {
+		for _, notice := range migrations[version](raw) {
+			log.Printf("配置迁移 v%d -> v%d: %s", version, version+1, notice)
+		}
+	}
+	raw["version"] = CurrentVersion
+	log.Printf("配置文件版本较旧，已按 v%d 加载，请更新配置文件（设置 version: %d）", CurrentVersion, CurrentVersion)
+
+	return yaml.Marshal(raw)
+}
+
+// migrateV0ToV1 v1 将 app.pprofToken 重命名为 app.adminToken（该令牌同时保护 /admin 和缓存预热等管理接口）
+func migrateV0ToV1(raw map[interface{}]interface{}) []string {
+	app, ok := raw["app"].(map[interface{}]interface{})
+	if !ok {
+		return nil
+	}
+	var notices []string
+	notices = append(notices, renameKey(app, "app", "pprofToken", "adminToken")...)
+	notices = append(notices, renameKey(app, "app", "pprofTokenFile", "adminTokenFile")...)
+	return notices
+}
+
+// renameKey 将 section 中的 from 键重命名为 to，to 已存在时保留 to 并忽略 from
+func renameKey(section map[interface{}]interface{}, prefix, from, to string) []string {
+	value, ok := section[from]
+	if !ok {
+		return nil
+	}
+	delete(section, from)
+	if _, exists := section[to]; exists {
+		return []string{fmt.Sprintf("%s.%s 已被 %s.%s 取代，忽略 %s.%s", prefix, from, prefix, to, prefix, from)}
+	}
+	section[to] = value
+	return []string{fmt.Sprintf("%s.%s 已重命名为 %s.%s", prefix, from, prefix, to)}
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+)
+
+// captureLog 将标准库日志输出重定向到缓冲区，测试结束时恢复
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous := log.Writer()
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(previous) })
+	return &buf
+}
+
+func TestMigrateV0ConfigToV1(t *testing.T) {
+	out := captureLog(t)
+	path := writeFile(t, "conf.yaml", "app:\n  mode: release\n  pprofToken: legacy-token\n")
+
+	cfg, err := LoadConfigWithPath(path)
+	if err != nil {
+		t.Fatalf("LoadConfigWithPath: %v", err)
+	}
+	if cfg.Version != CurrentVersion {
+		t.Errorf("Version = %d, want %d", cfg.Version, CurrentVersion)
+	}
+	if cfg.App.AdminToken != "legacy-token" || cfg.App.Mode != "release" {
+		t.Errorf("app = {mode: %q, adminToken: %q}, want pprofToken 迁移为 adminToken 且其他字段保留", cfg.App.Mode, cfg.App.AdminToken)
+	}
+	for _, want := range []string{"app.pprofToken 已重命名为 app.adminToken", "配置文件版本较旧"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("迁移日志缺少 %q: %s", want, out.String())
+		}
+	}
+}
+
+func TestMigrateKeepsNewKeyWhenBothPresent(t *testing.T) {
+	out := captureLog(t)
+	path := writeFile(t, "conf.yaml", "app:\n  pprofToken: old\n  adminToken: new\n")
+
+	cfg, err := LoadConfigWithPath(path)
+	if err != nil {
+		t.Fatalf("LoadConfigWithPath: %v", err)
+	}
+	if cfg.App.AdminToken != "new" {
+		t.Errorf("AdminToken = %q, want new", cfg.App.AdminToken)
+	}
+	if !strings.Contains(out.String(), "忽略 app.pprofToken") {
+		t.Errorf("应提示忽略旧字段: %s", out.String())
+	}
+}
+
+func TestCurrentVersionConfigLoadsWithoutNotice(t *testing.T) {
+	out := captureLog(t)
+	path := writeFile(t, "conf.yaml", "version: 1\napp:\n  adminToken: token\n")
+
+	if _, err := LoadConfigWithPath(path); err != nil {
+		t.Fatalf("LoadConfigWithPath: %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("当前版本的配置不应输出迁移日志: %s", out.String())
+	}
+}
+
+func TestMigrateRejectsUnknownVersion(t *testing.T) {
+	for _, content := range []string{"version: 99\n", "version: -1\n", "version: v2\n"} {
+		if _, err := LoadConfigWithPath(writeFile(t, "conf.yaml", content)); err == nil {
+			t.Errorf("%q 应加载失败", strings.TrimSpace(content))
+		}
+	}
+}
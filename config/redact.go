@@ -0,0 +1,74 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// RedactedValue 敏感字段脱敏后的值
+const RedactedValue = "******"
+
+// Redacted 返回配置的脱敏副本（键为 yaml 字段名），用于调试接口输出
+// 带 sensitive:"true" 标签且非空的字段替换为 RedactedValue；yaml:"-" 的字段（如函数）不输出
+func Redacted(cfg *Config) interface{} {
+	if cfg == nil {
+		return nil
+	}
+	return redactValue(reflect.ValueOf(*cfg))
+}
+
+// redactValue 递归转换为可序列化的值，并对敏感字段脱敏
+func redactValue(v reflect.Value) interface{} {
+	// time.Duration 输出为可读格式（如 "2s"），与配置文件写法一致
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		return time.Duration(v.Int()).String()
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return redactValue(v.Elem())
+	case reflect.Struct:
+		out := make(map[string]interface{}, v.NumField())
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+			if name == "-" || !field.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			if field.Tag.Get("sensitive") == "true" && !v.Field(i).IsZero() {
+				out[name] = RedactedValue
+				continue
+			}
+			out[name] = redactValue(v.Field(i))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		out := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out[iter.Key().String()] = redactValue(iter.Value())
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		out := make([]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out[i] = redactValue(v.Index(i))
+		}
+		return out
+	case reflect.Func, reflect.Chan:
+		return nil
+	default:
+		return v.Interface()
+	}
+}
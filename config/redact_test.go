@@ -0,0 +1,47 @@
+package config
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRedactedMasksSensitiveFields(t *testing.T) {
+	cfg := &Config{
+		Database: Database{Mysql: Mysql{Host: "db", Password: "mysql-secret", QueryTimeout: 2 * time.Second}},
+		Redis: Redis{Instances: []RedisInstance{
+			{Name: "locks", Password: "redis-secret"},
+			{Name: "empty"},
+		}},
+	}
+
+	data, err := json.Marshal(Redacted(cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := string(data)
+	for _, secret := range []string{"mysql-secret", "redis-secret"} {
+		if strings.Contains(body, secret) {
+			t.Errorf("脱敏后仍包含 %s: %s", secret, body)
+		}
+	}
+
+	out := Redacted(cfg).(map[string]interface{})
+	mysql := out["database"].(map[string]interface{})["mysql"].(map[string]interface{})
+	if mysql["password"] != RedactedValue || mysql["host"] != "db" || mysql["queryTimeout"] != "2s" {
+		t.Errorf("database.mysql = %v", mysql)
+	}
+	instances := out["redis"].(map[string]interface{})["instances"].([]interface{})
+	if got := instances[0].(map[string]interface{})["password"]; got != RedactedValue {
+		t.Errorf("instances[0].password = %v", got)
+	}
+	// 未配置的敏感字段保持为空，便于确认是否已配置
+	if got := instances[1].(map[string]interface{})["password"]; got != "" {
+		t.Errorf("instances[1].password = %v, want empty", got)
+	}
+
+	if Redacted(nil) != nil {
+		t.Error("Redacted(nil) 应返回 nil")
+	}
+}
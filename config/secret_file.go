@@ -0,0 +1,64 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// secretFileSuffix 从文件读取敏感配置的字段名后缀
+const secretFileSuffix = "File"
+
+// resolveSecretFiles 从文件读取敏感配置（Docker/K8s secrets 以文件形式挂载）
+// 约定：带 sensitive:"true" 标签的字符串字段 X 所在结构体中存在字符串字段 XFile（如 Password 与 PasswordFile），
+// 且 XFile 非空时，读取该文件内容（去除末尾换行）覆盖 X；XFile 为空时使用配置文件中的 X
+func resolveSecretFiles(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return resolveSecretFiles(v.Elem())
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			if err := resolveSecretFiles(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if field.Type.Kind() != reflect.String || field.Tag.Get("sensitive") != "true" {
+				if err := resolveSecretFiles(v.Field(i)); err != nil {
+					return err
+				}
+				continue
+			}
+
+			fileField := v.FieldByName(field.Name + secretFileSuffix)
+			if !fileField.IsValid() || fileField.Kind() != reflect.String || fileField.String() == "" {
+				continue
+			}
+			secret, err := readSecretFile(fileField.String())
+			if err != nil {
+				return fmt.Errorf("读取 %s 失败: %w", field.Name+secretFileSuffix, err)
+			}
+			v.Field(i).SetString(secret)
+		}
+	}
+	return nil
+}
+
+// readSecretFile 读取密钥文件，去除末尾的换行符（echo 或编辑器写入的文件通常以换行结尾）
+func readSecretFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
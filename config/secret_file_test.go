@@ -0,0 +1,70 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFile 在测试临时目录中写入文件，返回文件路径
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSecretFileOverridesInlineValue(t *testing.T) {
+	mysqlSecret := writeFile(t, "mysql_password", "from-file\n")
+	redisSecret := writeFile(t, "redis_password", "locks-secret\r\n")
+	path := writeFile(t, "conf.yaml", `
+version: 1
+database:
+  mysql:
+    password: inline
+    passwordFile: `+mysqlSecret+`
+redis:
+  password: redis-inline
+  instances:
+    - name: locks
+      passwordFile: `+redisSecret+`
+`)
+
+	cfg, err := LoadConfigWithPath(path)
+	if err != nil {
+		t.Fatalf("LoadConfigWithPath: %v", err)
+	}
+	if got := cfg.Database.Mysql.Password; got != "from-file" {
+		t.Errorf("mysql password = %q, want from-file（文件覆盖配置值并去除末尾换行）", got)
+	}
+	if got := cfg.Redis.Instances[0].Password; got != "locks-secret" {
+		t.Errorf("redis instances[0] password = %q, want locks-secret", got)
+	}
+	if got := cfg.Redis.Password; got != "redis-inline" {
+		t.Errorf("未配置 passwordFile 时 redis password = %q, want redis-inline", got)
+	}
+}
+
+func TestSecretFileFallsBackToInlineValue(t *testing.T) {
+	path := writeFile(t, "conf.yaml", "version: 1\ndatabase:\n  mysql:\n    password: inline\n")
+
+	cfg, err := LoadConfigWithPath(path)
+	if err != nil {
+		t.Fatalf("LoadConfigWithPath: %v", err)
+	}
+	if got := cfg.Database.Mysql.Password; got != "inline" {
+		t.Errorf("mysql password = %q, want inline", got)
+	}
+}
+
+func TestSecretFileMissingFails(t *testing.T) {
+	path := writeFile(t, "conf.yaml", "version: 1\ndatabase:\n  mysql:\n    passwordFile: "+filepath.Join(t.TempDir(), "missing")+"\n")
+
+	_, err := LoadConfigWithPath(path)
+	if err == nil || !strings.Contains(err.Error(), "PasswordFile") {
+		t.Errorf("密钥文件不存在时应返回错误，got %v", err)
+	}
+}
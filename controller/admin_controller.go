@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"gin-project/pkg"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminController 运维管理控制器（需携带 app.adminToken 访问）
+type AdminController struct {
+	BaseController
+}
+
+// MaintenanceRequest 切换维护模式请求
+type MaintenanceRequest struct {
+	Enabled *bool `json:"enabled" binding:"required" example:"true"` // 是否开启维护模式
+}
+
+// MaintenanceResponse 维护模式状态
+type MaintenanceResponse struct {
+	Enabled bool `json:"enabled"` // 是否处于维护模式
+}
+
+// GetMaintenance 查询维护模式状态
+//
+//	@Summary	查询维护模式
+//	@Tags		运维
+//	@Produce	json
+//	@Success	200	{object}	APIResponse{data=MaintenanceResponse}	"成功"
+//	@Failure	401	{object}	APIResponse								"令牌无效"
+//	@Router		/admin/maintenance [get]
+func (ac *AdminController) GetMaintenance(c *gin.Context) {
+	ac.Success(c, MaintenanceResponse{Enabled: pkg.InMaintenance()})
+}
+
+// SetMaintenance 切换维护模式（开启后写接口返回 503，读接口和健康检查不受影响）
+//
+//	@Summary	切换维护模式
+//	@Tags		运维
+//	@Accept		json
+//	@Produce	json
+//	@Param		request	body		MaintenanceRequest						true	"维护模式开关"
+//	@Success	200		{object}	APIResponse{data=MaintenanceResponse}	"成功，返回切换后的状态"
+//	@Failure	400		{object}	APIResponse								"参数错误"
+//	@Failure	401		{object}	APIResponse								"令牌无效"
+//	@Router		/admin/maintenance [put]
+func (ac *AdminController) SetMaintenance(c *gin.Context) {
+	var req MaintenanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ac.BindError(c, err, &req)
+		return
+	}
+
+	pkg.SetMaintenance(*req.Enabled)
+	log.Printf("维护模式已切换: enabled=%t（来源: %s）", *req.Enabled, c.ClientIP())
+	ac.Success(c, MaintenanceResponse{Enabled: pkg.InMaintenance()})
+}
+
+// SampleRateRequest 调整追踪采样率请求
+type SampleRateRequest struct {
+	Rate *float64 `json:"rate" binding:"required,gte=0,lte=1" example:"1"` // 采样率：0.0-1.0，1 表示全部采样
+}
+
+// SampleRateResponse 追踪采样率
+type SampleRateResponse struct {
+	Rate float64 `json:"rate"` // 当前生效的采样率
+}
+
+// GetSampleRate 查询当前生效的追踪采样率
+//
+//	@Summary	查询追踪采样率
+//	@Tags		运维
+//	@Produce	json
+//	@Success	200	{object}	APIResponse{data=SampleRateResponse}	"成功"
+//	@Failure	401	{object}	APIResponse								"令牌无效"
+//	@Failure	409	{object}	APIResponse								"追踪未启用"
+//	@Router		/debug/tracing/sample-rate [get]
+func (ac *AdminController) GetSampleRate(c *gin.Context) {
+	rate, err := pkg.TraceSampleRate()
+	if err != nil {
+		ac.Error(c, http.StatusConflict, err.Error())
+		return
+	}
+	ac.Success(c, SampleRateResponse{Rate: rate})
+}
+
+// SetSampleRate 在运行时调整追踪采样率（无需重启，对新请求立即生效，重启后恢复 tracing.sampleRate）
+//
+//	@Summary	调整追踪采样率
+//	@Tags		运维
+//	@Accept		json
+//	@Produce	json
+//	@Param		request	body		SampleRateRequest						true	"采样率"
+//	@Success	200		{object}	APIResponse{data=SampleRateResponse}	"成功，返回调整后的采样率"
+//	@Failure	400		{object}	APIResponse								"参数错误"
+//	@Failure	401		{object}	APIResponse								"令牌无效"
+//	@Failure	409		{object}	APIResponse								"追踪未启用"
+//	@Router		/debug/tracing/sample-rate [post]
+func (ac *AdminController) SetSampleRate(c *gin.Context) {
+	var req SampleRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ac.BindError(c, err, &req)
+		return
+	}
+
+	if err := pkg.SetTraceSampleRate(*req.Rate); err != nil {
+		if errors.Is(err, pkg.ErrTracingDisabled) {
+			ac.Error(c, http.StatusConflict, err.Error())
+			return
+		}
+		ac.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	log.Printf("追踪采样率已调整: rate=%v（来源: %s）", *req.Rate, c.ClientIP())
+
+	rate, _ := pkg.TraceSampleRate()
+	ac.Success(c, SampleRateResponse{Rate: rate})
+}
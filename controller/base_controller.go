@@ -3,13 +3,33 @@ package controller
 import (
 	"net/http"
 
+	"gin-project/config"
+	"gin-project/pkg/errcode"
+
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
 	"go.opentelemetry.io/otel/trace"
 )
 
+// MIMEMsgPack MessagePack 响应类型（供高吞吐的内部客户端使用）
+const MIMEMsgPack = "application/msgpack"
+
+// MIMENDJSON 换行分隔的 JSON 流（用于数据导出）
+const MIMENDJSON = "application/x-ndjson"
+
+// offeredFormats 支持的响应格式（第一个为默认格式）
+var offeredFormats = []string{binding.MIMEJSON, MIMEMsgPack}
+
+// 响应码约定（app.responseCodeConvention）
+const (
+	CodeConventionHTTP = "http" // 成功为 200，错误为 HTTP 状态码或业务错误码（默认）
+	CodeConventionZero = "zero" // 成功为 0，错误码不变（非 0），与服务C的响应约定一致
+)
+
 // APIResponse 定义统一的API响应格式
 type APIResponse struct {
-	Code    int         `json:"code"`               // 状态码
+	Code    int         `json:"code"`               // 状态码（成功时为 200，app.responseCodeConvention 为 zero 时为 0）
 	Message string      `json:"message"`            // 消息提示
 	Data    interface{} `json:"data,omitempty"`     // 数据字段
 	TraceID string      `json:"trace_id,omitempty"` // 追踪ID（链路追踪，用于日志关联和问题排查）
@@ -31,36 +51,110 @@ func (bc *BaseController) getTraceID(c *gin.Context) string {
 	return ""
 }
 
+// respond 根据 Accept 头协商响应格式并输出统一响应
+// 支持 application/json（默认，未指定 Accept 或 */* 时使用）和 application/msgpack，其他类型返回 406；
+// 错误响应经 respondError 输出，Accept 包含 application/problem+json 时按 RFC 7807 格式返回
+func (bc *BaseController) respond(c *gin.Context, status int, resp APIResponse) {
+	if wantsProblem(c) {
+		// 要求 problem+json 的客户端，成功响应仍按 JSON 输出
+		c.JSON(status, resp)
+		return
+	}
+	switch c.NegotiateFormat(offeredFormats...) {
+	case binding.MIMEJSON:
+		c.JSON(status, resp)
+	case MIMEMsgPack:
+		c.Render(status, render.MsgPack{Data: resp})
+	default:
+		c.JSON(bc.httpStatus(http.StatusNotAcceptable), APIResponse{
+			Code:    http.StatusNotAcceptable,
+			Message: "不支持的响应格式，可选: application/json、application/msgpack",
+			TraceID: resp.TraceID,
+		})
+	}
+}
+
 // Success 成功响应
+// 支持 ?fields=id,name 稀疏字段集（仅返回 data 中的指定字段）；data 为空（含 nil 指针）时省略 data 字段
 func (bc *BaseController) Success(c *gin.Context, data interface{}) {
+	if isNilData(data) {
+		data = nil
+	} else {
+		data = projectData(c, data)
+	}
 	traceID := bc.getTraceID(c)
-	c.JSON(http.StatusOK, APIResponse{
-		Code:    200,
+	bc.respond(c, http.StatusOK, APIResponse{
+		Code:    successCode(),
 		Message: "success",
 		Data:    data,
 		TraceID: traceID,
 	})
 }
 
-// Error 错误响应
+// successCode 成功响应的 code（按 app.responseCodeConvention，默认 200）
+func successCode() int {
+	if config.Cfg != nil && config.Cfg.App.ResponseCodeConvention == CodeConventionZero {
+		return 0
+	}
+	return http.StatusOK
+}
+
+// httpStatus 根据错误码确定 HTTP 状态码
+// 错误码即 HTTP 状态码（400/500/503 等），非法值按 500 处理；
+// 开启 app.legacyErrorStatus 时保持旧行为，错误也返回 HTTP 200（迁移期兼容）
+func (bc *BaseController) httpStatus(code int) int {
+	if config.Cfg != nil && config.Cfg.App.LegacyErrorStatus {
+		return http.StatusOK
+	}
+	if code < 400 || code > 599 {
+		return http.StatusInternalServerError
+	}
+	return code
+}
+
+// Error 错误响应（HTTP 状态码与错误码一致，响应体仍使用统一格式）
 func (bc *BaseController) Error(c *gin.Context, code int, message string) {
+	bc.ErrorWithData(c, code, message, nil)
+}
+
+// ErrorWithData 带附加数据的错误响应（如调试模式下的 panic 详情），data 为 nil 时等同于 Error
+func (bc *BaseController) ErrorWithData(c *gin.Context, code int, message string, data interface{}) {
 	traceID := bc.getTraceID(c)
-	c.JSON(http.StatusOK, APIResponse{
+	bc.respondError(c, bc.httpStatus(code), APIResponse{
 		Code:    code,
 		Message: message,
+		Data:    data,
+		TraceID: traceID,
+	}, nil)
+}
+
+// HandleError 逻辑层错误响应
+// 业务错误（*errcode.Error）按错误码注册表渲染 HTTP 状态码，响应体 code 为业务错误码；
+// 其他错误按 HTTP 400 返回 prefix + 错误信息
+func (bc *BaseController) HandleError(c *gin.Context, err error, prefix string) {
+	e, ok := errcode.FromError(err)
+	if !ok {
+		bc.ErrorWithMsg(c, prefix+err.Error())
+		return
+	}
+
+	traceID := bc.getTraceID(c)
+	bc.respondError(c, bc.httpStatus(e.Code.HTTPStatus()), APIResponse{
+		Code:    int(e.Code),
+		Message: e.Message,
 		Data:    nil,
 		TraceID: traceID,
-	})
+	}, nil)
 }
 
-// ErrorWithMsg 错误响应（带自定义消息）
+// ErrorWithMsg 错误响应（带自定义消息，HTTP 400）
 func (bc *BaseController) ErrorWithMsg(c *gin.Context, message string) {
 	traceID := bc.getTraceID(c)
-	c.JSON(http.StatusOK, APIResponse{
-		Code:    400,
+	bc.respondError(c, bc.httpStatus(http.StatusBadRequest), APIResponse{
+		Code:    http.StatusBadRequest,
 		Message: message,
 		Data:    nil,
 		TraceID: traceID,
-	})
+	}, nil)
 }
 
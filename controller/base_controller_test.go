@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"gin-project/config"
+	"gin-project/pkg/errcode"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestErrorSendsRealHTTPStatus(t *testing.T) {
+	useConfig(t, &config.Config{})
+	bc := &BaseController{}
+
+	tests := []struct {
+		name       string
+		respond    func(bc *BaseController, c *gin.Context)
+		wantStatus int
+		wantCode   int
+	}{
+		{"参数错误", func(bc *BaseController, c *gin.Context) { bc.ErrorWithMsg(c, "bad") }, http.StatusBadRequest, http.StatusBadRequest},
+		{"服务端错误", func(bc *BaseController, c *gin.Context) { bc.Error(c, http.StatusInternalServerError, "oops") }, http.StatusInternalServerError, http.StatusInternalServerError},
+		{"依赖不可用", func(bc *BaseController, c *gin.Context) { bc.Error(c, http.StatusServiceUnavailable, "down") }, http.StatusServiceUnavailable, http.StatusServiceUnavailable},
+		{"非法错误码按 500", func(bc *BaseController, c *gin.Context) { bc.Error(c, 42, "odd") }, http.StatusInternalServerError, 42},
+		{"业务错误按注册表", func(bc *BaseController, c *gin.Context) {
+			bc.HandleError(c, errcode.New(errcode.UserNotFound, ""), "")
+		}, http.StatusNotFound, int(errcode.UserNotFound)},
+		{"普通错误按 400", func(bc *BaseController, c *gin.Context) { bc.HandleError(c, errors.New("boom"), "失败: ") }, http.StatusBadRequest, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		c, w := newContext(http.MethodGet, "/")
+		tt.respond(bc, c)
+		if w.Code != tt.wantStatus {
+			t.Errorf("%s: status = %d, want %d", tt.name, w.Code, tt.wantStatus)
+		}
+		if resp := decodeResponse(t, w); resp.Code != tt.wantCode {
+			t.Errorf("%s: code = %d, want %d", tt.name, resp.Code, tt.wantCode)
+		}
+	}
+}
+
+func TestLegacyErrorStatus(t *testing.T) {
+	useConfig(t, &config.Config{App: config.App{LegacyErrorStatus: true}})
+	c, w := newContext(http.MethodGet, "/")
+	(&BaseController{}).Error(c, http.StatusInternalServerError, "oops")
+
+	if w.Code != http.StatusOK {
+		t.Errorf("兼容模式下 status = %d, want 200", w.Code)
+	}
+	if resp := decodeResponse(t, w); resp.Code != http.StatusInternalServerError {
+		t.Errorf("兼容模式下 code = %d, want 500", resp.Code)
+	}
+}
+
+func TestResponseCodeConvention(t *testing.T) {
+	tests := []struct {
+		convention  string
+		wantSuccess int
+	}{
+		{"", http.StatusOK},
+		{CodeConventionHTTP, http.StatusOK},
+		{CodeConventionZero, 0},
+	}
+	for _, tt := range tests {
+		useConfig(t, &config.Config{App: config.App{ResponseCodeConvention: tt.convention}})
+		bc := &BaseController{}
+
+		c, w := newContext(http.MethodGet, "/")
+		bc.Success(c, gin.H{"ok": true})
+		if resp := decodeResponse(t, w); w.Code != http.StatusOK || resp.Code != tt.wantSuccess {
+			t.Errorf("convention=%q: 成功响应 status = %d, code = %d, want 200/%d", tt.convention, w.Code, resp.Code, tt.wantSuccess)
+		}
+
+		// 错误码不受约定影响
+		c, w = newContext(http.MethodGet, "/")
+		bc.ErrorWithMsg(c, "bad")
+		if resp := decodeResponse(t, w); resp.Code != http.StatusBadRequest {
+			t.Errorf("convention=%q: 错误响应 code = %d, want 400", tt.convention, resp.Code)
+		}
+	}
+}
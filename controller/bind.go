@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"encoding/json"
+	"io"
+
+	"gin-project/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// BindJSON 绑定 JSON 请求体并校验，绑定前按 app.jsonFieldAliases 将别名字段映射为规范字段名
+// 用于客户端迁移期兼容旧字段（如 user_name -> name）；请求中同时提供规范字段时以规范字段为准
+func (bc *BaseController) BindJSON(c *gin.Context, obj interface{}) error {
+	aliases := jsonFieldAliases()
+	if len(aliases) == 0 {
+		return c.ShouldBindJSON(obj)
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return err
+	}
+	return binding.JSON.BindBody(applyFieldAliases(body, aliases), obj)
+}
+
+// jsonFieldAliases 获取请求字段别名配置（别名 -> 规范字段名）
+func jsonFieldAliases() map[string]string {
+	if config.Cfg == nil {
+		return nil
+	}
+	return config.Cfg.App.JSONFieldAliases
+}
+
+// applyFieldAliases 重命名请求体顶层对象中的别名字段
+// 请求体不是 JSON 对象或没有别名字段时原样返回，由绑定过程报告格式错误
+func applyFieldAliases(body []byte, aliases map[string]string) []byte {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return body
+	}
+
+	renamed := false
+	for alias, canonical := range aliases {
+		value, ok := fields[alias]
+		if !ok {
+			continue
+		}
+		delete(fields, alias)
+		if _, exists := fields[canonical]; !exists {
+			fields[canonical] = value
+		}
+		renamed = true
+	}
+	if !renamed {
+		return body
+	}
+
+	rewritten, err := json.Marshal(fields)
+	if err != nil {
+		return body
+	}
+	return rewritten
+}
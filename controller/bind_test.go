@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gin-project/config"
+)
+
+// bindCreateUser 使用 BindJSON 绑定 body 到 CreateUserRequest
+func bindCreateUser(body string) (CreateUserRequest, error) {
+	c, _ := newContext(http.MethodPost, "/api/user")
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/user", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	var req CreateUserRequest
+	err := (&BaseController{}).BindJSON(c, &req)
+	return req, err
+}
+
+func TestBindJSONAppliesFieldAliases(t *testing.T) {
+	useConfig(t, &config.Config{App: config.App{JSONFieldAliases: map[string]string{
+		"user_name":  "name",
+		"user_email": "email",
+	}}})
+
+	req, err := bindCreateUser(`{"user_name":"张三","user_email":"zhangsan@example.com","age":25}`)
+	if err != nil {
+		t.Fatalf("别名字段绑定失败: %v", err)
+	}
+	if req.Name != "张三" || req.Email != "zhangsan@example.com" || req.Age != 25 {
+		t.Errorf("绑定结果 = %+v", req)
+	}
+
+	// 规范字段仍可使用，且同时提供时以规范字段为准
+	req, err = bindCreateUser(`{"name":"李四","user_name":"旧名","email":"lisi@example.com"}`)
+	if err != nil {
+		t.Fatalf("规范字段绑定失败: %v", err)
+	}
+	if req.Name != "李四" {
+		t.Errorf("Name = %q, want 李四", req.Name)
+	}
+}
+
+func TestBindJSONWithoutAliases(t *testing.T) {
+	useConfig(t, &config.Config{})
+
+	if _, err := bindCreateUser(`{"user_name":"张三","email":"zhangsan@example.com"}`); err == nil {
+		t.Error("未配置别名时 user_name 不应满足 name 的必填校验")
+	}
+	if _, err := bindCreateUser(`{"name":"张三","email":"zhangsan@example.com"}`); err != nil {
+		t.Errorf("规范字段绑定失败: %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+package controller
+
+import (
+	"gin-project/config"
+	"gin-project/pkg"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DebugController 调试控制器（仅在 debug 模式下注册）
+type DebugController struct {
+	BaseController
+}
+
+// Config 返回当前进程实际加载的配置（敏感字段已脱敏）
+func (dc *DebugController) Config(c *gin.Context) {
+	dc.Success(c, config.Redacted(config.Cfg))
+}
+
+// FailedRequests 返回最近失败的请求（最新的在前，请求体已截断和脱敏），未启用 log.requestCapture 时返回空列表
+func (dc *DebugController) FailedRequests(c *gin.Context) {
+	requests := []pkg.FailedRequest{}
+	if recorder := pkg.FailedRequests(); recorder != nil {
+		requests = recorder.Recent()
+	}
+	dc.Success(c, gin.H{
+		"requests": requests,
+	})
+}
+
+// Spans 返回当前未结束的 span 数（HTTP 请求和服务层 span），持续增长说明存在 span 泄漏
+func (dc *DebugController) Spans(c *gin.Context) {
+	dc.Success(c, gin.H{
+		"active_spans": pkg.ActiveSpans(),
+	})
+}
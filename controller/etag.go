@@ -0,0 +1,41 @@
+package controller
+
+import "strings"
+
+// ifNoneMatch 判断 If-None-Match 请求头是否与 etag 匹配（RFC 9110 13.1.2）
+// 支持 "*" 和逗号分隔的多个 ETag，使用弱比较（忽略 W/ 前缀）；格式错误的部分及其后的内容视为不匹配
+func ifNoneMatch(header string, etag string) bool {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return false
+	}
+	if header == "*" {
+		return true
+	}
+
+	want := opaqueTag(etag)
+	for header != "" {
+		header = strings.TrimLeft(header, " \t,")
+		if header == "" {
+			break
+		}
+		header = strings.TrimPrefix(header, "W/")
+		if !strings.HasPrefix(header, `"`) {
+			return false
+		}
+		end := strings.IndexByte(header[1:], '"')
+		if end < 0 {
+			return false
+		}
+		if header[:end+2] == want {
+			return true
+		}
+		header = header[end+2:]
+	}
+	return false
+}
+
+// opaqueTag 去掉 ETag 的弱校验前缀 W/，返回带引号的 opaque-tag，用于弱比较
+func opaqueTag(etag string) string {
+	return strings.TrimPrefix(etag, "W/")
+}
@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"testing"
+
+	"gin-project/model"
+)
+
+func TestIfNoneMatch(t *testing.T) {
+	const etag = `"abc"`
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{``, false},
+		{`"abc"`, true},
+		{`W/"abc"`, true},
+		{`*`, true},
+		{` * `, true},
+		{`"xyz", "abc"`, true},
+		{`"xyz",W/"abc"`, true},
+		{`"a,b", "abc"`, true},
+		{`"xyz"`, false},
+		{`abc`, false},
+		{`"xyz", abc`, false},
+		{`"abc`, false},
+		{`"ab"`, false},
+	}
+	for _, tt := range tests {
+		if got := ifNoneMatch(tt.header, etag); got != tt.want {
+			t.Errorf("ifNoneMatch(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+
+	if !ifNoneMatch(`"abc"`, `W/"abc"`) {
+		t.Error("弱 ETag 应按弱比较匹配")
+	}
+}
+
+func TestUserETagIncludesNormalizedFields(t *testing.T) {
+	user := &model.User{ID: 1, Name: "alice"}
+
+	full := userETag(user, "")
+	picked := userETag(user, "id,name")
+	if full == picked {
+		t.Error("不同的字段集应产生不同的 ETag")
+	}
+	if got := userETag(user, " name ,id,,name"); got != picked {
+		t.Errorf("规范化后相同的字段集应产生相同的 ETag: %s != %s", got, picked)
+	}
+
+	user.Name = "bob"
+	if userETag(user, "id,name") == picked {
+		t.Error("用户数据变化后 ETag 应变化")
+	}
+}
@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gin-project/config"
+	"gin-project/database/dbtest"
+	"gin-project/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestExportUsersStreamsNDJSON(t *testing.T) {
+	useConfig(t, &config.Config{})
+	db := dbtest.Open(t, &model.User{})
+	dbtest.Redis(t)
+
+	// 超过一次刷新的行数，验证分批写出
+	const total = exportFlushRows*2 + 5
+	users := make([]model.User, total)
+	for i := range users {
+		users[i] = model.User{Name: fmt.Sprintf("user%d", i), Email: fmt.Sprintf("user%d@example.com", i)}
+	}
+	if err := db.CreateInBatches(users, 50).Error; err != nil {
+		t.Fatalf("创建用户失败: %v", err)
+	}
+
+	engine := gin.New()
+	engine.GET("/api/user/export", NewUserController(nil).ExportUsers)
+	// c.Stream 需要支持 CloseNotify 的真实连接
+	server := httptest.NewServer(engine)
+	t.Cleanup(server.Close)
+
+	resp, err := http.Get(server.URL + "/api/user/export")
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != MIMENDJSON {
+		t.Fatalf("status = %d, Content-Type = %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	count := 0
+	for scanner.Scan() {
+		var user model.User
+		if err := json.Unmarshal(scanner.Bytes(), &user); err != nil {
+			t.Fatalf("第 %d 行不是合法 JSON: %v, line = %q", count+1, err, scanner.Text())
+		}
+		if user.Email != users[count].Email {
+			t.Errorf("第 %d 行 email = %q, want %q", count+1, user.Email, users[count].Email)
+		}
+		count++
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if count != total {
+		t.Errorf("导出 %d 行, want %d", count, total)
+	}
+}
@@ -1,25 +1,49 @@
 package controller
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"gin-project/config"
 	"gin-project/database"
+	"gin-project/pkg"
+	"gin-project/pkg/breaker"
 
 	"github.com/gin-gonic/gin"
 )
 
+// defaultDependencyTimeout 下游依赖检查默认超时
+const defaultDependencyTimeout = time.Second
+
 // HealthController 健康检查控制器
 type HealthController struct {
 	BaseController
 }
 
 // Health 健康检查接口
+//
+//	@Summary	健康检查
+//	@Tags		健康检查
+//	@Produce	json
+//	@Success	200	{object}	APIResponse	"服务正常"
+//	@Router		/health [get]
 func (hc *HealthController) Health(c *gin.Context) {
 	hc.Success(c, gin.H{
-		"status": "ok",
+		"status":  "ok",
 		"service": "gin-project",
 	})
 }
 
 // Readiness 就绪检查接口
+//
+//	@Summary	就绪检查（MySQL、Redis、下游依赖）
+//	@Tags		健康检查
+//	@Produce	json
+//	@Success	200	{object}	APIResponse	"服务就绪"
+//	@Failure	503	{object}	APIResponse	"依赖不可用"
+//	@Router		/readiness [get]
 func (hc *HealthController) Readiness(c *gin.Context) {
 	// 检查数据库连接
 	if database.DB == nil {
@@ -40,29 +64,188 @@ func (hc *HealthController) Readiness(c *gin.Context) {
 		return
 	}
 
-	if err := sqlDB.Ping(); err != nil {
+	// 探活请求频率高，跳过 MySQL/Redis 追踪减少 span 开销
+	ctx := c.Request.Context()
+	pingCtx := database.SkipTracing(ctx)
+	if err := sqlDB.PingContext(pingCtx); err != nil {
 		hc.Error(c, 503, "数据库连接失败: "+err.Error())
 		return
 	}
 
 	// 测试 Redis 连接
-	ctx := c.Request.Context()
-	if err := database.RedisClient.Ping(ctx).Err(); err != nil {
+	if err := database.RedisClient.Ping(pingCtx).Err(); err != nil {
 		hc.Error(c, 503, "Redis连接失败: "+err.Error())
 		return
 	}
 
+	// 检查下游依赖：硬依赖不可用时就绪检查失败，软依赖不可用时标记为 degraded
+	status := "ready"
+	dependencies := gin.H{}
+	for _, dep := range dependencyChecks() {
+		if err := checkDependency(ctx, dep); err != nil {
+			if dep.Required {
+				hc.Error(c, 503, dep.Name+"不可用: "+err.Error())
+				return
+			}
+			status = "degraded"
+			dependencies[dep.Name] = "unavailable: " + err.Error()
+			continue
+		}
+		dependencies[dep.Name] = "ok"
+	}
+
+	// 下游熔断器打开时标记为 degraded（熔断不影响本服务就绪）
+	breakers, open := breakerStates()
+	if open {
+		status = "degraded"
+	}
+
+	hc.Success(c, gin.H{
+		"status":       status,
+		"database":     "ok",
+		"redis":        "ok",
+		"dependencies": dependencies,
+		"breakers":     breakers,
+	})
+}
+
+// Detailed 详细健康状态接口，汇总 MySQL、Redis、下游依赖和熔断器状态
+// 供运维排查部分故障使用，始终返回 200，整体状态见 status（ok、degraded、down）
+//
+//	@Summary	详细健康状态（MySQL、Redis、下游依赖、熔断器）
+//	@Tags		健康检查
+//	@Produce	json
+//	@Success	200	{object}	APIResponse	"各组件状态"
+//	@Router		/health/detailed [get]
+func (hc *HealthController) Detailed(c *gin.Context) {
+	status := "ok"
+	ctx := database.SkipTracing(c.Request.Context())
+
+	// MySQL、Redis 不可用时整体状态为 down
+	components := gin.H{}
+	if err := pingMysql(ctx); err != nil {
+		status = "down"
+		components["database"] = "unavailable: " + err.Error()
+	} else {
+		components["database"] = "ok"
+	}
+	if err := pingRedis(ctx); err != nil {
+		status = "down"
+		components["redis"] = "unavailable: " + err.Error()
+	} else {
+		components["redis"] = "ok"
+	}
+	// 后台探测状态（不可用期间读请求跳过缓存）
+	components["redis_monitor"] = database.RedisHealth()
+
+	// 下游依赖不可用或熔断器打开时标记为 degraded
+	dependencies := gin.H{}
+	for _, dep := range dependencyChecks() {
+		if err := checkDependency(c.Request.Context(), dep); err != nil {
+			if status == "ok" {
+				status = "degraded"
+			}
+			dependencies[dep.Name] = "unavailable: " + err.Error()
+			continue
+		}
+		dependencies[dep.Name] = "ok"
+	}
+
+	breakers, open := breakerStates()
+	if open && status == "ok" {
+		status = "degraded"
+	}
+
 	hc.Success(c, gin.H{
-		"status": "ready",
-		"database": "ok",
-		"redis": "ok",
+		"status":       status,
+		"components":   components,
+		"dependencies": dependencies,
+		"breakers":     breakers,
 	})
 }
 
+// pingMysql 检测 MySQL 连接
+func pingMysql(ctx context.Context) error {
+	if database.DB == nil {
+		return fmt.Errorf("数据库未初始化")
+	}
+	sqlDB, err := database.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// pingRedis 检测 Redis 连接
+func pingRedis(ctx context.Context) error {
+	if database.RedisClient == nil {
+		return fmt.Errorf("Redis未初始化")
+	}
+	return database.RedisClient.Ping(ctx).Err()
+}
+
+// breakerStates 汇总熔断器状态（名称 -> closed/open/half_open），并返回是否有熔断器处于打开状态
+func breakerStates() (gin.H, bool) {
+	states := gin.H{}
+	open := false
+	for name, state := range breaker.Default().States() {
+		states[name] = state.String()
+		if state != breaker.StateClosed {
+			open = true
+		}
+	}
+	return states, open
+}
+
+// dependencyChecks 获取配置的下游依赖检查
+func dependencyChecks() []config.HealthDependency {
+	if config.Cfg == nil {
+		return nil
+	}
+	return config.Cfg.Health.Dependencies
+}
+
+// checkDependency 对下游依赖发起轻量 GET 请求，2xx 视为健康
+// 使用较短的超时且不重试，避免拖慢就绪检查
+func checkDependency(ctx context.Context, dep config.HealthDependency) error {
+	timeout := dep.Timeout
+	if timeout <= 0 {
+		timeout = defaultDependencyTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	resp, err := pkg.HTTPClient().R().
+		SetContext(ctx).
+		SetRetryCount(0).
+		Get(dep.URL)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("HTTP 状态码 %d", resp.StatusCode)
+	}
+	return nil
+}
+
 // Liveness 存活检查接口
+//
+//	@Summary	存活检查
+//	@Tags		健康检查
+//	@Produce	json
+//	@Success	200	{object}	APIResponse	"服务存活"
+//	@Failure	503	{object}	APIResponse	"心跳超时（进程可能被阻塞）"
+//	@Router		/liveness [get]
 func (hc *HealthController) Liveness(c *gin.Context) {
+	// 启用看门狗时检查心跳，超时说明进程被阻塞（如死锁），返回 503 触发重启
+	if watchdog := pkg.LivenessWatchdog(); watchdog != nil {
+		if healthy, since := watchdog.Healthy(); !healthy {
+			hc.Error(c, 503, fmt.Sprintf("unhealthy: 心跳已 %s 未更新", since.Round(time.Millisecond)))
+			return
+		}
+	}
+
 	hc.Success(c, gin.H{
 		"status": "alive",
 	})
 }
-
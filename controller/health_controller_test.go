@@ -0,0 +1,139 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gin-project/config"
+	"gin-project/database/dbtest"
+	"gin-project/pkg"
+	"gin-project/pkg/breaker"
+)
+
+// stubDependency 启动返回固定状态码的下游健康检查服务
+func stubDependency(t *testing.T, status int) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL + "/health"
+}
+
+func TestReadinessChecksServiceC(t *testing.T) {
+	dbtest.Open(t)
+	dbtest.Redis(t)
+
+	tests := []struct {
+		name       string
+		status     int
+		required   bool
+		wantStatus int
+		wantState  string
+	}{
+		{"健康", http.StatusOK, false, http.StatusOK, "ready"},
+		{"软依赖不可用", http.StatusServiceUnavailable, false, http.StatusOK, "degraded"},
+		{"硬依赖不可用", http.StatusServiceUnavailable, true, http.StatusServiceUnavailable, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useConfig(t, &config.Config{Health: config.Health{Dependencies: []config.HealthDependency{
+				{Name: "ServiceC", URL: stubDependency(t, tt.status), Required: tt.required},
+			}}})
+
+			c, w := newContext(http.MethodGet, "/readiness")
+			(&HealthController{}).Readiness(c)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			resp := decodeResponse(t, w)
+			if tt.wantState == "" {
+				if !strings.Contains(resp.Message, "ServiceC不可用") {
+					t.Errorf("message = %q", resp.Message)
+				}
+				return
+			}
+			data := resp.Data.(map[string]interface{})
+			if data["status"] != tt.wantState {
+				t.Errorf("status = %v, want %s", data["status"], tt.wantState)
+			}
+			dependency := data["dependencies"].(map[string]interface{})["ServiceC"].(string)
+			if (tt.wantState == "ready") != (dependency == "ok") {
+				t.Errorf("ServiceC = %q", dependency)
+			}
+		})
+	}
+}
+
+func TestDetailedReflectsOpenBreaker(t *testing.T) {
+	useConfig(t, &config.Config{})
+	dbtest.Open(t)
+	dbtest.Redis(t)
+	b := breaker.Default().Get("servicec-health-test", 1, time.Minute)
+	t.Cleanup(func() { b.Record(nil) })
+
+	detailed := func() map[string]interface{} {
+		c, w := newContext(http.MethodGet, "/health/detailed")
+		(&HealthController{}).Detailed(c)
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200", w.Code)
+		}
+		return decodeResponse(t, w).Data.(map[string]interface{})
+	}
+
+	data := detailed()
+	if data["status"] != "ok" {
+		t.Fatalf("熔断器关闭时 status = %v, want ok", data["status"])
+	}
+
+	b.Record(errors.New("downstream failure"))
+	data = detailed()
+	if data["status"] != "degraded" {
+		t.Errorf("熔断器打开时 status = %v, want degraded", data["status"])
+	}
+	if state := data["breakers"].(map[string]interface{})["servicec-health-test"]; state != breaker.StateOpen.String() {
+		t.Errorf("breakers[servicec-health-test] = %v, want %s", state, breaker.StateOpen)
+	}
+
+	// 熔断不影响就绪，仅标记为 degraded
+	c, w := newContext(http.MethodGet, "/readiness")
+	(&HealthController{}).Readiness(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("readiness status = %d, want 200", w.Code)
+	}
+	if got := decodeResponse(t, w).Data.(map[string]interface{})["status"]; got != "degraded" {
+		t.Errorf("readiness status = %v, want degraded", got)
+	}
+}
+
+func TestLivenessFlipsWhenHeartbeatStops(t *testing.T) {
+	watchdog := pkg.NewWatchdog(5*time.Millisecond, 30*time.Millisecond)
+	watchdog.Start()
+	prev := pkg.UseLivenessWatchdog(watchdog)
+	t.Cleanup(func() {
+		watchdog.Stop()
+		pkg.UseLivenessWatchdog(prev)
+	})
+
+	hc := &HealthController{}
+	c, w := newContext(http.MethodGet, "/liveness")
+	hc.Liveness(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("心跳正常时 Liveness = %d, want 200", w.Code)
+	}
+
+	watchdog.Stop()
+	time.Sleep(60 * time.Millisecond)
+	c, w = newContext(http.MethodGet, "/liveness")
+	hc.Liveness(c)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("心跳停止后 Liveness = %d, want 503", w.Code)
+	}
+	if resp := decodeResponse(t, w); !strings.HasPrefix(resp.Message, "unhealthy") {
+		t.Errorf("Message = %q, want unhealthy 前缀", resp.Message)
+	}
+}
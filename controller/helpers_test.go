@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gin-project/config"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// useConfig 将全局配置替换为 cfg，测试结束时恢复
+func useConfig(t *testing.T, cfg *config.Config) {
+	t.Helper()
+	previous := config.Cfg
+	config.Cfg = cfg
+	t.Cleanup(func() { config.Cfg = previous })
+}
+
+// newContext 创建处理 method target 请求的测试 Context
+func newContext(method, target string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, target, nil)
+	return c, w
+}
+
+// decodeResponse 解析统一格式的 JSON 响应
+func decodeResponse(t *testing.T, w *httptest.ResponseRecorder) APIResponse {
+	t.Helper()
+	var resp APIResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v, body = %s", err, w.Body.String())
+	}
+	return resp
+}
+
+// waitForCache 等待后台缓存写入完成（miniredis 中出现任意键）
+func waitForCache(t *testing.T, mr *miniredis.Miniredis) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for len(mr.Keys()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("等待后台缓存写入超时")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
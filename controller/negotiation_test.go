@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"net/http"
+	"testing"
+
+	"gin-project/config"
+
+	"github.com/ugorji/go/codec"
+)
+
+func TestSuccessNegotiatesFormat(t *testing.T) {
+	useConfig(t, &config.Config{})
+	tests := []struct {
+		accept      string
+		contentType string
+	}{
+		{"", "application/json; charset=utf-8"},
+		{"*/*", "application/json; charset=utf-8"},
+		{"application/json", "application/json; charset=utf-8"},
+		{MIMEMsgPack, MIMEMsgPack + "; charset=utf-8"},
+		{"text/html, application/msgpack;q=0.9", MIMEMsgPack + "; charset=utf-8"},
+	}
+	for _, tt := range tests {
+		c, w := newContext(http.MethodGet, "/")
+		if tt.accept != "" {
+			c.Request.Header.Set("Accept", tt.accept)
+		}
+		(&BaseController{}).Success(c, map[string]string{"name": "alice"})
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Accept %q: status = %d, want 200", tt.accept, w.Code)
+		}
+		if got := w.Header().Get("Content-Type"); got != tt.contentType {
+			t.Errorf("Accept %q: Content-Type = %q, want %q", tt.accept, got, tt.contentType)
+		}
+	}
+}
+
+func TestSuccessEncodesMsgPackEnvelope(t *testing.T) {
+	useConfig(t, &config.Config{})
+	c, w := newContext(http.MethodGet, "/")
+	c.Request.Header.Set("Accept", MIMEMsgPack)
+	(&BaseController{}).Success(c, map[string]string{"name": "alice"})
+
+	var resp struct {
+		Code    int               `codec:"code"`
+		Message string            `codec:"message"`
+		Data    map[string]string `codec:"data"`
+	}
+	var mh codec.MsgpackHandle
+	if err := codec.NewDecoderBytes(w.Body.Bytes(), &mh).Decode(&resp); err != nil {
+		t.Fatalf("解析 msgpack 响应失败: %v", err)
+	}
+	if resp.Message != "success" || resp.Data["name"] != "alice" {
+		t.Errorf("msgpack 响应 = %+v", resp)
+	}
+}
+
+func TestUnsupportedAcceptReturns406(t *testing.T) {
+	useConfig(t, &config.Config{})
+	c, w := newContext(http.MethodGet, "/")
+	c.Request.Header.Set("Accept", "text/xml")
+	(&BaseController{}).Success(c, "ok")
+
+	if w.Code != http.StatusNotAcceptable {
+		t.Fatalf("status = %d, want 406", w.Code)
+	}
+	if resp := decodeResponse(t, w); resp.Code != http.StatusNotAcceptable {
+		t.Errorf("code = %d, want 406", resp.Code)
+	}
+}
@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// MIMEProblemJSON RFC 7807 问题详情响应类型
+const MIMEProblemJSON = "application/problem+json"
+
+// ProblemDetails RFC 7807 问题详情（Accept: application/problem+json 时的错误响应格式）
+// 除标准字段外扩展 code（业务错误码）、trace_id 和 errors（字段级校验错误）
+type ProblemDetails struct {
+	Type     string       `json:"type"`               // 问题类型 URI，未细分时为 about:blank
+	Title    string       `json:"title"`              // HTTP 状态码对应的简短描述
+	Status   int          `json:"status"`             // HTTP 状态码
+	Detail   string       `json:"detail,omitempty"`   // 错误详情
+	Instance string       `json:"instance,omitempty"` // 出错的请求路径
+	Code     int          `json:"code"`               // 业务错误码（与统一响应格式的 code 一致）
+	TraceID  string       `json:"trace_id,omitempty"` // 追踪ID
+	Errors   []FieldError `json:"errors,omitempty"`   // 字段级校验错误
+}
+
+// FieldError 字段级校验错误
+type FieldError struct {
+	Field   string `json:"field"`           // 请求字段名（JSON/查询参数名）
+	Rule    string `json:"rule"`            // 未通过的校验规则（如 required、email、max）
+	Param   string `json:"param,omitempty"` // 校验规则参数（如 max=50 中的 50）
+	Message string `json:"message"`         // 错误描述
+}
+
+// wantsProblem 客户端是否通过 Accept 头显式要求 problem+json
+// 未指定或 */* 时仍使用统一响应格式
+func wantsProblem(c *gin.Context) bool {
+	return strings.Contains(c.GetHeader("Accept"), MIMEProblemJSON)
+}
+
+// respondError 输出错误响应：客户端要求 problem+json 时按 RFC 7807 输出，否则使用统一响应格式
+func (bc *BaseController) respondError(c *gin.Context, status int, resp APIResponse, fieldErrs []FieldError) {
+	if !wantsProblem(c) {
+		bc.respond(c, status, resp)
+		return
+	}
+
+	problemStatus := status
+	if problemStatus < 400 {
+		// app.legacyErrorStatus 下错误返回 HTTP 200，问题详情中仍给出错误码对应的状态
+		problemStatus = bc.problemStatus(resp.Code)
+	}
+	c.Header("Content-Type", MIMEProblemJSON)
+	c.JSON(status, ProblemDetails{
+		Type:     "about:blank",
+		Title:    http.StatusText(problemStatus),
+		Status:   problemStatus,
+		Detail:   resp.Message,
+		Instance: c.Request.URL.Path,
+		Code:     resp.Code,
+		TraceID:  resp.TraceID,
+		Errors:   fieldErrs,
+	})
+}
+
+// problemStatus 错误码对应的 HTTP 状态码（忽略 app.legacyErrorStatus）
+func (bc *BaseController) problemStatus(code int) int {
+	if code < 400 || code > 599 {
+		return http.StatusInternalServerError
+	}
+	return code
+}
+
+// BindError 请求参数绑定/校验失败响应（HTTP 400）
+// obj 为绑定目标，用于将校验错误中的结构体字段名还原为请求字段名；
+// problem+json 模式下字段级校验错误放在 errors 数组中
+func (bc *BaseController) BindError(c *gin.Context, err error, obj interface{}) {
+	bc.respondError(c, bc.httpStatus(http.StatusBadRequest), APIResponse{
+		Code:    http.StatusBadRequest,
+		Message: "参数错误: " + err.Error(),
+		TraceID: bc.getTraceID(c),
+	}, fieldErrors(err, obj))
+}
+
+// fieldErrors 将 validator 校验错误转换为字段级错误列表，非校验错误（如 JSON 格式错误）返回 nil
+func fieldErrors(err error, obj interface{}) []FieldError {
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return nil
+	}
+
+	result := make([]FieldError, 0, len(errs))
+	for _, fe := range errs {
+		result = append(result, FieldError{
+			Field:   requestFieldName(obj, fe),
+			Rule:    fe.Tag(),
+			Param:   fe.Param(),
+			Message: fe.Error(),
+		})
+	}
+	return result
+}
+
+// requestFieldName 按 json/form 标签获取字段在请求中的名称，无标签时使用结构体字段名
+func requestFieldName(obj interface{}, fe validator.FieldError) string {
+	t := reflect.TypeOf(obj)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return fe.Field()
+	}
+
+	field, ok := t.FieldByName(fe.StructField())
+	if !ok {
+		return fe.Field()
+	}
+	for _, key := range []string{"json", "form"} {
+		if name, _, _ := strings.Cut(field.Tag.Get(key), ","); name != "" && name != "-" {
+			return name
+		}
+	}
+	return fe.Field()
+}
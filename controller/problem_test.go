@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gin-project/config"
+)
+
+// bindInvalidCreateUser 以 accept 发送校验失败的创建用户请求，返回 BindError 的响应
+func bindInvalidCreateUser(accept string) *httptest.ResponseRecorder {
+	c, w := newContext(http.MethodPost, "/api/user")
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/user", strings.NewReader(`{"email":"not-an-email"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	if accept != "" {
+		c.Request.Header.Set("Accept", accept)
+	}
+
+	bc := &BaseController{}
+	var req CreateUserRequest
+	if err := bc.BindJSON(c, &req); err != nil {
+		bc.BindError(c, err, &req)
+	}
+	return w
+}
+
+func TestBindErrorProblemJSON(t *testing.T) {
+	useConfig(t, &config.Config{})
+	w := bindInvalidCreateUser(MIMEProblemJSON)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", w.Code)
+	}
+	if got := w.Header().Get("Content-Type"); !strings.HasPrefix(got, MIMEProblemJSON) {
+		t.Errorf("Content-Type = %q, want %s", got, MIMEProblemJSON)
+	}
+	var problem ProblemDetails
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("解析问题详情失败: %v, body = %s", err, w.Body.String())
+	}
+	if problem.Type != "about:blank" || problem.Title != "Bad Request" || problem.Status != http.StatusBadRequest ||
+		problem.Instance != "/api/user" || problem.Detail == "" {
+		t.Errorf("problem = %+v", problem)
+	}
+
+	rules := make(map[string]string)
+	for _, fe := range problem.Errors {
+		rules[fe.Field] = fe.Rule
+	}
+	if rules["name"] != "required" || rules["email"] != "email" || len(rules) != 2 {
+		t.Errorf("字段级错误 = %+v, want name=required、email=email", problem.Errors)
+	}
+}
+
+func TestBindErrorDefaultsToEnvelope(t *testing.T) {
+	useConfig(t, &config.Config{})
+	for _, accept := range []string{"", "*/*", "application/json"} {
+		w := bindInvalidCreateUser(accept)
+		if got := w.Header().Get("Content-Type"); strings.HasPrefix(got, MIMEProblemJSON) {
+			t.Errorf("Accept=%q 时不应返回 problem+json", accept)
+		}
+		if resp := decodeResponse(t, w); resp.Code != http.StatusBadRequest || resp.Message == "" {
+			t.Errorf("Accept=%q 时响应 = %+v", accept, resp)
+		}
+	}
+}
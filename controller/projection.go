@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FieldsQuery 稀疏字段集查询参数，如 ?fields=id,name 仅返回 data 中的指定字段
+const FieldsQuery = "fields"
+
+// listField 分页响应中数据列表的字段名（ListUsersResponse、CursorUsersResponse）
+const listField = "list"
+
+// projectData 按 ?fields= 裁剪响应数据
+// data 为对象时保留指定字段，为数组时对每个对象元素裁剪；
+// 分页响应（包含 list 数组的对象）裁剪 list 中的元素，保留 total 等分页字段；未指定字段或数据无法裁剪时原样返回
+func projectData(c *gin.Context, data interface{}) interface{} {
+	fields := parseFields(c.Query(FieldsQuery))
+	if len(fields) == 0 || data == nil {
+		return data
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return data
+	}
+	// 使用 UseNumber 解码，避免超过 2^53 的整数（如雪花ID）转为 float64 后丢失精度
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return data
+	}
+	decoded = restoreNumbers(decoded)
+
+	switch v := decoded.(type) {
+	case map[string]interface{}:
+		if list, ok := v[listField].([]interface{}); ok {
+			v[listField] = pickEach(list, fields)
+			return v
+		}
+		return pickFields(v, fields)
+	case []interface{}:
+		return pickEach(v, fields)
+	default:
+		return data
+	}
+}
+
+// restoreNumbers 将解码得到的 json.Number 转为 int64（非整数时为 float64），
+// 使 msgpack 等非 JSON 格式同样按数值编码
+func restoreNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = restoreNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = restoreNumbers(item)
+		}
+	}
+	return value
+}
+
+// pickEach 对数组中的每个对象元素保留指定字段
+func pickEach(list []interface{}, fields map[string]struct{}) []interface{} {
+	for i, item := range list {
+		if obj, ok := item.(map[string]interface{}); ok {
+			list[i] = pickFields(obj, fields)
+		}
+	}
+	return list
+}
+
+// parseFields 解析逗号分隔的字段列表（忽略空白和空项）
+func parseFields(value string) map[string]struct{} {
+	if value == "" {
+		return nil
+	}
+	fields := make(map[string]struct{})
+	for _, field := range strings.Split(value, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields[field] = struct{}{}
+		}
+	}
+	return fields
+}
+
+// normalizeFields 返回字段列表的规范化形式（去重、排序后以逗号连接），未指定字段时返回空字符串
+func normalizeFields(value string) string {
+	fields := parseFields(value)
+	names := make([]string, 0, len(fields))
+	for field := range fields {
+		names = append(names, field)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+// pickFields 保留对象中的指定字段
+func pickFields(obj map[string]interface{}, fields map[string]struct{}) map[string]interface{} {
+	picked := make(map[string]interface{}, len(fields))
+	for key, value := range obj {
+		if _, ok := fields[key]; ok {
+			picked[key] = value
+		}
+	}
+	return picked
+}
+
+// isNilData 判断数据是否为空（包括值为 nil 的指针、map、切片），空数据在响应中省略 data 字段
+func isNilData(data interface{}) bool {
+	if data == nil {
+		return true
+	}
+	v := reflect.ValueOf(data)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
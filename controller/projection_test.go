@@ -0,0 +1,143 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"gin-project/config"
+	"gin-project/database/dbtest"
+	"gin-project/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// keys 返回对象的字段名（排序后）
+func keys(obj interface{}) []string {
+	m, _ := obj.(map[string]interface{})
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestSuccessProjectsFields(t *testing.T) {
+	useConfig(t, &config.Config{})
+	user := model.User{ID: 1, Name: "alice", Email: "alice@example.com", Age: 20}
+
+	tests := []struct {
+		name   string
+		data   interface{}
+		fields string
+		check  func(t *testing.T, data interface{})
+	}{
+		{"对象", user, " id, name ,,", func(t *testing.T, data interface{}) {
+			if got := keys(data); !reflect.DeepEqual(got, []string{"id", "name"}) {
+				t.Errorf("字段 = %v, want [id name]", got)
+			}
+		}},
+		{"分页响应只裁剪 list", ListUsersResponse{List: []model.User{user}, Total: 1}, "email", func(t *testing.T, data interface{}) {
+			page := data.(map[string]interface{})
+			if page["total"] != float64(1) {
+				t.Errorf("total = %v, want 1", page["total"])
+			}
+			list := page[listField].([]interface{})
+			if got := keys(list[0]); !reflect.DeepEqual(got, []string{"email"}) {
+				t.Errorf("list 元素字段 = %v, want [email]", got)
+			}
+		}},
+		{"数组", []model.User{user, user}, "age", func(t *testing.T, data interface{}) {
+			for _, item := range data.([]interface{}) {
+				if got := keys(item); !reflect.DeepEqual(got, []string{"age"}) {
+					t.Errorf("元素字段 = %v, want [age]", got)
+				}
+			}
+		}},
+		{"未指定字段", user, "", func(t *testing.T, data interface{}) {
+			if len(keys(data)) <= 2 {
+				t.Errorf("未指定字段时应返回完整数据，got %v", keys(data))
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := newContext(http.MethodGet, "/?fields="+strings.ReplaceAll(tt.fields, " ", "%20"))
+			(&BaseController{}).Success(c, tt.data)
+			tt.check(t, decodeResponse(t, w).Data)
+		})
+	}
+}
+
+func TestProjectDataKeepsLargeIntegers(t *testing.T) {
+	// 超过 2^53 的整数转为 float64 会丢失精度
+	const big = uint(1<<53 + 1)
+	data := ListUsersResponse{List: []model.User{{ID: big, Name: "alice"}}, Total: 1}
+
+	c, _ := newContext(http.MethodGet, "/?fields=id")
+	page := projectData(c, data).(map[string]interface{})
+	item := page[listField].([]interface{})[0].(map[string]interface{})
+	if item["id"] != int64(big) {
+		t.Errorf("id = %v (%T), want %d", item["id"], item["id"], big)
+	}
+	if page["total"] != int64(1) {
+		t.Errorf("total = %v (%T), want int64 1", page["total"], page["total"])
+	}
+
+	c, w := newContext(http.MethodGet, "/?fields=id")
+	(&BaseController{}).Success(c, model.User{ID: big})
+	if want := fmt.Sprintf(`"id":%d`, big); !strings.Contains(w.Body.String(), want) {
+		t.Errorf("响应应包含 %s，body = %s", want, w.Body.String())
+	}
+}
+
+func TestSuccessOmitsNilData(t *testing.T) {
+	useConfig(t, &config.Config{})
+	var user *model.User
+
+	c, w := newContext(http.MethodGet, "/?fields=id")
+	(&BaseController{}).Success(c, user)
+	if strings.Contains(w.Body.String(), `"data"`) {
+		t.Errorf("nil 指针数据应省略 data 字段，body = %s", w.Body.String())
+	}
+}
+
+func TestGetUserWithFields(t *testing.T) {
+	useConfig(t, &config.Config{})
+	db := dbtest.Open(t, &model.User{})
+	mr := dbtest.Redis(t)
+	if err := db.Create(&model.User{Name: "alice", Email: "alice@example.com"}).Error; err != nil {
+		t.Fatalf("创建用户失败: %v", err)
+	}
+
+	engine := gin.New()
+	engine.GET("/api/user/:id", NewUserController(nil).GetUser)
+	get := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		return w
+	}
+
+	full := get("/api/user/1")
+	waitForCache(t, mr)
+	sparse := get("/api/user/1?fields=id,name")
+	if sparse.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", sparse.Code, sparse.Body.String())
+	}
+	data := decodeResponse(t, sparse).Data
+	if got := keys(data); !reflect.DeepEqual(got, []string{"id", "name"}) {
+		t.Errorf("字段 = %v, want [id name]", got)
+	}
+	if data.(map[string]interface{})["name"] != "alice" {
+		t.Errorf("name = %v", data)
+	}
+	// 字段集不同的响应使用不同的 ETag
+	if full.Header().Get("ETag") == sparse.Header().Get("ETag") {
+		t.Error("不同字段集应产生不同 ETag")
+	}
+}
@@ -1,13 +1,24 @@
 package controller
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
 
+	"gin-project/config"
 	"gin-project/logic"
 	"gin-project/model"
+	"gin-project/pkg"
 	"gin-project/service"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // UserController 用户控制器
@@ -25,21 +36,28 @@ func NewUserController(serviceFactory *service.Factory) *UserController {
 }
 
 // GetUserByID 查询用户接口 - 数据查询+缓存接口
+//
+//	@Summary	查询用户
+//	@Tags		用户
+//	@Accept		json
+//	@Produce	json
+//	@Param		request	body		GetUserRequest					true	"查询参数"
+//	@Success	200		{object}	APIResponse{data=model.User}	"成功（trace_id 用于链路追踪）"
+//	@Failure	400		{object}	APIResponse						"参数错误或查询失败"
+//	@Router		/api/user/query [post]
 func (uc *UserController) GetUserByID(c *gin.Context) {
-	var req struct {
-		ID uint `json:"id" binding:"required"`
-	}
+	var req GetUserRequest
 
 	// 绑定请求参数
-	if err := c.ShouldBindJSON(&req); err != nil {
-		uc.ErrorWithMsg(c, "参数错误: "+err.Error())
+	if err := uc.BindJSON(c, &req); err != nil {
+		uc.BindError(c, err, &req)
 		return
 	}
 
 	// 调用逻辑层查询用户
 	user, err := logic.GetUserByID(c.Request.Context(), req.ID)
 	if err != nil {
-		uc.ErrorWithMsg(c, "查询用户失败: "+err.Error())
+		uc.HandleError(c, err, "查询用户失败: ")
 		return
 	}
 
@@ -52,40 +70,115 @@ func (uc *UserController) GetUserByID(c *gin.Context) {
 	// 4. 服务层追踪：由 TraceServiceFunc 装饰器自动处理
 
 	// 从服务工厂获取服务C（所有方法自动追踪）
-	serviceC := uc.serviceFactory.GetServiceC()
+	// 服务C不可用时跳过调用，不影响已查询到的用户
+	serviceC, err := uc.serviceFactory.GetServiceC()
+	if err != nil {
+		pkg.LoggerFromContext(c.Request.Context()).Warn("获取服务C失败，跳过服务C调用", "error", err)
+		uc.Success(c, user)
+		return
+	}
 
 	// 调用计算接口（自动追踪，HTTP请求也自动追踪）
-	calculateResult, err := serviceC.Calculate(c.Request.Context(), 5)
+	calculateResult, err := serviceC.CalculateTyped(c.Request.Context(), 5)
 	if err != nil {
 		fmt.Printf("调用计算接口失败（已记录到追踪）: %v\n", err)
 	} else {
-		fmt.Printf("计算接口调用成功: %s\n", calculateResult)
+		fmt.Printf("计算接口调用成功: %d\n", calculateResult.Result)
 	}
 
 	// 调用处理接口（自动追踪，HTTP请求也自动追踪）
-	processResult, err := serviceC.Process(c.Request.Context(), "hello world")
+	processResult, err := serviceC.ProcessTyped(c.Request.Context(), "hello world")
 	if err != nil {
 		fmt.Printf("调用处理接口失败（已记录到追踪）: %v\n", err)
 	} else {
-		fmt.Printf("处理接口调用成功: %s\n", processResult)
+		fmt.Printf("处理接口调用成功: %s\n", processResult.Result)
 	}
 
 	// 返回成功响应（包含 trace_id）
 	uc.Success(c, user)
 }
 
+// defaultHTTPMaxAge 用户读取接口默认的 Cache-Control max-age（秒）
+const defaultHTTPMaxAge = 60
+
+// GetUser 读取用户接口 - 支持 ETag 条件请求
+// 客户端携带匹配的 If-None-Match 时返回 304，节省带宽；用户更新后 ETag 随之变化
+//
+//	@Summary	读取用户（支持 ETag）
+//	@Tags		用户
+//	@Produce	json
+//	@Param		id				path		int								true	"用户ID"
+//	@Param		If-None-Match	header		string							false	"上次响应的 ETag"
+//	@Param		fields			query		string							false	"仅返回指定字段（逗号分隔），如 id,name"
+//	@Success	200				{object}	APIResponse{data=model.User}	"成功"
+//	@Success	304				"未修改"
+//	@Failure	400				{object}	APIResponse						"参数错误或查询失败"
+//	@Failure	404				{object}	APIResponse						"用户不存在（code=10001）"
+//	@Router		/api/user/{id} [get]
+func (uc *UserController) GetUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		uc.ErrorWithMsg(c, "参数错误: 无效的用户ID")
+		return
+	}
+
+	// 调用逻辑层查询用户
+	user, err := logic.GetUserByID(c.Request.Context(), uint(id))
+	if err != nil {
+		uc.HandleError(c, err, "查询用户失败: ")
+		return
+	}
+
+	// 设置缓存头，客户端数据未变化时返回 304
+	etag := userETag(user, c.Query(FieldsQuery))
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", httpMaxAge()))
+	if ifNoneMatch(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	uc.Success(c, user)
+}
+
+// userETag 根据序列化后的用户数据和 ?fields= 字段集计算 ETag（任意字段变化都会改变 ETag）
+// 字段集按规范化形式（去重、排序）参与计算，同一用户不同的字段集对应不同的响应，ETag 也不同
+func userETag(user *model.User, fields string) string {
+	data, _ := json.Marshal(user)
+	h := sha256.New()
+	h.Write(data)
+	h.Write([]byte{0})
+	h.Write([]byte(normalizeFields(fields)))
+	sum := h.Sum(nil)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// httpMaxAge 获取用户读取接口的 Cache-Control max-age（cache.httpMaxAge，未配置时使用默认值）
+func httpMaxAge() int {
+	if config.Cfg != nil && config.Cfg.Cache.HTTPMaxAge > 0 {
+		return config.Cfg.Cache.HTTPMaxAge
+	}
+	return defaultHTTPMaxAge
+}
+
 // CreateUser 创建用户接口 - 数据写入接口
+//
+//	@Summary	创建用户
+//	@Tags		用户
+//	@Accept		json
+//	@Produce	json
+//	@Param		request	body		CreateUserRequest				true	"用户信息"
+//	@Success	200		{object}	APIResponse{data=model.User}	"成功"
+//	@Failure	400		{object}	APIResponse						"参数错误或创建失败（数据不合法时 code=10004）"
+//	@Failure	409		{object}	APIResponse						"邮箱已存在（code=10002）"
+//	@Failure	503		{object}	APIResponse						"超过并发上限（app.createBulkhead）"
+//	@Router		/api/user/create [post]
 func (uc *UserController) CreateUser(c *gin.Context) {
-	var req struct {
-		Name   string `json:"name" binding:"required"`
-		Email  string `json:"email" binding:"required,email"`
-		Age    int    `json:"age"`
-		Status int    `json:"status"`
-	}
+	var req CreateUserRequest
 
 	// 绑定请求参数
-	if err := c.ShouldBindJSON(&req); err != nil {
-		uc.ErrorWithMsg(c, "参数错误: "+err.Error())
+	if err := uc.BindJSON(c, &req); err != nil {
+		uc.BindError(c, err, &req)
 		return
 	}
 
@@ -100,7 +193,7 @@ func (uc *UserController) CreateUser(c *gin.Context) {
 	// 调用逻辑层创建用户（传递 context 用于追踪）
 	err := logic.CreateUser(c.Request.Context(), &user)
 	if err != nil {
-		uc.ErrorWithMsg(c, "创建用户失败: "+err.Error())
+		uc.HandleError(c, err, "创建用户失败: ")
 		return
 	}
 
@@ -109,37 +202,265 @@ func (uc *UserController) CreateUser(c *gin.Context) {
 }
 
 // UpdateUser 更新用户接口
+//
+//	@Summary	更新用户
+//	@Tags		用户
+//	@Accept		json
+//	@Produce	json
+//	@Param		request	body		UpdateUserRequest				true	"用户信息（包含读取到的版本号）"
+//	@Success	200		{object}	APIResponse{data=model.User}	"成功，返回更新后的数据"
+//	@Failure	400		{object}	APIResponse						"参数错误或更新失败"
+//	@Failure	404		{object}	APIResponse						"用户不存在（code=10001）"
+//	@Failure	409		{object}	APIResponse						"版本冲突（code=10003）"
+//	@Router		/api/user/update [put]
 func (uc *UserController) UpdateUser(c *gin.Context) {
-	var req struct {
-		ID     uint   `json:"id" binding:"required"`
-		Name   string `json:"name" binding:"required"`
-		Email  string `json:"email" binding:"required,email"`
-		Age    int    `json:"age"`
-		Status int    `json:"status"`
-	}
+	var req UpdateUserRequest
 
 	// 绑定请求参数
-	if err := c.ShouldBindJSON(&req); err != nil {
-		uc.ErrorWithMsg(c, "参数错误: "+err.Error())
+	if err := uc.BindJSON(c, &req); err != nil {
+		uc.BindError(c, err, &req)
 		return
 	}
 
 	// 创建用户对象用于更新
 	user := model.User{
-		ID:     req.ID,
-		Name:   req.Name,
-		Email:  req.Email,
-		Age:    req.Age,
-		Status: req.Status,
+		ID:      req.ID,
+		Name:    req.Name,
+		Email:   req.Email,
+		Age:     req.Age,
+		Status:  req.Status,
+		Version: req.Version,
 	}
 
 	// 调用逻辑层更新用户（传递 context 用于追踪）
-	err := logic.UpdateUser(c.Request.Context(), &user)
+	updated, err := logic.UpdateUser(c.Request.Context(), &user)
 	if err != nil {
-		uc.ErrorWithMsg(c, "更新用户失败: "+err.Error())
+		uc.HandleError(c, err, "更新用户失败: ")
 		return
 	}
 
-	// 返回成功响应
-	uc.Success(c, user)
+	// 返回更新后实际持久化的用户数据
+	uc.Success(c, updated)
+}
+
+// PatchUser 部分更新用户接口 - 仅更新请求中提供的字段
+//
+//	@Summary	部分更新用户
+//	@Tags		用户
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path		int								true	"用户ID"
+//	@Param		request	body		PatchUserRequest				true	"需要更新的字段"
+//	@Success	200		{object}	APIResponse{data=model.User}	"成功，返回更新后的数据"
+//	@Failure	400		{object}	APIResponse						"参数错误或更新失败"
+//	@Failure	404		{object}	APIResponse						"用户不存在（code=10001）"
+//	@Failure	409		{object}	APIResponse						"版本冲突（code=10003）"
+//	@Router		/api/user/{id} [patch]
+func (uc *UserController) PatchUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		uc.ErrorWithMsg(c, "参数错误: 无效的用户ID")
+		return
+	}
+
+	// 使用指针字段区分"未提供"和"零值"，仅校验提供的字段
+	var req PatchUserRequest
+
+	// 绑定请求参数
+	if err := uc.BindJSON(c, &req); err != nil {
+		uc.BindError(c, err, &req)
+		return
+	}
+
+	// 收集提供的字段
+	fields := make(map[string]interface{})
+	if req.Name != nil {
+		fields["name"] = *req.Name
+	}
+	if req.Email != nil {
+		fields["email"] = *req.Email
+	}
+	if req.Age != nil {
+		fields["age"] = *req.Age
+	}
+	if req.Status != nil {
+		fields["status"] = *req.Status
+	}
+
+	// 调用逻辑层部分更新用户（传递 context 用于追踪）
+	updated, err := logic.PatchUser(c.Request.Context(), uint(id), fields, req.Version)
+	if err != nil {
+		uc.HandleError(c, err, "更新用户失败: ")
+		return
+	}
+
+	// 返回更新后实际持久化的用户数据
+	uc.Success(c, updated)
+}
+
+// ListUsers 用户列表接口 - 支持偏移分页和游标分页
+// 偏移分页：?page=1&page_size=20&order=created_at:desc；游标分页：?mode=cursor&cursor=0&page_size=20
+//
+//	@Summary	用户列表
+//	@Tags		用户
+//	@Produce	json
+//	@Param		query	query		ListUsersRequest						false	"分页参数"
+//	@Param		fields	query		string									false	"仅返回列表元素的指定字段（逗号分隔），如 id,name"
+//	@Success	200		{object}	APIResponse{data=ListUsersResponse}		"偏移分页结果"
+//	@Success	200		{object}	APIResponse{data=CursorUsersResponse}	"游标分页结果（mode=cursor）"
+//	@Failure	400		{object}	APIResponse								"参数错误或查询失败"
+//	@Router		/api/user/list [get]
+func (uc *UserController) ListUsers(c *gin.Context) {
+	var req ListUsersRequest
+
+	// 绑定请求参数
+	if err := c.ShouldBindQuery(&req); err != nil {
+		uc.BindError(c, err, &req)
+		return
+	}
+
+	if req.Mode == "cursor" {
+		// 游标分页依赖 id 升序，不支持自定义排序
+		if req.Order != "" && req.Order != "id" && req.Order != "id:asc" {
+			uc.ErrorWithMsg(c, "参数错误: 游标分页仅支持按 id 升序")
+			return
+		}
+		users, nextCursor, err := logic.ListUsersByCursor(c.Request.Context(), req.Cursor, req.PageSize)
+		if err != nil {
+			uc.ErrorWithMsg(c, "查询用户列表失败: "+err.Error())
+			return
+		}
+		uc.Success(c, CursorUsersResponse{
+			List:       users,
+			NextCursor: nextCursor,
+			HasMore:    nextCursor != 0,
+		})
+		return
+	}
+
+	users, total, err := logic.ListUsers(c.Request.Context(), req.Page, req.PageSize, req.Order)
+	if err != nil {
+		uc.ErrorWithMsg(c, "查询用户列表失败: "+err.Error())
+		return
+	}
+	uc.Success(c, ListUsersResponse{
+		List:  users,
+		Total: total,
+	})
+}
+
+// CountUsers 用户统计接口 - 返回用户总数及各状态的用户数
+//
+//	@Summary	用户统计
+//	@Tags		用户
+//	@Produce	json
+//	@Success	200	{object}	APIResponse{data=CountUsersResponse}	"成功"
+//	@Failure	400	{object}	APIResponse								"统计失败"
+//	@Router		/api/user/count [get]
+func (uc *UserController) CountUsers(c *gin.Context) {
+	total, byStatus, err := logic.CountUsers(c.Request.Context())
+	if err != nil {
+		uc.ErrorWithMsg(c, "统计用户失败: "+err.Error())
+		return
+	}
+
+	uc.Success(c, CountUsersResponse{
+		Total:    total,
+		ByStatus: byStatus,
+	})
+}
+
+// UserExists 邮箱是否已使用接口 - 供注册流程轻量校验，不返回用户数据
+//
+//	@Summary	邮箱是否已使用
+//	@Tags		用户
+//	@Produce	json
+//	@Param		query	query		UserExistsRequest						true	"查询参数"
+//	@Success	200		{object}	APIResponse{data=UserExistsResponse}	"成功"
+//	@Failure	400		{object}	APIResponse								"参数错误或查询失败"
+//	@Router		/api/user/exists [get]
+func (uc *UserController) UserExists(c *gin.Context) {
+	var req UserExistsRequest
+
+	// 绑定请求参数
+	if err := c.ShouldBindQuery(&req); err != nil {
+		uc.BindError(c, err, &req)
+		return
+	}
+
+	exists, err := logic.UserExistsByEmail(c.Request.Context(), req.Email)
+	if err != nil {
+		uc.ErrorWithMsg(c, "查询失败: "+err.Error())
+		return
+	}
+	uc.Success(c, UserExistsResponse{Exists: exists})
+}
+
+// WarmUserCache 用户缓存预热接口 - 部署后分批将全部用户加载到 Redis，避免冷缓存导致数据库压力激增
+// 预热在后台执行，重复调用返回当前进度（预热进行中时不会重复启动）
+//
+//	@Summary	用户缓存预热
+//	@Tags		用户
+//	@Produce	json
+//	@Success	200	{object}	APIResponse{data=logic.CacheWarmProgress}	"已启动或正在预热，返回进度"
+//	@Failure	400	{object}	APIResponse									"启动预热失败"
+//	@Failure	401	{object}	APIResponse									"令牌无效"
+//	@Router		/api/user/cache/warm [post]
+func (uc *UserController) WarmUserCache(c *gin.Context) {
+	progress, err := logic.WarmUserCache(c.Request.Context())
+	if err != nil {
+		uc.ErrorWithMsg(c, "启动缓存预热失败: "+err.Error())
+		return
+	}
+	uc.Success(c, progress)
+}
+
+// exportFlushRows 导出时每写入多少行刷新一次响应
+const exportFlushRows = 100
+
+// ExportUsers 用户导出接口 - 以 NDJSON（每行一个用户 JSON）流式返回全部用户
+// 逐行读取数据库并分批刷新响应，内存占用与表大小无关；请求 span 覆盖整个导出过程，
+// 导出行数记录在 span 属性 export.rows 中。响应头发出后出错只能中断输出，错误记录在日志和 span 中
+//
+//	@Summary	用户导出
+//	@Tags		用户
+//	@Produce	application/x-ndjson
+//	@Success	200	{string}	string		"每行一个用户 JSON（model.User）"
+//	@Failure	400	{object}	APIResponse	"查询失败"
+//	@Router		/api/user/export [get]
+func (uc *UserController) ExportUsers(c *gin.Context) {
+	ctx := c.Request.Context()
+	rows, err := logic.ExportUsers(ctx)
+	if err != nil {
+		uc.HandleError(c, err, "导出用户失败: ")
+		return
+	}
+	defer rows.Close()
+
+	// 导出耗时与表大小相关，不受服务器写超时（app.server.writeTimeout）限制
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", MIMENDJSON)
+	c.Status(http.StatusOK)
+	c.Stream(func(w io.Writer) bool {
+		encoder := json.NewEncoder(w)
+		for i := 0; i < exportFlushRows; i++ {
+			user, ok := rows.Next()
+			if !ok {
+				return false
+			}
+			if err := encoder.Encode(user); err != nil {
+				// 客户端断开
+				return false
+			}
+		}
+		return true
+	})
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.Int("export.rows", rows.Count()))
+	if err := rows.Err(); err != nil {
+		span.RecordError(err)
+		pkg.LoggerFromContext(ctx).Error("导出用户中断", "rows", rows.Count(), "error", err)
+	}
 }
@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gin-project/config"
+	"gin-project/database/dbtest"
+	"gin-project/model"
+	"gin-project/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetUserConditionalGet(t *testing.T) {
+	useConfig(t, &config.Config{})
+	db := dbtest.Open(t, &model.User{})
+	mr := dbtest.Redis(t)
+	user := &model.User{Name: "alice", Email: "alice@example.com"}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("创建用户失败: %v", err)
+	}
+
+	engine := gin.New()
+	engine.GET("/api/user/:id", NewUserController(nil).GetUser)
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/user/1", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	first := get("")
+	waitForCache(t, mr)
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("首次请求 status = %d, ETag = %q", first.Code, etag)
+	}
+	if got := first.Header().Get("Cache-Control"); got != "private, max-age=60" {
+		t.Errorf("Cache-Control = %q", got)
+	}
+
+	second := get(etag)
+	if second.Code != http.StatusNotModified || second.Body.Len() != 0 {
+		t.Fatalf("携带 ETag 时 status = %d, body = %q, want 304", second.Code, second.Body.String())
+	}
+
+	// 用户更新后 ETag 变化，旧 ETag 不再命中
+	if err := db.Model(user).Update("name", "bob").Error; err != nil {
+		t.Fatalf("更新用户失败: %v", err)
+	}
+	mr.FlushAll()
+	third := get(etag)
+	waitForCache(t, mr)
+	if third.Code != http.StatusOK {
+		t.Fatalf("更新后 status = %d, want 200", third.Code)
+	}
+	if third.Header().Get("ETag") == etag {
+		t.Error("用户更新后 ETag 未变化")
+	}
+}
+
+func TestGetUserByIDWithoutServiceC(t *testing.T) {
+	useConfig(t, &config.Config{})
+	db := dbtest.Open(t, &model.User{})
+	mr := dbtest.Redis(t)
+	if err := db.Create(&model.User{Name: "alice", Email: "alice@example.com"}).Error; err != nil {
+		t.Fatalf("创建用户失败: %v", err)
+	}
+
+	// 服务C无法创建时仍返回已查询到的用户
+	factory := service.NewFactoryWithConfig(service.Config{})
+	factory.Register(service.ServiceCName, func(service.Config) (interface{}, error) {
+		return nil, errors.New("服务C地址解析失败")
+	})
+	engine := gin.New()
+	engine.POST("/api/user/query", NewUserController(factory).GetUserByID)
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/user/query", strings.NewReader(`{"id":1}`)))
+	waitForCache(t, mr)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s, want 200", w.Code, w.Body.String())
+	}
+	if data, _ := decodeResponse(t, w).Data.(map[string]interface{}); data["name"] != "alice" {
+		t.Errorf("data = %v, want 用户 alice", decodeResponse(t, w).Data)
+	}
+}
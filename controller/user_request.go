@@ -0,0 +1,74 @@
+package controller
+
+import "gin-project/model"
+
+// GetUserRequest 查询用户请求
+type GetUserRequest struct {
+	ID uint `json:"id" binding:"required" example:"1"` // 用户ID
+}
+
+// CreateUserRequest 创建用户请求
+type CreateUserRequest struct {
+	Name   string `json:"name" binding:"required" example:"张三"`                          // 用户姓名
+	Email  string `json:"email" binding:"required,email" example:"zhangsan@example.com"` // 用户邮箱
+	Age    int    `json:"age" example:"25"`                                              // 用户年龄
+	Status int    `json:"status" example:"1"`                                            // 用户状态 1-正常 0-禁用
+}
+
+// UpdateUserRequest 更新用户请求
+type UpdateUserRequest struct {
+	ID      uint   `json:"id" binding:"required" example:"1"`                             // 用户ID
+	Name    string `json:"name" binding:"required" example:"张三"`                          // 用户姓名
+	Email   string `json:"email" binding:"required,email" example:"zhangsan@example.com"` // 用户邮箱
+	Age     int    `json:"age" example:"26"`                                              // 用户年龄
+	Status  int    `json:"status" example:"1"`                                            // 用户状态 1-正常 0-禁用
+	Version int    `json:"version" example:"0"`                                           // 读取到的版本号（乐观锁）
+}
+
+// PatchUserRequest 部分更新用户请求
+// 使用指针字段区分"未提供"和"零值"，仅校验提供的字段
+type PatchUserRequest struct {
+	Name    *string `json:"name" binding:"omitempty,min=1" example:"张三"`                    // 用户姓名
+	Email   *string `json:"email" binding:"omitempty,email" example:"zhangsan@example.com"` // 用户邮箱
+	Age     *int    `json:"age" example:"30"`                                               // 用户年龄
+	Status  *int    `json:"status" example:"1"`                                             // 用户状态 1-正常 0-禁用
+	Version *int    `json:"version" example:"1"`                                            // 可选，提供时启用乐观锁校验
+}
+
+// ListUsersRequest 用户列表请求（查询参数）
+type ListUsersRequest struct {
+	Mode     string `form:"mode" binding:"omitempty,oneof=offset cursor" enums:"offset,cursor"` // 分页模式，默认 offset
+	Page     int    `form:"page" example:"1"`                                                   // 页码（偏移分页）
+	PageSize int    `form:"page_size" example:"20"`                                             // 每页条数
+	Cursor   uint   `form:"cursor" example:"0"`                                                 // 游标（游标分页，上一页的 next_cursor）
+	Order    string `form:"order" example:"created_at:desc"`                                    // 排序（偏移分页）：id、name、created_at，可加 :asc/:desc，默认 id 升序
+}
+
+// UserExistsRequest 邮箱是否已使用请求（查询参数）
+type UserExistsRequest struct {
+	Email string `form:"email" binding:"required,email" example:"zhangsan@example.com"` // 用户邮箱
+}
+
+// UserExistsResponse 邮箱是否已使用响应
+type UserExistsResponse struct {
+	Exists bool `json:"exists"` // 邮箱是否已被使用
+}
+
+// ListUsersResponse 用户列表响应（偏移分页）
+type ListUsersResponse struct {
+	List  []model.User `json:"list"`  // 当前页数据
+	Total int64        `json:"total"` // 总数
+}
+
+// CursorUsersResponse 用户列表响应（游标分页）
+type CursorUsersResponse struct {
+	List       []model.User `json:"list"`        // 当前页数据
+	NextCursor uint         `json:"next_cursor"` // 下一页游标，没有更多数据时为 0
+	HasMore    bool         `json:"has_more"`    // 是否还有更多数据
+}
+
+// CountUsersResponse 用户统计响应
+type CountUsersResponse struct {
+	Total    int64         `json:"total"`     // 用户总数
+	ByStatus map[int]int64 `json:"by_status"` // 各状态的用户数
+}
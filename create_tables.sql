@@ -5,6 +5,7 @@
 -- 创建用户表
 CREATE TABLE IF NOT EXISTS `users` (
     `id` bigint unsigned NOT NULL AUTO_INCREMENT COMMENT '用户ID，主键',
+    `tenant_id` varchar(64) NOT NULL DEFAULT '' COMMENT '租户ID（单租户部署时为空）',
     `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
     `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
     `deleted_at` timestamp NULL DEFAULT NULL COMMENT '软删除时间戳',
@@ -12,16 +13,42 @@ CREATE TABLE IF NOT EXISTS `users` (
     `email` varchar(100) NOT NULL COMMENT '用户邮箱',
     `age` int DEFAULT NULL COMMENT '用户年龄',
     `status` tinyint NOT NULL DEFAULT 1 COMMENT '用户状态 1-正常 0-禁用',
+    `version` int NOT NULL DEFAULT 0 COMMENT '版本号（乐观锁，每次更新递增）',
+    `created_by` varchar(100) DEFAULT NULL COMMENT '创建人',
+    `updated_by` varchar(100) DEFAULT NULL COMMENT '最后修改人',
     PRIMARY KEY (`id`),
     UNIQUE KEY `idx_users_email` (`email`),
     KEY `idx_users_deleted_at` (`deleted_at`),
-    KEY `idx_users_status` (`status`)
+    KEY `idx_users_status` (`status`),
+    KEY `idx_users_tenant_id` (`tenant_id`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='用户表';
 
 -- 创建索引优化查询性能
 -- 为邮箱字段创建唯一索引，确保邮箱唯一性
 ALTER TABLE `users` ADD CONSTRAINT `uk_users_email` UNIQUE (`email`) COMMENT '邮箱唯一约束';
 
+-- 已有表升级：添加乐观锁版本号字段
+-- ALTER TABLE `users` ADD COLUMN `version` int NOT NULL DEFAULT 0 COMMENT '版本号（乐观锁，每次更新递增）';
+
+-- 已有表升级：添加操作人字段
+-- ALTER TABLE `users` ADD COLUMN `created_by` varchar(100) DEFAULT NULL COMMENT '创建人', ADD COLUMN `updated_by` varchar(100) DEFAULT NULL COMMENT '最后修改人';
+
+-- 已有表升级：添加租户字段
+-- ALTER TABLE `users` ADD COLUMN `tenant_id` varchar(64) NOT NULL DEFAULT '' COMMENT '租户ID（单租户部署时为空）' AFTER `id`, ADD KEY `idx_users_tenant_id` (`tenant_id`);
+
+-- 创建审计日志表（记录用户等实体的写操作，与业务写入在同一事务中提交）
+CREATE TABLE IF NOT EXISTS `audit_logs` (
+    `id` bigint unsigned NOT NULL AUTO_INCREMENT COMMENT '审计日志ID，主键',
+    `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '操作时间',
+    `action` varchar(20) NOT NULL COMMENT '操作类型：create、update',
+    `entity` varchar(50) NOT NULL COMMENT '实体类型，如 user',
+    `entity_id` bigint unsigned NOT NULL COMMENT '实体ID',
+    `actor` varchar(100) NOT NULL COMMENT '操作人，未认证时为 anonymous',
+    `trace_id` varchar(32) DEFAULT NULL COMMENT '追踪ID',
+    PRIMARY KEY (`id`),
+    KEY `idx_audit_logs_entity_id` (`entity_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='审计日志表';
+
 -- 添加示例数据（可选）
 -- INSERT INTO `users` (`name`, `email`, `age`, `status`) VALUES 
 -- ('张三', 'zhangsan@example.com', 25, 1),
@@ -0,0 +1,45 @@
+package database
+
+import (
+	"gin-project/pkg"
+
+	"gorm.io/gorm"
+)
+
+// SystemActor context 中没有操作人时（如后台任务）写入的操作人
+const SystemActor = "system"
+
+// 操作人字段列名，模型包含对应字段时自动填充
+const (
+	createdByColumn = "created_by"
+	updatedByColumn = "updated_by"
+)
+
+// registerActorCallbacks 注册写入操作人字段的回调
+// 创建时填充 created_by 和 updated_by，更新时填充 updated_by；操作人来自 context（pkg.WithActor），
+// 未设置时为 SystemActor。使用 Select 指定更新列时需包含 updated_by 才会写入
+func registerActorCallbacks(db *gorm.DB) error {
+	if err := db.Callback().Create().Before("gorm:create").Register("app:stamp_created_by", stampActor(createdByColumn, updatedByColumn)); err != nil {
+		return err
+	}
+	return db.Callback().Update().Before("gorm:update").Register("app:stamp_updated_by", stampActor(updatedByColumn))
+}
+
+// stampActor 将当前操作人写入模型中存在的指定列
+func stampActor(columns ...string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Statement.Schema == nil {
+			return
+		}
+
+		actor, ok := pkg.LookupActor(db.Statement.Context)
+		if !ok {
+			actor = SystemActor
+		}
+		for _, column := range columns {
+			if db.Statement.Schema.LookUpField(column) != nil {
+				db.Statement.SetColumn(column, actor)
+			}
+		}
+	}
+}
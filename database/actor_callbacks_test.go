@@ -0,0 +1,68 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"gin-project/model"
+	"gin-project/pkg"
+)
+
+func TestActorCallbacksStampUsers(t *testing.T) {
+	db := openSQLite(t)
+	if err := db.AutoMigrate(&model.User{}); err != nil {
+		t.Fatalf("迁移 users 表失败: %v", err)
+	}
+	if err := registerActorCallbacks(db); err != nil {
+		t.Fatalf("注册回调失败: %v", err)
+	}
+
+	ctx := pkg.WithActor(context.Background(), "alice")
+	user := model.User{Name: "张三", Email: "actor@example.com"}
+	if err := db.WithContext(ctx).Create(&user).Error; err != nil {
+		t.Fatalf("创建失败: %v", err)
+	}
+
+	var created model.User
+	if err := db.First(&created, user.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if created.CreatedBy != "alice" || created.UpdatedBy != "alice" {
+		t.Errorf("创建后 CreatedBy/UpdatedBy = %q/%q, want alice/alice", created.CreatedBy, created.UpdatedBy)
+	}
+
+	ctx = pkg.WithActor(context.Background(), "bob")
+	if err := db.WithContext(ctx).Model(&created).Update("name", "李四").Error; err != nil {
+		t.Fatalf("更新失败: %v", err)
+	}
+	var updated model.User
+	if err := db.First(&updated, user.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if updated.CreatedBy != "alice" || updated.UpdatedBy != "bob" {
+		t.Errorf("更新后 CreatedBy/UpdatedBy = %q/%q, want alice/bob", updated.CreatedBy, updated.UpdatedBy)
+	}
+}
+
+func TestActorCallbacksDefaultToSystem(t *testing.T) {
+	db := openSQLite(t)
+	if err := db.AutoMigrate(&model.User{}); err != nil {
+		t.Fatalf("迁移 users 表失败: %v", err)
+	}
+	if err := registerActorCallbacks(db); err != nil {
+		t.Fatalf("注册回调失败: %v", err)
+	}
+
+	user := model.User{Name: "王五", Email: "system@example.com"}
+	if err := db.WithContext(context.Background()).Create(&user).Error; err != nil {
+		t.Fatalf("创建失败: %v", err)
+	}
+	if user.CreatedBy != SystemActor || user.UpdatedBy != SystemActor {
+		t.Errorf("CreatedBy/UpdatedBy = %q/%q, want %q", user.CreatedBy, user.UpdatedBy, SystemActor)
+	}
+
+	// 不含操作人字段的模型不受影响
+	if err := db.Create(&testUser{Name: "no-actor"}).Error; err != nil {
+		t.Errorf("创建 testUser 失败: %v", err)
+	}
+}
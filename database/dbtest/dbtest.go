@@ -0,0 +1,69 @@
+// Package dbtest 数据访问测试工具：使用内存 SQLite 和 miniredis 替换全局 DB 和 RedisClient，
+// 无需启动 MySQL/Redis 即可测试仓储和逻辑层
+//
+// 使用示例:
+//
+//	func TestGetUser(t *testing.T) {
+//		db := dbtest.Open(t, &model.User{})
+//		mr := dbtest.Redis(t)
+//		db.Create(&model.User{Name: "alice", Email: "alice@example.com"})
+//		// ... 调用逻辑层，mr.Keys() 查看写入的缓存键
+//	}
+package dbtest
+
+import (
+	"testing"
+
+	"gin-project/database"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/glebarez/sqlite"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Open 创建内存 SQLite 数据库，迁移 models 并设置为全局 DB，测试结束时恢复原来的 DB
+// 只使用一个连接（每个连接对应独立的内存数据库），同一时刻只能执行一个查询或事务
+func Open(t testing.TB, models ...interface{}) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("打开 SQLite 失败: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("获取 SQLite 连接失败: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatalf("迁移表结构失败: %v", err)
+	}
+
+	previous := database.DB
+	database.DB = db
+	t.Cleanup(func() {
+		database.DB = previous
+		_ = sqlDB.Close()
+	})
+	return db
+}
+
+// Redis 启动 miniredis 并设置为全局 RedisClient，测试结束时关闭并恢复原来的客户端
+func Redis(t testing.TB) *miniredis.Miniredis {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	previous := database.RedisClient
+	database.RedisClient = client
+	t.Cleanup(func() {
+		database.RedisClient = previous
+		_ = client.Close()
+	})
+	return mr
+}
@@ -0,0 +1,94 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gin-project/config"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// MySQL 可安全重试的锁错误码
+const (
+	mysqlErrLockWaitTimeout uint16 = 1205 // Lock wait timeout exceeded
+	mysqlErrDeadlock        uint16 = 1213 // Deadlock found when trying to get lock
+)
+
+// 死锁重试默认值（database.mysql.deadlockRetries / deadlockBackoff 未配置时使用）
+const (
+	DefaultDeadlockRetries = 2
+	DefaultDeadlockBackoff = 20 * time.Millisecond
+)
+
+// deadlockRetries 事务死锁重试次数，deadlockBackoff 首次重试前的等待时间（之后每次翻倍）
+var (
+	deadlockRetries = DefaultDeadlockRetries
+	deadlockBackoff = DefaultDeadlockBackoff
+)
+
+// initDeadlockRetry 读取死锁重试配置，retries 为负数时关闭重试
+func initDeadlockRetry(cfg config.Mysql) {
+	deadlockRetries = DefaultDeadlockRetries
+	if cfg.DeadlockRetries != nil {
+		deadlockRetries = *cfg.DeadlockRetries
+	}
+	deadlockBackoff = DefaultDeadlockBackoff
+	if cfg.DeadlockBackoff > 0 {
+		deadlockBackoff = cfg.DeadlockBackoff
+	}
+}
+
+// IsRetryableLockError 判断错误是否为可重试的死锁（1213）或锁等待超时（1205）
+func IsRetryableLockError(err error) bool {
+	var mysqlErr *mysqldriver.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return false
+	}
+	return mysqlErr.Number == mysqlErrDeadlock || mysqlErr.Number == mysqlErrLockWaitTimeout
+}
+
+// Transaction 在带默认查询超时的全局连接上执行事务，遇到死锁或锁等待超时时整体重试
+// 重试次数和退避由 database.mysql.deadlockRetries / deadlockBackoff 控制，每次重试在当前 span 上记录 db.deadlock_retry 事件；
+// 其他错误直接返回。fn 可能被执行多次，必须只通过 tx 修改数据，不能依赖上一次执行遗留的状态
+//
+// 使用示例:
+//
+//	err := database.Transaction(ctx, func(tx *gorm.DB) error { ... })
+func Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return retryOnLockError(ctx, deadlockRetries, deadlockBackoff, func() error {
+		db, cancel := Conn(ctx)
+		defer cancel()
+		return db.Transaction(fn)
+	})
+}
+
+// retryOnLockError 执行 run，遇到可重试的锁错误时按指数退避最多重试 retries 次
+func retryOnLockError(ctx context.Context, retries int, backoff time.Duration, run func() error) error {
+	span := trace.SpanFromContext(ctx)
+	for attempt := 0; ; attempt++ {
+		err := run()
+		if err == nil || attempt >= retries || !IsRetryableLockError(err) {
+			if attempt > 0 {
+				span.SetAttributes(attribute.Int("db.deadlock_retries", attempt))
+			}
+			return err
+		}
+
+		span.AddEvent("db.deadlock_retry", trace.WithAttributes(
+			attribute.Int("retry.attempt", attempt+1),
+			attribute.String("error", err.Error()),
+		))
+		timer := time.NewTimer(backoff << attempt)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	sdktracetest "go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/gorm"
+)
+
+// errDeadlock 模拟 MySQL 返回的死锁错误
+var errDeadlock = &mysqldriver.MySQLError{Number: mysqlErrDeadlock, Message: "Deadlock found when trying to get lock"}
+
+// useDeadlockRetry 设置死锁重试次数（退避极短）并将全局连接替换为内存 SQLite，测试结束时恢复
+func useDeadlockRetry(t *testing.T, retries int) {
+	t.Helper()
+	useQueryTimeout(t, 0)
+	previousRetries, previousBackoff := deadlockRetries, deadlockBackoff
+	deadlockRetries, deadlockBackoff = retries, time.Millisecond
+	t.Cleanup(func() { deadlockRetries, deadlockBackoff = previousRetries, previousBackoff })
+}
+
+// tracedContext 返回带已采样 span 的 context，结束 span 后可从记录器读取事件和属性
+func tracedContext(t *testing.T) (context.Context, func() sdktrace.ReadOnlySpan) {
+	t.Helper()
+	recorder := sdktracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	ctx, span := provider.Tracer("deadlock-test").Start(context.Background(), "transaction")
+	return ctx, func() sdktrace.ReadOnlySpan {
+		span.End()
+		return recorder.Ended()[0]
+	}
+}
+
+func TestTransactionRetriesDeadlock(t *testing.T) {
+	useDeadlockRetry(t, 2)
+	ctx, endSpan := tracedContext(t)
+
+	attempts := 0
+	err := Transaction(ctx, func(tx *gorm.DB) error {
+		attempts++
+		if err := tx.Create(&testUser{Name: fmt.Sprintf("attempt-%d", attempts)}).Error; err != nil {
+			return err
+		}
+		if attempts == 1 {
+			return errDeadlock
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Transaction = %v, want 死锁重试后成功", err)
+	}
+	if attempts != 2 {
+		t.Errorf("执行次数 = %d, want 2", attempts)
+	}
+
+	var names []string
+	DB.Model(&testUser{}).Pluck("name", &names)
+	if len(names) != 1 || names[0] != "attempt-2" {
+		t.Errorf("users = %v, want 仅保留重试成功的写入（死锁的事务已回滚）", names)
+	}
+
+	span := endSpan()
+	if events := span.Events(); len(events) != 1 || events[0].Name != "db.deadlock_retry" {
+		t.Errorf("span 事件 = %v, want 一次 db.deadlock_retry", events)
+	}
+	if got, ok := attributeValue(span.Attributes(), "db.deadlock_retries"); !ok || got.AsInt64() != 1 {
+		t.Errorf("db.deadlock_retries = %v, want 1", got.Emit())
+	}
+}
+
+func TestTransactionDoesNotRetryOtherErrors(t *testing.T) {
+	useDeadlockRetry(t, 2)
+
+	duplicate := &mysqldriver.MySQLError{Number: 1062, Message: "Duplicate entry"}
+	for _, want := range []error{errors.New("boom"), duplicate} {
+		attempts := 0
+		err := Transaction(context.Background(), func(*gorm.DB) error {
+			attempts++
+			return want
+		})
+		if !errors.Is(err, want) || attempts != 1 {
+			t.Errorf("%v: Transaction = (%v, %d 次), want 原样返回且不重试", want, err, attempts)
+		}
+	}
+}
+
+func TestTransactionGivesUpAfterRetries(t *testing.T) {
+	useDeadlockRetry(t, 2)
+
+	lockWait := fmt.Errorf("update user: %w", &mysqldriver.MySQLError{Number: mysqlErrLockWaitTimeout})
+	attempts := 0
+	err := Transaction(context.Background(), func(*gorm.DB) error {
+		attempts++
+		return lockWait
+	})
+	if !IsRetryableLockError(err) || attempts != 3 {
+		t.Errorf("Transaction = (%v, %d 次), want 重试 2 次后返回锁等待超时错误", err, attempts)
+	}
+}
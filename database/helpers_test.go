@@ -0,0 +1,51 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/uptrace/opentelemetry-go-extra/otelgorm"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	sdktracetest "go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testUser 包内测试使用的模型
+type testUser struct {
+	ID      uint `gorm:"primaryKey"`
+	Name    string
+	Version int
+}
+
+// openSQLite 创建内存 SQLite 数据库并迁移 testUser（单连接，每个连接对应独立的内存数据库）
+// 包内测试不能使用 dbtest（会形成循环导入）
+func openSQLite(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("打开 SQLite 失败: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	if err := db.AutoMigrate(&testUser{}); err != nil {
+		t.Fatalf("迁移表结构失败: %v", err)
+	}
+	return db
+}
+
+// traceSQLite 为 db 注册 otelgorm 插件（与 InitMysql 相同的可跳过 TracerProvider），返回记录 span 的记录器
+func traceSQLite(t *testing.T, db *gorm.DB) *sdktracetest.SpanRecorder {
+	t.Helper()
+	recorder := sdktracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	if err := db.Use(otelgorm.NewPlugin(otelgorm.WithTracerProvider(skippableTracerProvider{TracerProvider: provider}))); err != nil {
+		t.Fatalf("注册 otelgorm 插件失败: %v", err)
+	}
+	return recorder
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm/logger"
+)
+
+// DefaultSlowSQLThreshold 慢 SQL 默认阈值
+const DefaultSlowSQLThreshold = time.Second
+
+// traceLogger GORM 日志包装：慢 SQL 通过 slog 输出并携带 trace_id、span_id，
+// 便于从慢查询日志跳转到对应链路；其他日志仍由原日志记录器输出
+type traceLogger struct {
+	logger.Interface
+	level         logger.LogLevel
+	slowThreshold time.Duration
+}
+
+// newTraceLogger 创建带追踪关联的 GORM 日志记录器
+func newTraceLogger(inner logger.Interface, level logger.LogLevel, slowThreshold time.Duration) logger.Interface {
+	return traceLogger{
+		Interface:     inner.LogMode(level),
+		level:         level,
+		slowThreshold: slowThreshold,
+	}
+}
+
+// LogMode 设置日志级别（Session、Debug 等会调用）
+func (l traceLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return traceLogger{
+		Interface:     l.Interface.LogMode(level),
+		level:         level,
+		slowThreshold: l.slowThreshold,
+	}
+}
+
+// Trace 输出 SQL 日志，执行时间超过阈值且未出错时输出带 trace_id 的慢 SQL 警告
+func (l traceLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	elapsed := time.Since(begin)
+	if err != nil || l.level < logger.Warn || l.slowThreshold <= 0 || elapsed <= l.slowThreshold {
+		l.Interface.Trace(ctx, begin, fc, err)
+		return
+	}
+
+	sql, rows := fc()
+	attrs := []any{"elapsed", elapsed, "threshold", l.slowThreshold, "rows", rows, "sql", sql}
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.IsValid() {
+		attrs = append(attrs, "trace_id", spanCtx.TraceID().String(), "span_id", spanCtx.SpanID().String())
+	}
+	slog.WarnContext(ctx, "慢SQL", attrs...)
+}
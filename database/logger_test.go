@@ -0,0 +1,70 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// captureSlog 将默认 slog 输出替换为 JSON 缓冲区，测试结束时恢复
+func captureSlog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &buf
+}
+
+func TestSlowSQLLogCarriesTraceID(t *testing.T) {
+	out := captureSlog(t)
+	db := openSQLite(t)
+	provider := sdktrace.NewTracerProvider()
+	ctx, span := provider.Tracer("logger-test").Start(context.Background(), "request")
+	defer span.End()
+
+	// 阈值为 1ns，任何查询都视为慢 SQL
+	slow := db.Session(&gorm.Session{Logger: newTraceLogger(logger.Discard, logger.Warn, time.Nanosecond)})
+	var users []testUser
+	if err := slow.WithContext(ctx).Find(&users).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	var entry map[string]interface{}
+	line, _, _ := strings.Cut(out.String(), "\n")
+	if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		t.Fatalf("解析慢 SQL 日志失败: %v, output = %q", err, out.String())
+	}
+	if entry["msg"] != "慢SQL" || entry["level"] != "WARN" {
+		t.Errorf("日志 = %v", entry)
+	}
+	if entry["trace_id"] != span.SpanContext().TraceID().String() || entry["span_id"] != span.SpanContext().SpanID().String() {
+		t.Errorf("trace_id/span_id = %v/%v, want %s/%s", entry["trace_id"], entry["span_id"],
+			span.SpanContext().TraceID(), span.SpanContext().SpanID())
+	}
+	if sql, _ := entry["sql"].(string); !strings.Contains(sql, "test_users") {
+		t.Errorf("sql = %v", entry["sql"])
+	}
+}
+
+func TestFastSQLNotLoggedAsSlow(t *testing.T) {
+	out := captureSlog(t)
+	db := openSQLite(t)
+
+	fast := db.Session(&gorm.Session{Logger: newTraceLogger(logger.Discard, logger.Warn, time.Hour)})
+	var users []testUser
+	if err := fast.Find(&users).Error; err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("未超过阈值的查询不应输出慢 SQL 日志，got %q", out.String())
+	}
+}
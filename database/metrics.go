@@ -0,0 +1,58 @@
+package database
+
+import (
+	"database/sql"
+
+	"gin-project/pkg/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+// registerMysqlPoolMetrics 注册 MySQL 连接池指标（抓取时读取 sqlDB.Stats()）
+// 用于在连接池耗尽引起延迟之前发出告警
+func registerMysqlPoolMetrics(sqlDB *sql.DB) {
+	gauge := func(name, help string, value func(sql.DBStats) float64) prometheus.Collector {
+		return prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "mysql_pool",
+			Name:      name,
+			Help:      help,
+		}, func() float64 {
+			return value(sqlDB.Stats())
+		})
+	}
+
+	metrics.Register(
+		gauge("open_connections", "当前打开的连接数", func(s sql.DBStats) float64 { return float64(s.OpenConnections) }),
+		gauge("in_use", "正在使用的连接数", func(s sql.DBStats) float64 { return float64(s.InUse) }),
+		gauge("idle", "空闲连接数", func(s sql.DBStats) float64 { return float64(s.Idle) }),
+		gauge("wait_count", "累计等待连接的次数", func(s sql.DBStats) float64 { return float64(s.WaitCount) }),
+		gauge("wait_duration_seconds", "累计等待连接的时间（秒）", func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() }),
+	)
+}
+
+// registerRedisPoolMetrics 注册 Redis 连接池指标（抓取时读取 client.PoolStats()）
+// 多个实例通过 instance 标签区分
+func registerRedisPoolMetrics(instance string, client *redis.Client) {
+	gauge := func(name, help string, value func(*redis.PoolStats) float64) prometheus.Collector {
+		return prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace:   metrics.Namespace,
+			Subsystem:   "redis_pool",
+			Name:        name,
+			Help:        help,
+			ConstLabels: prometheus.Labels{"instance": instance},
+		}, func() float64 {
+			return value(client.PoolStats())
+		})
+	}
+
+	metrics.Register(
+		gauge("total_conns", "连接池中的连接总数", func(s *redis.PoolStats) float64 { return float64(s.TotalConns) }),
+		gauge("idle_conns", "连接池中的空闲连接数", func(s *redis.PoolStats) float64 { return float64(s.IdleConns) }),
+		gauge("stale_conns", "累计移除的过期连接数", func(s *redis.PoolStats) float64 { return float64(s.StaleConns) }),
+		gauge("hits", "累计从连接池获取到空闲连接的次数", func(s *redis.PoolStats) float64 { return float64(s.Hits) }),
+		gauge("misses", "累计连接池无空闲连接需新建的次数", func(s *redis.PoolStats) float64 { return float64(s.Misses) }),
+		gauge("timeouts", "累计等待连接超时的次数", func(s *redis.PoolStats) float64 { return float64(s.Timeouts) }),
+	)
+}
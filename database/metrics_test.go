@@ -0,0 +1,47 @@
+package database
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gin-project/pkg/metrics"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestPoolMetricsExposed(t *testing.T) {
+	metrics.Init(true)
+	t.Cleanup(func() { metrics.Init(false) })
+
+	sqlDB, err := openSQLite(t).DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	registerMysqlPoolMetrics(sqlDB)
+	registerRedisPoolMetrics(DefaultRedisInstance, client)
+
+	w := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := w.Body.String()
+
+	for _, name := range []string{
+		"gin_project_mysql_pool_open_connections",
+		"gin_project_mysql_pool_in_use",
+		"gin_project_mysql_pool_idle",
+		"gin_project_mysql_pool_wait_count",
+		"gin_project_mysql_pool_wait_duration_seconds",
+		`gin_project_redis_pool_total_conns{instance="default"}`,
+		`gin_project_redis_pool_idle_conns{instance="default"}`,
+		`gin_project_redis_pool_timeouts{instance="default"}`,
+	} {
+		if !strings.Contains(body, name) {
+			t.Errorf("/metrics 缺少指标 %s", name)
+		}
+	}
+}
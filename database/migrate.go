@@ -0,0 +1,47 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Migrate 执行表结构迁移（AutoMigrate）
+// dryRun 为 true 时使用 DryRun 会话，只生成计划执行的 DDL 并返回，不修改表结构
+func Migrate(db *gorm.DB, dryRun bool, models ...interface{}) ([]string, error) {
+	if !dryRun {
+		return nil, db.AutoMigrate(models...)
+	}
+
+	recorder := &ddlRecorder{Interface: db.Logger}
+	session := db.Session(&gorm.Session{DryRun: true, Logger: recorder})
+	if err := session.AutoMigrate(models...); err != nil {
+		return nil, err
+	}
+	return recorder.statements, nil
+}
+
+// ddlRecorder 记录迁移过程中生成的 DDL 语句
+// DryRun 模式下迁移器仍会执行只读查询（如检查表是否存在），这些查询不记录
+type ddlRecorder struct {
+	logger.Interface
+	mu         sync.Mutex
+	statements []string
+}
+
+// Trace 记录非查询语句，不输出日志
+func (r *ddlRecorder) Trace(_ context.Context, _ time.Time, fc func() (string, int64), _ error) {
+	sql, _ := fc()
+	upper := strings.ToUpper(strings.TrimSpace(sql))
+	if upper == "" || strings.HasPrefix(upper, "SELECT") || strings.HasPrefix(upper, "SHOW") {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.statements = append(r.statements, sql)
+}
@@ -0,0 +1,35 @@
+package database
+
+import (
+	"strings"
+	"testing"
+)
+
+// migrateProbe 迁移测试使用的模型（openSQLite 不会创建该表）
+type migrateProbe struct {
+	ID   uint `gorm:"primaryKey"`
+	Code string
+}
+
+func TestMigrateDryRunCreatesNoTable(t *testing.T) {
+	db := openSQLite(t)
+
+	statements, err := Migrate(db, true, &migrateProbe{})
+	if err != nil {
+		t.Fatalf("Migrate dry-run: %v", err)
+	}
+	if len(statements) == 0 || !strings.Contains(statements[0], "CREATE TABLE `migrate_probes`") {
+		t.Errorf("计划执行的 DDL = %q", statements)
+	}
+	if db.Migrator().HasTable(&migrateProbe{}) {
+		t.Fatal("dry-run 模式不应创建表")
+	}
+
+	statements, err = Migrate(db, false, &migrateProbe{})
+	if err != nil || statements != nil {
+		t.Fatalf("Migrate = (%q, %v)", statements, err)
+	}
+	if !db.Migrator().HasTable(&migrateProbe{}) {
+		t.Error("非 dry-run 模式应创建表")
+	}
+}
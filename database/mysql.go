@@ -1,6 +1,9 @@
 package database
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
 	"fmt"
 	"log"
 	"os"
@@ -8,6 +11,7 @@ import (
 
 	"gin-project/config"
 
+	mysqldriver "github.com/go-sql-driver/mysql"
 	"github.com/uptrace/opentelemetry-go-extra/otelgorm"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -16,60 +20,35 @@ import (
 
 var DB *gorm.DB
 
+// customTLSConfigName 使用自定义 CA 时注册到 MySQL 驱动的 TLS 配置名称
+const customTLSConfigName = "custom"
+
 // InitMysql 初始化MySQL数据库连接
 func InitMysql(cfg *config.Config) {
-	// 先连接到系统数据库
-	sysDSN := fmt.Sprintf("%s:%s@tcp(%s:%d)/?charset=%s&parseTime=%t&loc=%s",
-		cfg.Database.Mysql.Username,
-		cfg.Database.Mysql.Password,
-		cfg.Database.Mysql.Host,
-		cfg.Database.Mysql.Port,
-		cfg.Database.Mysql.Charset,
-		cfg.Database.Mysql.ParseTime,
-		cfg.Database.Mysql.Loc,
-	)
-
-	// 连接系统数据库
-	sysDB, err := gorm.Open(mysql.Open(sysDSN), &gorm.Config{
-		Logger: logger.Default,
-	})
-	if err != nil {
-		panic("failed to connect to system database: " + err.Error())
+	// 注册自定义 TLS 配置（database.mysql.tls 为 custom 时），必须在构建 DSN 之前
+	if err := registerTLSConfig(cfg.Database.Mysql); err != nil {
+		panic("failed to register mysql tls config: " + err.Error())
 	}
 
-	// 创建数据库（如果不存在）
-	dbName := cfg.Database.Mysql.Database
-	err = sysDB.Exec(fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci", dbName)).Error
-	if err != nil {
-		panic("failed to create database: " + err.Error())
+	// 创建数据库（如果不存在），数据库用户没有 CREATE 权限时可通过 createIfNotExists: false 跳过
+	if createIfNotExists(cfg.Database.Mysql) {
+		createDatabase(cfg.Database.Mysql)
 	}
 
-	// 关闭系统数据库连接
-	sqlDB, _ := sysDB.DB()
-	sqlDB.Close()
-
 	// 构建目标数据库的DSN
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=%s&parseTime=%t&loc=%s",
-		cfg.Database.Mysql.Username,
-		cfg.Database.Mysql.Password,
-		cfg.Database.Mysql.Host,
-		cfg.Database.Mysql.Port,
-		cfg.Database.Mysql.Database,
-		cfg.Database.Mysql.Charset,
-		cfg.Database.Mysql.ParseTime,
-		cfg.Database.Mysql.Loc,
-	)
+	dsn := buildDSN(cfg.Database.Mysql, cfg.Database.Mysql.Database)
 
-	// 配置GORM日志级别
-	newLogger := logger.New(
+	// 配置GORM日志级别（慢SQL由 traceLogger 输出，携带 trace_id 便于关联链路）
+	slowThreshold := slowSQLThreshold(cfg.Database.Mysql)
+	newLogger := newTraceLogger(logger.New(
 		log.New(os.Stdout, "\r\n", log.LstdFlags), // io writer
 		logger.Config{
-			SlowThreshold:             time.Second, // 慢SQL阈值
-			LogLevel:                  logger.Info, // 日志级别
-			IgnoreRecordNotFoundError: false,       // 忽略ErrRecordNotFound错误
-			Colorful:                  true,        // 彩色打印
+			SlowThreshold:             slowThreshold, // 慢SQL阈值
+			LogLevel:                  logger.Info,   // 日志级别
+			IgnoreRecordNotFoundError: false,         // 忽略ErrRecordNotFound错误
+			Colorful:                  true,          // 彩色打印
 		},
-	)
+	), logger.Info, slowThreshold)
 
 	// 连接到目标数据库
 	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
@@ -81,9 +60,11 @@ func InitMysql(cfg *config.Config) {
 	}
 
 	// 【最佳实践】使用 otelgorm 插件，自动追踪所有数据库操作（零代码入侵）
-	// 仅在追踪启用时注册插件，避免不必要的性能开销
+	// 仅在追踪启用时注册插件，避免不必要的性能开销；context 标记 SkipTracing 时不创建 span
+	// 每个 SQL span 自带 db.statement、db.sql.table 和 db.rows_affected（返回/影响的行数，用于发现意外的全表扫描），
+	// 无需额外回调；Rows() 逐行迭代时行数未知，不记录 db.rows_affected
 	if cfg.Tracing.Enabled {
-		if err := db.Use(otelgorm.NewPlugin()); err != nil {
+		if err := db.Use(otelgorm.NewPlugin(otelgorm.WithTracerProvider(newTracerProvider()))); err != nil {
 			panic("failed to register otelgorm plugin: " + err.Error())
 		}
 		log.Println("MySQL 追踪已启用")
@@ -91,16 +72,126 @@ func InitMysql(cfg *config.Config) {
 		log.Println("MySQL 追踪未启用（性能优化模式）")
 	}
 
+	// 自动填充操作人字段（created_by、updated_by）
+	if err := registerActorCallbacks(db); err != nil {
+		panic("failed to register actor callbacks: " + err.Error())
+	}
+
 	// 设置连接池
-	sqlDB, err = db.DB()
+	sqlDB, err := db.DB()
 	if err != nil {
 		panic("failed to get database instance: " + err.Error())
 	}
 
 	// 设置连接池参数
-	sqlDB.SetMaxIdleConns(cfg.Database.Mysql.MaxIdleConns) // 设置最大空闲连接数
-	sqlDB.SetMaxOpenConns(cfg.Database.Mysql.MaxOpenConns) // 设置最大打开连接数
-	sqlDB.SetConnMaxLifetime(time.Hour)                    // 设置连接最大生存时间
+	configurePool(sqlDB, cfg)
+
+	// 注册连接池指标（仅在指标启用时注册）
+	registerMysqlPoolMetrics(sqlDB)
 
+	queryTimeout = cfg.Database.Mysql.QueryTimeout
+	initDeadlockRetry(cfg.Database.Mysql)
 	DB = db
 }
+
+// slowSQLThreshold 获取慢SQL阈值（database.mysql.slowThreshold，未配置时使用默认值）
+func slowSQLThreshold(cfg config.Mysql) time.Duration {
+	if cfg.SlowThreshold > 0 {
+		return cfg.SlowThreshold
+	}
+	return DefaultSlowSQLThreshold
+}
+
+// createDatabase 连接到系统数据库并创建目标数据库（如果不存在）
+func createDatabase(cfg config.Mysql) {
+	// 连接系统数据库
+	sysDB, err := gorm.Open(mysql.Open(buildDSN(cfg, "")), &gorm.Config{
+		Logger: logger.Default,
+	})
+	if err != nil {
+		panic("failed to connect to system database: " + err.Error())
+	}
+
+	// 关闭系统数据库连接
+	defer func() {
+		if sqlDB, err := sysDB.DB(); err == nil {
+			sqlDB.Close()
+		}
+	}()
+
+	err = sysDB.Exec(fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci", cfg.Database)).Error
+	if err != nil {
+		panic("failed to create database: " + err.Error())
+	}
+}
+
+// createIfNotExists 是否在启动时创建数据库，未配置时默认创建（便于本地开发）
+func createIfNotExists(cfg config.Mysql) bool {
+	return cfg.CreateIfNotExists == nil || *cfg.CreateIfNotExists
+}
+
+// buildDSN 构建 MySQL DSN，dbName 为空时连接到服务器（不指定数据库）
+// 配置了 socket 时通过 Unix 套接字连接（如 Cloud SQL），忽略 host 和 port
+func buildDSN(cfg config.Mysql, dbName string) string {
+	address := fmt.Sprintf("tcp(%s:%d)", cfg.Host, cfg.Port)
+	if cfg.Socket != "" {
+		address = fmt.Sprintf("unix(%s)", cfg.Socket)
+	}
+
+	dsn := fmt.Sprintf("%s:%s@%s/%s?charset=%s&parseTime=%t&loc=%s",
+		cfg.Username,
+		cfg.Password,
+		address,
+		dbName,
+		cfg.Charset,
+		cfg.ParseTime,
+		cfg.Loc,
+	)
+
+	// 启用 TLS（true、skip-verify、preferred 或 custom）
+	if cfg.TLS != "" && cfg.TLS != "false" {
+		dsn += "&tls=" + cfg.TLS
+	}
+	return dsn
+}
+
+// registerTLSConfig database.mysql.tls 为 custom 时，使用 tlsCAFile 指定的 CA 证书注册 TLS 配置
+func registerTLSConfig(cfg config.Mysql) error {
+	if cfg.TLS != customTLSConfigName {
+		return nil
+	}
+	if cfg.TLSCAFile == "" {
+		return fmt.Errorf("database.mysql.tls 为 custom 时必须配置 tlsCAFile")
+	}
+
+	caPEM, err := os.ReadFile(cfg.TLSCAFile)
+	if err != nil {
+		return fmt.Errorf("读取 CA 证书失败: %v", err)
+	}
+	rootCAs := x509.NewCertPool()
+	if !rootCAs.AppendCertsFromPEM(caPEM) {
+		return fmt.Errorf("CA 证书 %s 格式无效", cfg.TLSCAFile)
+	}
+
+	return mysqldriver.RegisterTLSConfig(customTLSConfigName, &tls.Config{
+		RootCAs:    rootCAs,
+		ServerName: cfg.Host,
+		MinVersion: tls.VersionTLS12,
+	})
+}
+
+// configurePool 按 database.mysql 配置设置连接池参数
+func configurePool(sqlDB *sql.DB, cfg *config.Config) {
+	sqlDB.SetMaxIdleConns(cfg.Database.Mysql.MaxIdleConns)       // 设置最大空闲连接数
+	sqlDB.SetMaxOpenConns(cfg.Database.Mysql.MaxOpenConns)       // 设置最大打开连接数
+	sqlDB.SetConnMaxLifetime(connMaxLifetime(cfg))               // 设置连接最大生存时间
+	sqlDB.SetConnMaxIdleTime(cfg.Database.Mysql.ConnMaxIdleTime) // 设置连接最大空闲时间（0 表示不限制）
+}
+
+// connMaxLifetime 获取连接最大生存时间，未配置时默认1小时
+func connMaxLifetime(cfg *config.Config) time.Duration {
+	if cfg.Database.Mysql.ConnMaxLifetime > 0 {
+		return cfg.Database.Mysql.ConnMaxLifetime
+	}
+	return time.Hour
+}
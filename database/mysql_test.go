@@ -0,0 +1,220 @@
+package database
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"database/sql"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gin-project/config"
+
+	_ "github.com/glebarez/go-sqlite"
+)
+
+// openPool 打开不限制连接数的内存 SQLite 连接池（每个连接对应独立的内存数据库）
+func openPool(t *testing.T) *sql.DB {
+	t.Helper()
+	sqlDB, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	return sqlDB
+}
+
+func TestConfigurePoolAppliesConfig(t *testing.T) {
+	sqlDB := openPool(t)
+	configurePool(sqlDB, &config.Config{Database: config.Database{Mysql: config.Mysql{
+		MaxOpenConns:    3,
+		MaxIdleConns:    2,
+		ConnMaxIdleTime: 10 * time.Millisecond,
+	}}})
+
+	if got := sqlDB.Stats().MaxOpenConnections; got != 3 {
+		t.Errorf("MaxOpenConnections = %d, want 3", got)
+	}
+
+	// 同时占用 3 个连接后归还，最多保留 2 个空闲连接
+	ctx := context.Background()
+	var conns []*sql.Conn
+	for i := 0; i < 3; i++ {
+		conn, err := sqlDB.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		conns = append(conns, conn)
+	}
+	for _, conn := range conns {
+		_ = conn.Close()
+	}
+	if got := sqlDB.Stats().Idle; got != 2 {
+		t.Errorf("Idle = %d, want 2", got)
+	}
+
+	// 空闲超过 connMaxIdleTime 的连接由连接池后台清理（清理间隔至少 1 秒）
+	deadline := time.Now().Add(3 * time.Second)
+	for sqlDB.Stats().MaxIdleTimeClosed == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("空闲连接未按 connMaxIdleTime 关闭")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestConnMaxLifetime(t *testing.T) {
+	if got := connMaxLifetime(&config.Config{}); got != time.Hour {
+		t.Errorf("未配置时 connMaxLifetime = %s, want 1h", got)
+	}
+	cfg := &config.Config{Database: config.Database{Mysql: config.Mysql{ConnMaxLifetime: 5 * time.Minute}}}
+	if got := connMaxLifetime(cfg); got != 5*time.Minute {
+		t.Errorf("connMaxLifetime = %s, want 5m", got)
+	}
+}
+
+func TestBuildDSN(t *testing.T) {
+	base := config.Mysql{
+		Host:      "db.internal",
+		Port:      3307,
+		Username:  "app",
+		Password:  "secret",
+		Charset:   "utf8mb4",
+		ParseTime: true,
+		Loc:       "Local",
+	}
+	tests := []struct {
+		name   string
+		socket string
+		dbName string
+		want   string
+	}{
+		{"TCP", "", "users", "app:secret@tcp(db.internal:3307)/users?charset=utf8mb4&parseTime=true&loc=Local"},
+		{"TCP 系统库", "", "", "app:secret@tcp(db.internal:3307)/?charset=utf8mb4&parseTime=true&loc=Local"},
+		{"Unix 套接字", "/cloudsql/p:r:i", "users", "app:secret@unix(/cloudsql/p:r:i)/users?charset=utf8mb4&parseTime=true&loc=Local"},
+	}
+	for _, tt := range tests {
+		cfg := base
+		cfg.Socket = tt.socket
+		if got := buildDSN(cfg, tt.dbName); got != tt.want {
+			t.Errorf("%s: buildDSN = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// closedPort 返回本机当前没有监听的端口，连接会被立即拒绝
+func closedPort(t *testing.T) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	_ = listener.Close()
+	return port
+}
+
+// initMysqlPanic 执行 InitMysql 并返回其 panic 信息
+func initMysqlPanic(cfg *config.Config) (msg string) {
+	defer func() { msg = fmt.Sprint(recover()) }()
+	InitMysql(cfg)
+	return ""
+}
+
+func TestInitMysqlSkipsCreateDatabase(t *testing.T) {
+	disabled := false
+	tests := []struct {
+		name              string
+		createIfNotExists *bool
+		wantPanicPrefix   string
+	}{
+		{"默认连接系统库", nil, "failed to connect to system database"},
+		{"关闭后直接连接目标库", &disabled, "failed to connect database"},
+	}
+	for _, tt := range tests {
+		cfg := &config.Config{Database: config.Database{Mysql: config.Mysql{
+			Host:              "127.0.0.1",
+			Port:              closedPort(t),
+			Database:          "app",
+			Charset:           "utf8mb4",
+			Loc:               "Local",
+			CreateIfNotExists: tt.createIfNotExists,
+		}}}
+		if got := initMysqlPanic(cfg); !strings.HasPrefix(got, tt.wantPanicPrefix) {
+			t.Errorf("%s: panic = %q, want prefix %q", tt.name, got, tt.wantPanicPrefix)
+		}
+	}
+}
+
+func TestBuildDSNWithTLS(t *testing.T) {
+	for _, mode := range []string{"true", "skip-verify", "custom"} {
+		cfg := config.Mysql{Host: "db", Port: 3306, Charset: "utf8mb4", Loc: "Local", TLS: mode}
+		if dsn := buildDSN(cfg, "app"); !strings.HasSuffix(dsn, "&tls="+mode) {
+			t.Errorf("tls=%s: DSN = %q", mode, dsn)
+		}
+	}
+	for _, mode := range []string{"", "false"} {
+		cfg := config.Mysql{Host: "db", Port: 3306, TLS: mode}
+		if dsn := buildDSN(cfg, "app"); strings.Contains(dsn, "tls=") {
+			t.Errorf("tls=%q 时不应启用 TLS: DSN = %q", mode, dsn)
+		}
+	}
+}
+
+func TestRegisterTLSConfig(t *testing.T) {
+	if err := registerTLSConfig(config.Mysql{TLS: "true"}); err != nil {
+		t.Errorf("非 custom 模式不应注册: %v", err)
+	}
+	if err := registerTLSConfig(config.Mysql{TLS: customTLSConfigName}); err == nil {
+		t.Error("custom 模式未配置 tlsCAFile 时应返回错误")
+	}
+
+	invalid := filepath.Join(t.TempDir(), "invalid.pem")
+	if err := os.WriteFile(invalid, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := registerTLSConfig(config.Mysql{TLS: customTLSConfigName, TLSCAFile: invalid}); err == nil {
+		t.Error("CA 证书格式无效时应返回错误")
+	}
+
+	if err := registerTLSConfig(config.Mysql{TLS: customTLSConfigName, TLSCAFile: writeCACert(t), Host: "db"}); err != nil {
+		t.Errorf("注册自定义 CA 失败: %v", err)
+	}
+}
+
+// writeCACert 生成自签名 CA 证书并写入临时文件，返回文件路径
+func writeCACert(t *testing.T) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "gin-project-test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
@@ -0,0 +1,48 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ParseOrder 解析排序参数并生成 GORM 排序 scope
+// 格式为 "列名" 或 "列名:asc|desc"（如 created_at:desc），列名必须在 allowed 白名单中，防止 SQL 注入；
+// value 为空时按 defaultColumn 升序。排序列不是 id 时追加 id 升序作为第二排序键，保证分页结果稳定
+func ParseOrder(value string, allowed []string, defaultColumn string) (Scope, error) {
+	column, direction := defaultColumn, "asc"
+	if value != "" {
+		column, direction, _ = strings.Cut(strings.TrimSpace(value), ":")
+		if direction == "" {
+			direction = "asc"
+		}
+	}
+
+	if !containsColumn(allowed, column) {
+		return nil, fmt.Errorf("不支持按 %s 排序，可选: %s", column, strings.Join(allowed, "、"))
+	}
+	direction = strings.ToLower(direction)
+	if direction != "asc" && direction != "desc" {
+		return nil, fmt.Errorf("排序方向只能为 asc 或 desc: %s", direction)
+	}
+
+	columns := []clause.OrderByColumn{{Column: clause.Column{Name: column}, Desc: direction == "desc"}}
+	if column != "id" {
+		columns = append(columns, clause.OrderByColumn{Column: clause.Column{Name: "id"}})
+	}
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Order(clause.OrderBy{Columns: columns})
+	}, nil
+}
+
+// containsColumn 判断列名是否在白名单中
+func containsColumn(allowed []string, column string) bool {
+	for _, name := range allowed {
+		if name == column {
+			return true
+		}
+	}
+	return false
+}
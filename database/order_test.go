@@ -0,0 +1,50 @@
+package database
+
+import (
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestParseOrder(t *testing.T) {
+	db := openSQLite(t)
+	allowed := []string{"id", "name", "created_at"}
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", "ORDER BY `id`"},
+		{"id:desc", "ORDER BY `id` DESC"},
+		{"name", "ORDER BY `name`,`id`"},
+		{" created_at:DESC ", "ORDER BY `created_at` DESC,`id`"},
+	}
+	for _, tt := range tests {
+		order, err := ParseOrder(tt.value, allowed, "id")
+		if err != nil {
+			t.Errorf("ParseOrder(%q): %v", tt.value, err)
+			continue
+		}
+		sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+			return tx.Scopes(order).Find(&[]testUser{})
+		})
+		if !strings.HasSuffix(sql, tt.want) {
+			t.Errorf("ParseOrder(%q) SQL = %q, want suffix %q", tt.value, sql, tt.want)
+		}
+	}
+}
+
+func TestParseOrderRejects(t *testing.T) {
+	allowed := []string{"id", "name", "created_at"}
+	for _, value := range []string{
+		"email",
+		"id; DROP TABLE users",
+		"name:sideways",
+		"(select 1)",
+		"ID",
+	} {
+		if _, err := ParseOrder(value, allowed, "id"); err == nil {
+			t.Errorf("ParseOrder(%q) 应返回错误", value)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package database
+
+import "gorm.io/gorm"
+
+const (
+	DefaultPageSize = 20  // 默认分页大小
+	MaxPageSize     = 100 // 最大分页大小
+)
+
+// NormalizePage 规范化分页参数
+// 页码最小为 1；分页大小未指定时使用默认值，超过上限时截断为 MaxPageSize
+func NormalizePage(page, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	} else if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+	return page, pageSize
+}
+
+// Paginate 偏移分页 GORM scope，统一分页参数的校验和 OFFSET 计算
+//
+// 使用示例:
+//
+//	database.DB.WithContext(ctx).Scopes(database.Paginate(page, pageSize)).Find(&users)
+func Paginate(page, pageSize int) func(*gorm.DB) *gorm.DB {
+	page, pageSize = NormalizePage(page, pageSize)
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Offset((page - 1) * pageSize).Limit(pageSize)
+	}
+}
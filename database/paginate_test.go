@@ -0,0 +1,57 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestNormalizePage(t *testing.T) {
+	tests := []struct {
+		page, pageSize         int
+		wantPage, wantPageSize int
+	}{
+		{1, 10, 1, 10},
+		{0, 10, 1, 10},
+		{-3, 10, 1, 10},
+		{2, 0, 2, DefaultPageSize},
+		{2, -1, 2, DefaultPageSize},
+		{2, MaxPageSize, 2, MaxPageSize},
+		{2, MaxPageSize + 1, 2, MaxPageSize},
+	}
+	for _, tt := range tests {
+		page, pageSize := NormalizePage(tt.page, tt.pageSize)
+		if page != tt.wantPage || pageSize != tt.wantPageSize {
+			t.Errorf("NormalizePage(%d, %d) = (%d, %d), want (%d, %d)",
+				tt.page, tt.pageSize, page, pageSize, tt.wantPage, tt.wantPageSize)
+		}
+	}
+}
+
+func TestPaginateOffset(t *testing.T) {
+	db := openSQLite(t)
+	tests := []struct {
+		page, pageSize        int
+		wantLimit, wantOffset int
+	}{
+		{1, 10, 10, 0},
+		{3, 10, 10, 20},
+		{0, 10, 10, 0},
+		{2, 0, DefaultPageSize, DefaultPageSize},
+		{2, 500, MaxPageSize, MaxPageSize},
+	}
+	for _, tt := range tests {
+		sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+			return tx.Scopes(Paginate(tt.page, tt.pageSize)).Find(&[]testUser{})
+		})
+		want := fmt.Sprintf("LIMIT %d", tt.wantLimit)
+		if tt.wantOffset > 0 {
+			want += fmt.Sprintf(" OFFSET %d", tt.wantOffset)
+		}
+		if !strings.HasSuffix(sql, want) {
+			t.Errorf("Paginate(%d, %d) SQL = %q, want suffix %q", tt.page, tt.pageSize, sql, want)
+		}
+	}
+}
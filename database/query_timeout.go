@@ -0,0 +1,36 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// queryTimeout 单次数据库操作的默认超时（database.mysql.queryTimeout），0 表示仅受请求 context 限制
+var queryTimeout time.Duration
+
+// WithQueryTimeout 为数据库操作派生不超过 database.mysql.queryTimeout 的 context
+// 传入的 context 截止时间更早时保持不变；调用方必须调用返回的 cancel 释放计时器
+func WithQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if queryTimeout <= 0 {
+		return ctx, func() {}
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= queryTimeout {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, queryTimeout)
+}
+
+// Conn 获取绑定 context 并带默认查询超时的全局连接（保留 otelgorm 追踪），供逻辑层直接使用 GORM 时调用
+// 操作完成后必须调用 cancel。事务使用同一个超时，事务内的仓储操作各自再派生超时
+//
+// 使用示例:
+//
+//	db, cancel := database.Conn(ctx)
+//	defer cancel()
+//	err := db.Transaction(func(tx *gorm.DB) error { ... })
+func Conn(ctx context.Context) (*gorm.DB, context.CancelFunc) {
+	ctx, cancel := WithQueryTimeout(ctx)
+	return DB.WithContext(ctx), cancel
+}
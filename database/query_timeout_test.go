@@ -0,0 +1,71 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// useQueryTimeout 设置默认查询超时并将全局连接替换为内存 SQLite，测试结束时恢复
+func useQueryTimeout(t *testing.T, timeout time.Duration) {
+	t.Helper()
+	previousTimeout, previousDB := queryTimeout, DB
+	queryTimeout = timeout
+	DB = openSQLite(t)
+	t.Cleanup(func() { queryTimeout, DB = previousTimeout, previousDB })
+}
+
+// slowQuery 不会自行结束的递归查询，只能被 context 取消
+const slowQuery = "WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c) SELECT count(*) FROM c"
+
+func TestConnCancelsSlowQueryAtBudget(t *testing.T) {
+	useQueryTimeout(t, 100*time.Millisecond)
+
+	db, cancel := Conn(context.Background())
+	defer cancel()
+
+	// 使用 Exec 执行：SQLite 驱动只在执行语句期间响应 context 取消，读取结果行时不响应
+	start := time.Now()
+	err := db.Exec(slowQuery).Error
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatal("慢查询应在超时后被取消")
+	}
+	if elapsed > 2*time.Second {
+		t.Errorf("慢查询耗时 %v，应在 queryTimeout（100ms）附近取消", elapsed)
+	}
+	if db.Statement.Context.Err() != context.DeadlineExceeded {
+		t.Errorf("context err = %v, want DeadlineExceeded", db.Statement.Context.Err())
+	}
+}
+
+func TestWithQueryTimeoutRespectsShorterDeadline(t *testing.T) {
+	useQueryTimeout(t, time.Minute)
+
+	parent, cancelParent := context.WithTimeout(context.Background(), time.Second)
+	defer cancelParent()
+	ctx, cancel := WithQueryTimeout(parent)
+	defer cancel()
+	if ctx != parent {
+		t.Error("传入的截止时间更早时应直接使用传入的 context")
+	}
+
+	ctx, cancel = WithQueryTimeout(context.Background())
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	if !ok || time.Until(deadline) > time.Minute {
+		t.Errorf("未设置截止时间的 context 应派生 queryTimeout 超时，deadline = %v, ok = %v", deadline, ok)
+	}
+}
+
+func TestWithQueryTimeoutDisabled(t *testing.T) {
+	useQueryTimeout(t, 0)
+
+	parent := context.Background()
+	ctx, cancel := WithQueryTimeout(parent)
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok || ctx != parent {
+		t.Error("queryTimeout 为 0 时不应派生超时")
+	}
+}
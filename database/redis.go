@@ -2,43 +2,99 @@ package database
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"gin-project/config"
 
-	"github.com/redis/go-redis/v9"
 	redisotel "github.com/redis/go-redis/extra/redisotel/v9"
+	"github.com/redis/go-redis/v9"
 )
 
+// DefaultRedisInstance 默认 Redis 实例名称（使用 redis 顶层配置）
+const DefaultRedisInstance = "default"
+
+// RedisClient 默认 Redis 实例（兼容旧代码，等同于 Redis(DefaultRedisInstance)）
 var RedisClient *redis.Client
 
+var (
+	redisMu      sync.RWMutex
+	redisClients = map[string]*redis.Client{}
+)
+
 // InitRedis 初始化Redis连接
+// redis 顶层配置为默认实例；redis.instances 中的实例按名称初始化，通过 Redis(name) 获取
 func InitRedis(cfg *config.Config) {
-	RedisClient = redis.NewClient(&redis.Options{
-		Addr:     cfg.Redis.Addr,
-		Password: cfg.Redis.Password,
-		DB:       cfg.Redis.DB,
-		PoolSize: cfg.Redis.PoolSize,
+	if cfg.Tracing.Enabled {
+		log.Println("Redis 追踪已启用")
+	} else {
+		log.Println("Redis 追踪未启用（性能优化模式）")
+	}
+
+	RedisClient = newRedisClient(cfg, DefaultRedisInstance, cfg.Redis.Addr, cfg.Redis.Password, cfg.Redis.DB, cfg.Redis.PoolSize)
+
+	clients := map[string]*redis.Client{DefaultRedisInstance: RedisClient}
+	for _, instance := range cfg.Redis.Instances {
+		if instance.Name == "" || instance.Name == DefaultRedisInstance {
+			panic(fmt.Sprintf("invalid redis instance name %q", instance.Name))
+		}
+		if _, ok := clients[instance.Name]; ok {
+			panic("duplicate redis instance: " + instance.Name)
+		}
+		clients[instance.Name] = newRedisClient(cfg, instance.Name, instance.Addr, instance.Password, instance.DB, instance.PoolSize)
+	}
+
+	redisMu.Lock()
+	redisClients = clients
+	redisMu.Unlock()
+}
+
+// Redis 按名称获取 Redis 实例，名称为空时返回默认实例
+// 实例不存在时返回默认实例并记录日志，避免配置遗漏导致空指针
+func Redis(name string) *redis.Client {
+	if name == "" {
+		name = DefaultRedisInstance
+	}
+
+	redisMu.RLock()
+	client, ok := redisClients[name]
+	redisMu.RUnlock()
+	if !ok {
+		log.Printf("Redis 实例 %s 未配置，使用默认实例", name)
+		return RedisClient
+	}
+	return client
+}
+
+// newRedisClient 创建 Redis 客户端，注册追踪和连接池指标并测试连接
+func newRedisClient(cfg *config.Config, name, addr, password string, db, poolSize int) *redis.Client {
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       db,
+		PoolSize: poolSize,
 	})
 
 	// 【最佳实践】使用 redisotel 自动追踪所有 Redis 操作（零代码入侵）
-	// 仅在追踪启用时注册追踪，避免不必要的性能开销
+	// 仅在追踪启用时注册追踪，避免不必要的性能开销；context 标记 SkipTracing 时不创建 span
 	if cfg.Tracing.Enabled {
-		if err := redisotel.InstrumentTracing(RedisClient); err != nil {
+		if err := redisotel.InstrumentTracing(client, redisotel.WithTracerProvider(newTracerProvider())); err != nil {
 			panic("failed to instrument redis tracing: " + err.Error())
 		}
-		log.Println("Redis 追踪已启用")
-	} else {
-		log.Println("Redis 追踪未启用（性能优化模式）")
 	}
 
+	// 注册连接池指标（仅在指标启用时注册）
+	registerRedisPoolMetrics(name, client)
+
 	// 测试连接
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	_, err := RedisClient.Ping(ctx).Result()
+	_, err := client.Ping(ctx).Result()
 	if err != nil {
-		panic("failed to connect to redis: " + err.Error())
+		panic(fmt.Sprintf("failed to connect to redis %s: %v", name, err))
 	}
+	return client
 }
@@ -0,0 +1,118 @@
+package database
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"gin-project/pkg/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// DefaultRedisHealthInterval Redis 健康探测默认间隔
+	DefaultRedisHealthInterval = 5 * time.Second
+	// redisHealthPingTimeout 单次探测超时
+	redisHealthPingTimeout = time.Second
+)
+
+// RedisHealthStatus 默认 Redis 实例的探测状态
+type RedisHealthStatus struct {
+	Healthy   bool      `json:"healthy"`              // 最近一次探测是否成功
+	Since     time.Time `json:"since"`                // 进入当前状态的时间
+	LastError string    `json:"last_error,omitempty"` // 不可用时最近一次探测的错误
+}
+
+var (
+	redisHealthMu sync.RWMutex
+	// redisHealth 未启动探测时视为可用
+	redisHealth = RedisHealthStatus{Healthy: true, Since: time.Now()}
+
+	// redisUp 默认 Redis 实例是否可用（1 可用，0 不可用）
+	redisUp = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "redis",
+		Name:      "up",
+		Help:      "默认 Redis 实例是否可用（后台探测，1 可用，0 不可用）",
+	})
+	// redisDisconnects Redis 断开次数
+	redisDisconnects = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "redis",
+		Name:      "disconnects_total",
+		Help:      "后台探测发现默认 Redis 实例不可用的次数",
+	})
+)
+
+// RedisHealthy 默认 Redis 实例是否可用
+// 不可用期间读路径跳过缓存直接查询数据库，避免每个请求都等待 Redis 超时
+func RedisHealthy() bool {
+	redisHealthMu.RLock()
+	defer redisHealthMu.RUnlock()
+	return redisHealth.Healthy
+}
+
+// RedisHealth 获取默认 Redis 实例的探测状态
+func RedisHealth() RedisHealthStatus {
+	redisHealthMu.RLock()
+	defer redisHealthMu.RUnlock()
+	return redisHealth
+}
+
+// StartRedisHealthCheck 启动后台探测，定期 PING 默认 Redis 实例并在状态变化时记录日志和指标
+// interval <= 0 时使用默认间隔；返回的函数用于停止探测，返回时探测 goroutine 已退出
+func StartRedisHealthCheck(interval time.Duration) func() {
+	if interval <= 0 {
+		interval = DefaultRedisHealthInterval
+	}
+	metrics.Register(redisUp, redisDisconnects)
+	redisUp.Set(1)
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				probeRedis()
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(stop) })
+		<-done
+	}
+}
+
+// probeRedis 执行一次探测并更新状态（探测请求不创建 span）
+func probeRedis() {
+	ctx, cancel := context.WithTimeout(SkipTracing(context.Background()), redisHealthPingTimeout)
+	defer cancel()
+	err := RedisClient.Ping(ctx).Err()
+
+	redisHealthMu.Lock()
+	defer redisHealthMu.Unlock()
+
+	switch {
+	case err != nil && redisHealth.Healthy:
+		redisHealth = RedisHealthStatus{Healthy: false, Since: time.Now(), LastError: err.Error()}
+		redisUp.Set(0)
+		redisDisconnects.Inc()
+		log.Printf("Redis 不可用，读请求将跳过缓存: %v", err)
+	case err != nil:
+		redisHealth.LastError = err.Error()
+	case !redisHealth.Healthy:
+		log.Printf("Redis 已恢复，不可用持续 %s", time.Since(redisHealth.Since).Round(time.Second))
+		redisHealth = RedisHealthStatus{Healthy: true, Since: time.Now()}
+		redisUp.Set(1)
+	}
+}
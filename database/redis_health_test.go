@@ -0,0 +1,82 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/redis/go-redis/v9"
+)
+
+// useProbedRedis 将默认 Redis 实例替换为 miniredis，测试结束时恢复客户端和探测状态
+func useProbedRedis(t *testing.T) *miniredis.Miniredis {
+	t.Helper()
+	restoreRedis(t)
+	mr := miniredis.RunT(t)
+	RedisClient = redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+
+	redisHealthMu.Lock()
+	previous := redisHealth
+	redisHealthMu.Unlock()
+	t.Cleanup(func() {
+		redisHealthMu.Lock()
+		redisHealth = previous
+		redisHealthMu.Unlock()
+	})
+	return mr
+}
+
+func TestProbeRedisDetectsDropAndRecovery(t *testing.T) {
+	mr := useProbedRedis(t)
+
+	probeRedis()
+	if !RedisHealthy() {
+		t.Fatalf("Redis 可用时探测结果为不可用: %+v", RedisHealth())
+	}
+
+	disconnects := testutil.ToFloat64(redisDisconnects)
+	mr.Close()
+	probeRedis()
+	probeRedis() // 持续不可用只记一次断开
+	status := RedisHealth()
+	if status.Healthy || status.LastError == "" {
+		t.Fatalf("断开后状态 = %+v, want 不可用并记录错误", status)
+	}
+	if got := testutil.ToFloat64(redisDisconnects) - disconnects; got != 1 {
+		t.Errorf("断开次数增加 %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(redisUp); got != 0 {
+		t.Errorf("redis_up = %v, want 0", got)
+	}
+
+	if err := mr.Restart(); err != nil {
+		t.Fatal(err)
+	}
+	probeRedis()
+	status = RedisHealth()
+	if !status.Healthy || status.LastError != "" {
+		t.Errorf("恢复后状态 = %+v, want 可用", status)
+	}
+	if got := testutil.ToFloat64(redisUp); got != 1 {
+		t.Errorf("redis_up = %v, want 1", got)
+	}
+}
+
+func TestRedisHealthCheckRunsInBackground(t *testing.T) {
+	mr := useProbedRedis(t)
+	stop := StartRedisHealthCheck(10 * time.Millisecond)
+	t.Cleanup(stop)
+
+	mr.Close()
+	deadline := time.Now().Add(2 * time.Second)
+	for RedisHealthy() {
+		if time.Now().After(deadline) {
+			t.Fatal("后台探测未发现 Redis 断开")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	stop()
+	stop() // 重复停止不 panic
+}
@@ -0,0 +1,94 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gin-project/config"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+// restoreRedis 测试结束时关闭新建的实例，并恢复原来的默认实例和命名实例
+func restoreRedis(t *testing.T) {
+	t.Helper()
+	previousDefault := RedisClient
+	redisMu.RLock()
+	previousClients := redisClients
+	redisMu.RUnlock()
+	t.Cleanup(func() {
+		redisMu.Lock()
+		for name, client := range redisClients {
+			if previousClients[name] != client {
+				_ = client.Close()
+			}
+		}
+		redisClients = previousClients
+		redisMu.Unlock()
+		// 初始化中途 panic 时默认实例已创建但未登记
+		if RedisClient != previousDefault {
+			_ = RedisClient.Close()
+		}
+		RedisClient = previousDefault
+	})
+}
+
+func TestInitRedisNamedInstances(t *testing.T) {
+	restoreRedis(t)
+	cacheServer, locksServer := miniredis.RunT(t), miniredis.RunT(t)
+
+	InitRedis(&config.Config{Redis: config.Redis{
+		Addr:      cacheServer.Addr(),
+		Instances: []config.RedisInstance{{Name: "locks", Addr: locksServer.Addr(), DB: 2}},
+	}})
+
+	if Redis(DefaultRedisInstance) != RedisClient || Redis("") != RedisClient {
+		t.Fatal("默认实例应与 RedisClient 相同")
+	}
+	locks := Redis("locks")
+	if locks == RedisClient {
+		t.Fatal("命名实例不应与默认实例相同")
+	}
+	if got := locks.Options().DB; got != 2 {
+		t.Errorf("locks DB = %d, want 2", got)
+	}
+
+	ctx := context.Background()
+	if err := RedisClient.Set(ctx, "key", "cache", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := locks.Set(ctx, "key", "locks", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := cacheServer.Get("key"); got != "cache" {
+		t.Errorf("默认实例写入了错误的服务器: %q", got)
+	}
+	locksServer.Select(2)
+	if got, _ := locksServer.Get("key"); got != "locks" {
+		t.Errorf("locks 实例写入了错误的服务器: %q", got)
+	}
+
+	if Redis("unknown") != RedisClient {
+		t.Error("未配置的实例应回退到默认实例")
+	}
+}
+
+func TestInitRedisRejectsInvalidInstanceNames(t *testing.T) {
+	server := miniredis.RunT(t)
+	for _, instances := range [][]config.RedisInstance{
+		{{Name: "", Addr: server.Addr()}},
+		{{Name: DefaultRedisInstance, Addr: server.Addr()}},
+		{{Name: "locks", Addr: server.Addr()}, {Name: "locks", Addr: server.Addr()}},
+	} {
+		restoreRedis(t)
+		msg := func() (msg string) {
+			defer func() { msg, _ = recover().(string) }()
+			InitRedis(&config.Config{Redis: config.Redis{Addr: server.Addr(), Instances: instances}})
+			return ""
+		}()
+		if !strings.Contains(msg, "redis instance") {
+			t.Errorf("instances %+v: panic = %q", instances, msg)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package database
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Scope GORM 查询条件，用于组合 Where、Order、Paginate 等
+type Scope = func(*gorm.DB) *gorm.DB
+
+// Repository 通用数据访问仓储，封装模型 T 的增删改查
+// 所有操作都通过 DB.WithContext(ctx) 执行，保留 otelgorm 追踪，并受 database.mysql.queryTimeout 限制；主键列需为 id
+//
+// 使用示例:
+//
+//	users := database.NewRepository[model.User](nil)
+//	user, err := users.FindByID(ctx, id)
+//	list, err := users.List(ctx, database.Paginate(page, pageSize))
+type Repository[T any] struct {
+	db *gorm.DB // 为 nil 时使用全局 DB（调用时读取，可在 InitMysql 之前创建）
+}
+
+// NewRepository 创建仓储，db 为 nil 时使用全局 DB
+func NewRepository[T any](db *gorm.DB) *Repository[T] {
+	return &Repository[T]{db: db}
+}
+
+// WithDB 返回使用指定连接（如事务 tx）的仓储副本
+func (r *Repository[T]) WithDB(db *gorm.DB) *Repository[T] {
+	return &Repository[T]{db: db}
+}
+
+// conn 获取绑定 context（带默认查询超时）的连接，操作完成后必须调用 cancel
+func (r *Repository[T]) conn(ctx context.Context) (*gorm.DB, context.CancelFunc) {
+	db := r.db
+	if db == nil {
+		db = DB
+	}
+	ctx, cancel := WithQueryTimeout(ctx)
+	return db.WithContext(ctx), cancel
+}
+
+// Create 插入记录
+func (r *Repository[T]) Create(ctx context.Context, entity *T) error {
+	db, cancel := r.conn(ctx)
+	defer cancel()
+
+	return db.Create(entity).Error
+}
+
+// FindByID 按主键查询，scopes 可追加条件（如租户过滤），记录不存在时返回 gorm.ErrRecordNotFound
+func (r *Repository[T]) FindByID(ctx context.Context, id uint, scopes ...Scope) (*T, error) {
+	db, cancel := r.conn(ctx)
+	defer cancel()
+
+	entity := new(T)
+	if err := db.Scopes(scopes...).First(entity, id).Error; err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+// First 按条件查询第一条记录，记录不存在时返回 gorm.ErrRecordNotFound
+func (r *Repository[T]) First(ctx context.Context, scopes ...Scope) (*T, error) {
+	db, cancel := r.conn(ctx)
+	defer cancel()
+
+	entity := new(T)
+	if err := db.Scopes(scopes...).First(entity).Error; err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+// Update 按主键更新指定列，scopes 可追加条件（如乐观锁版本号、Select 列）
+// 返回受影响的行数，调用方据此判断记录不存在或版本冲突
+func (r *Repository[T]) Update(ctx context.Context, id uint, values map[string]interface{}, scopes ...Scope) (int64, error) {
+	db, cancel := r.conn(ctx)
+	defer cancel()
+
+	result := db.Model(new(T)).Scopes(scopes...).Where("id = ?", id).Updates(values)
+	return result.RowsAffected, result.Error
+}
+
+// Delete 按主键删除（模型包含 gorm.DeletedAt 时为软删除）
+func (r *Repository[T]) Delete(ctx context.Context, id uint) error {
+	db, cancel := r.conn(ctx)
+	defer cancel()
+
+	return db.Delete(new(T), id).Error
+}
+
+// List 按条件查询记录列表
+func (r *Repository[T]) List(ctx context.Context, scopes ...Scope) ([]T, error) {
+	db, cancel := r.conn(ctx)
+	defer cancel()
+
+	var entities []T
+	if err := db.Scopes(scopes...).Find(&entities).Error; err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
+
+// Exists 按条件判断记录是否存在（SELECT 1 ... LIMIT 1，不读取整行）
+func (r *Repository[T]) Exists(ctx context.Context, scopes ...Scope) (bool, error) {
+	db, cancel := r.conn(ctx)
+	defer cancel()
+
+	var found int
+	result := db.Model(new(T)).Scopes(scopes...).Select("1").Limit(1).Scan(&found)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Count 按条件统计记录数
+func (r *Repository[T]) Count(ctx context.Context, scopes ...Scope) (int64, error) {
+	db, cancel := r.conn(ctx)
+	defer cancel()
+
+	var total int64
+	err := db.Model(new(T)).Scopes(scopes...).Count(&total).Error
+	return total, err
+}
@@ -0,0 +1,92 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gin-project/model"
+
+	"gorm.io/gorm"
+)
+
+// openUserRepository 在内存 SQLite 上创建 User 仓储
+func openUserRepository(t *testing.T) *Repository[model.User] {
+	t.Helper()
+	db := openSQLite(t)
+	if err := db.AutoMigrate(&model.User{}); err != nil {
+		t.Fatalf("迁移 users 表失败: %v", err)
+	}
+	return NewRepository[model.User](db)
+}
+
+func TestRepositoryCRUD(t *testing.T) {
+	users := openUserRepository(t)
+	ctx := context.Background()
+
+	alice := &model.User{Name: "alice", Email: "alice@example.com"}
+	if err := users.Create(ctx, alice); err != nil || alice.ID == 0 {
+		t.Fatalf("Create = %v, id = %d", err, alice.ID)
+	}
+
+	found, err := users.FindByID(ctx, alice.ID)
+	if err != nil || found.Email != "alice@example.com" {
+		t.Fatalf("FindByID = (%+v, %v)", found, err)
+	}
+
+	rows, err := users.Update(ctx, alice.ID, map[string]interface{}{"name": "alicia"})
+	if err != nil || rows != 1 {
+		t.Fatalf("Update = (%d, %v), want 1 row", rows, err)
+	}
+	// 追加的条件不满足时不更新任何行
+	rows, err = users.Update(ctx, alice.ID, map[string]interface{}{"name": "x"}, func(db *gorm.DB) *gorm.DB {
+		return db.Where("version = ?", 99)
+	})
+	if err != nil || rows != 0 {
+		t.Fatalf("条件不满足时 Update = (%d, %v), want 0 rows", rows, err)
+	}
+	if found, _ := users.FindByID(ctx, alice.ID); found.Name != "alicia" {
+		t.Errorf("name = %q, want alicia", found.Name)
+	}
+
+	if err := users.Delete(ctx, alice.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := users.FindByID(ctx, alice.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("删除后 FindByID err = %v, want ErrRecordNotFound", err)
+	}
+}
+
+func TestRepositoryQueries(t *testing.T) {
+	users := openUserRepository(t)
+	ctx := context.Background()
+	for _, name := range []string{"alice", "bob", "carol"} {
+		if err := users.Create(ctx, &model.User{Name: name, Email: name + "@example.com"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	byName := func(name string) Scope {
+		return func(db *gorm.DB) *gorm.DB { return db.Where("name = ?", name) }
+	}
+
+	newestFirst, err := ParseOrder("id:desc", []string{"id"}, "id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := users.List(ctx, newestFirst, Paginate(1, 2))
+	if err != nil || len(list) != 2 || list[0].Name != "carol" {
+		t.Fatalf("List = (%v, %v), want carol, bob", list, err)
+	}
+	if first, err := users.First(ctx, byName("bob")); err != nil || first.Name != "bob" {
+		t.Errorf("First = (%+v, %v)", first, err)
+	}
+	if ok, err := users.Exists(ctx, byName("bob")); err != nil || !ok {
+		t.Errorf("Exists(bob) = (%v, %v), want true", ok, err)
+	}
+	if ok, err := users.Exists(ctx, byName("dave")); err != nil || ok {
+		t.Errorf("Exists(dave) = (%v, %v), want false", ok, err)
+	}
+	if total, err := users.Count(ctx); err != nil || total != 3 {
+		t.Errorf("Count = (%d, %v), want 3", total, err)
+	}
+}
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// skipTracingKey 跳过数据库追踪标记在 context 中的键
+type skipTracingKey struct{}
+
+// SkipTracing 标记 context 中的 MySQL/Redis 操作不创建 span
+// 用于健康检查等高频且无需追踪的内部调用，减少 span 开销
+//
+// 使用示例:
+//
+//	database.DB.WithContext(database.SkipTracing(ctx)).Exec("SELECT 1")
+func SkipTracing(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipTracingKey{}, true)
+}
+
+// tracingSkipped context 是否标记了跳过追踪
+func tracingSkipped(ctx context.Context) bool {
+	skip, _ := ctx.Value(skipTracingKey{}).(bool)
+	return skip
+}
+
+// noopTracer 跳过追踪时使用的无操作追踪器（返回不记录的 span，保留父 span 上下文）
+var noopTracer = noop.NewTracerProvider().Tracer("")
+
+// skippableTracerProvider 支持按 context 跳过追踪的 TracerProvider，供 otelgorm、redisotel 使用
+type skippableTracerProvider struct {
+	trace.TracerProvider
+}
+
+// newTracerProvider 包装全局 TracerProvider（必须在 InitTracing 之后调用）
+func newTracerProvider() trace.TracerProvider {
+	return skippableTracerProvider{TracerProvider: otel.GetTracerProvider()}
+}
+
+// Tracer 返回支持跳过追踪的 Tracer
+func (p skippableTracerProvider) Tracer(name string, opts ...trace.TracerOption) trace.Tracer {
+	return skippableTracer{Tracer: p.TracerProvider.Tracer(name, opts...)}
+}
+
+// skippableTracer context 标记了 SkipTracing 时不创建 span
+type skippableTracer struct {
+	trace.Tracer
+}
+
+// Start 创建 span，context 标记了 SkipTracing 时返回不记录的 span
+func (t skippableTracer) Start(ctx context.Context, spanName string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if tracingSkipped(ctx) {
+		return noopTracer.Start(ctx, spanName, opts...)
+	}
+	return t.Tracer.Start(ctx, spanName, opts...)
+}
@@ -0,0 +1,101 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	redisotel "github.com/redis/go-redis/extra/redisotel/v9"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	sdktracetest "go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSQLSpansRecordRowsAffected(t *testing.T) {
+	db := openSQLite(t)
+	recorder := traceSQLite(t, db)
+
+	users := []testUser{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	if err := db.Create(&users).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	var found []testUser
+	if err := db.WithContext(context.Background()).Where("name <> ?", "").Find(&found).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&testUser{}).Where("name IN ?", []string{"a", "b"}).Update("version", 1).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]int64{"gorm.Create": 3, "gorm.Query": 3, "gorm.Update": 2}
+	spans := recorder.Ended()
+	if len(spans) != len(want) {
+		t.Fatalf("span 数 = %d, want %d", len(spans), len(want))
+	}
+	for _, span := range spans {
+		rows, ok := attributeValue(span.Attributes(), "db.rows_affected")
+		if !ok {
+			t.Errorf("%s 缺少 db.rows_affected", span.Name())
+			continue
+		}
+		if rows.AsInt64() != want[span.Name()] {
+			t.Errorf("%s db.rows_affected = %d, want %d", span.Name(), rows.AsInt64(), want[span.Name()])
+		}
+	}
+}
+
+func TestSkipTracingCreatesNoSQLSpan(t *testing.T) {
+	db := openSQLite(t)
+	recorder := traceSQLite(t, db)
+
+	if err := db.WithContext(SkipTracing(context.Background())).Exec("SELECT 1").Error; err != nil {
+		t.Fatal(err)
+	}
+	if got := len(recorder.Ended()); got != 0 {
+		t.Errorf("SkipTracing 时不应创建 span，got %d", got)
+	}
+}
+
+// attributeValue 查找指定键的属性值
+func attributeValue(attrs []attribute.KeyValue, key attribute.Key) (attribute.Value, bool) {
+	for _, attr := range attrs {
+		if attr.Key == key {
+			return attr.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestSkipTracingCreatesNoRedisSpan(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	recorder := sdktracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	if err := redisotel.InstrumentTracing(client, redisotel.WithTracerProvider(skippableTracerProvider{TracerProvider: provider})); err != nil {
+		t.Fatal(err)
+	}
+
+	// 先建立连接，连接池拨号（redis.dial）的 span 不属于单次命令
+	ctx := context.Background()
+	if err := client.Ping(ctx).Err(); err != nil {
+		t.Fatal(err)
+	}
+	recorder.Reset()
+
+	if err := client.Set(SkipTracing(ctx), "skipped", "1", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(recorder.Ended()); got != 0 {
+		t.Fatalf("SkipTracing 时不应创建 span，got %d", got)
+	}
+
+	if err := client.Set(ctx, "traced", "1", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(recorder.Ended()); got != 1 {
+		t.Errorf("未标记时应创建 1 个 span，got %d", got)
+	}
+}
@@ -0,0 +1,79 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"gin-project/config"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// defaultWarmupTimeout 连接预热总超时
+const defaultWarmupTimeout = 10 * time.Second
+
+// Warmup 启动时预热连接池（database.mysql.warmup 开启时），避免启动后的首批请求承担建连延迟
+// MySQL 同时建立 maxIdleConns 个连接，默认 Redis 实例为每个连接池槽位（poolSize）建立连接并 PING，完成后全部归还为空闲连接
+// 预热失败只输出日志，不影响启动（依赖不可用时由启动诊断处理）
+func Warmup(cfg *config.Config) {
+	if !cfg.Database.Mysql.Warmup {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(SkipTracing(context.Background()), defaultWarmupTimeout)
+	defer cancel()
+
+	start := time.Now()
+	mysqlConns, mysqlErr := warmupMysql(ctx, cfg.Database.Mysql.MaxIdleConns)
+	redisConns, redisErr := warmupRedis(ctx, RedisClient)
+	if err := errors.Join(mysqlErr, redisErr); err != nil {
+		log.Printf("连接预热未全部完成: %v", err)
+	}
+	log.Printf("连接预热完成: mysql=%d, redis=%d, 耗时 %s", mysqlConns, redisConns, time.Since(start).Round(time.Millisecond))
+}
+
+// warmupMysql 同时占用 n 个连接并 PING，之后全部归还连接池，返回成功建立的连接数
+func warmupMysql(ctx context.Context, n int) (int, error) {
+	if DB == nil || n <= 0 {
+		return 0, nil
+	}
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return 0, err
+	}
+
+	warmed := 0
+	for i := 0; i < n; i++ {
+		conn, err := sqlDB.Conn(ctx)
+		if err != nil {
+			return warmed, err
+		}
+		// 先全部占用再归还（函数返回时），确保建立的是 n 个不同的连接
+		defer conn.Close()
+		if err := conn.PingContext(ctx); err != nil {
+			return warmed, err
+		}
+		warmed++
+	}
+	return warmed, nil
+}
+
+// warmupRedis 为连接池的每个槽位占用一个连接并 PING，之后全部归还连接池，返回成功建立的连接数
+func warmupRedis(ctx context.Context, client *redis.Client) (int, error) {
+	if client == nil {
+		return 0, nil
+	}
+
+	warmed := 0
+	for i := 0; i < client.Options().PoolSize; i++ {
+		conn := client.Conn()
+		defer conn.Close()
+		if err := conn.Ping(ctx).Err(); err != nil {
+			return warmed, err
+		}
+		warmed++
+	}
+	return warmed, nil
+}
@@ -0,0 +1,66 @@
+package database
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"gin-project/config"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/glebarez/sqlite"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// usePools 将全局 DB 替换为文件 SQLite（允许多个连接）、RedisClient 替换为 miniredis 客户端，测试结束时恢复
+func usePools(t *testing.T, maxIdleConns, redisPoolSize int) (*sql.DB, *redis.Client) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "warmup.db")), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("打开 SQLite 失败: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxIdleConns(maxIdleConns)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	previousDB := DB
+	DB = db
+	t.Cleanup(func() { DB = previousDB })
+
+	restoreRedis(t)
+	RedisClient = redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr(), PoolSize: redisPoolSize})
+	return sqlDB, RedisClient
+}
+
+func TestWarmupPrimesIdleConnections(t *testing.T) {
+	sqlDB, client := usePools(t, 4, 3)
+
+	Warmup(&config.Config{Database: config.Database{Mysql: config.Mysql{Warmup: true, MaxIdleConns: 4}}})
+
+	if stats := sqlDB.Stats(); stats.Idle != 4 || stats.InUse != 0 {
+		t.Errorf("MySQL 连接池 idle = %d, inUse = %d, want 4 个空闲连接", stats.Idle, stats.InUse)
+	}
+	if stats := client.PoolStats(); stats.IdleConns != 3 || stats.TotalConns != 3 {
+		t.Errorf("Redis 连接池 idle = %d, total = %d, want 3 个空闲连接", stats.IdleConns, stats.TotalConns)
+	}
+}
+
+func TestWarmupDisabled(t *testing.T) {
+	sqlDB, client := usePools(t, 4, 3)
+	// gorm.Open 会 PING 一次，连接池中已有一个连接
+	before := sqlDB.Stats().OpenConnections
+
+	Warmup(&config.Config{Database: config.Database{Mysql: config.Mysql{MaxIdleConns: 4}}})
+
+	if stats := sqlDB.Stats(); stats.OpenConnections != before {
+		t.Errorf("未开启预热时 MySQL 连接数 = %d, want %d（不新建连接）", stats.OpenConnections, before)
+	}
+	if stats := client.PoolStats(); stats.TotalConns != 0 {
+		t.Errorf("未开启预热时 Redis 连接数 = %d, want 0", stats.TotalConns)
+	}
+}
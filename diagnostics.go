@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"time"
+
+	"gin-project/config"
+	"gin-project/database"
+	"gin-project/pkg"
+	"gin-project/pkg/metrics"
+)
+
+// defaultStartupCheckTimeout 启动诊断中单个依赖探测的默认超时（health.startupCheck.timeout 未配置时使用）
+const defaultStartupCheckTimeout = 3 * time.Second
+
+// runStartupDiagnostics 初始化完成后输出一条结构化启动诊断日志，汇总生效配置和 MySQL、Redis 连接状态
+// health.startupCheck.failFast 开启时任一依赖不可用即退出，避免带病启动；否则仅输出 WARN 日志
+func runStartupDiagnostics(cfg *config.Config, port string) {
+	timeout := durationOr(cfg.Health.StartupCheck.Timeout, defaultStartupCheckTimeout)
+	mysqlStatus := probe(timeout, pingMysql)
+	redisStatus := probe(timeout, pingRedis)
+
+	sampleRate, err := pkg.TraceSampleRate()
+	if err != nil {
+		sampleRate = 0
+	}
+
+	mysqlCfg := cfg.Database.Mysql
+	mysqlAddr := mysqlCfg.Socket
+	if mysqlAddr == "" {
+		mysqlAddr = fmt.Sprintf("%s:%d", mysqlCfg.Host, mysqlCfg.Port)
+	}
+
+	healthy := mysqlStatus == "ok" && redisStatus == "ok"
+	level := slog.LevelInfo
+	if !healthy {
+		level = slog.LevelWarn
+	}
+	slog.Log(context.Background(), level, "启动诊断",
+		"mode", cfg.App.Mode,
+		"port", port,
+		"tls", cfg.App.TLS.Enabled,
+		"metrics", metrics.Enabled(),
+		slog.Group("tracing",
+			"enabled", cfg.Tracing.Enabled,
+			"endpoint", cfg.Tracing.Endpoint,
+			"sample_rate", sampleRate,
+		),
+		slog.Group("mysql",
+			"addr", mysqlAddr,
+			"database", mysqlCfg.Database,
+			"max_open_conns", mysqlCfg.MaxOpenConns,
+			"max_idle_conns", mysqlCfg.MaxIdleConns,
+			"status", mysqlStatus,
+		),
+		slog.Group("redis",
+			"addr", cfg.Redis.Addr,
+			"db", cfg.Redis.DB,
+			"pool_size", cfg.Redis.PoolSize,
+			"status", redisStatus,
+		),
+	)
+
+	if !healthy && cfg.Health.StartupCheck.FailFast {
+		log.Fatalf("启动诊断失败（health.startupCheck.failFast）: mysql=%s, redis=%s", mysqlStatus, redisStatus)
+	}
+}
+
+// probe 在超时内执行依赖探测，返回 ok 或失败原因
+func probe(timeout time.Duration, ping func(ctx context.Context) error) string {
+	ctx, cancel := context.WithTimeout(database.SkipTracing(context.Background()), timeout)
+	defer cancel()
+	if err := ping(ctx); err != nil {
+		return "down: " + err.Error()
+	}
+	return "ok"
+}
+
+// pingMysql 探测 MySQL 连接
+func pingMysql(ctx context.Context) error {
+	if database.DB == nil {
+		return fmt.Errorf("数据库未初始化")
+	}
+	sqlDB, err := database.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// pingRedis 探测默认 Redis 实例连接
+func pingRedis(ctx context.Context) error {
+	if database.RedisClient == nil {
+		return fmt.Errorf("Redis未初始化")
+	}
+	return database.RedisClient.Ping(ctx).Err()
+}
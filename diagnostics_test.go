@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"gin-project/config"
+	"gin-project/database/dbtest"
+)
+
+// diagnosticsConfig 启动诊断测试使用的配置
+func diagnosticsConfig(failFast bool) *config.Config {
+	return &config.Config{
+		App:      config.App{Mode: "release"},
+		Database: config.Database{Mysql: config.Mysql{Host: "db.internal", Port: 3306, Database: "app", MaxOpenConns: 20}},
+		Redis:    config.Redis{Addr: "cache.internal:6379", PoolSize: 10},
+		Health:   config.Health{StartupCheck: config.StartupCheck{FailFast: failFast, Timeout: 200 * time.Millisecond}},
+	}
+}
+
+// captureDiagnostics 执行启动诊断，返回输出的唯一一条结构化日志
+func captureDiagnostics(t *testing.T, cfg *config.Config) map[string]interface{} {
+	t.Helper()
+	var logs bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	runStartupDiagnostics(cfg, "8080")
+
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("启动诊断应只输出一条日志，got %d: %s", len(lines), logs.String())
+	}
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatalf("解析日志失败: %v, logs = %s", err, logs.String())
+	}
+	return record
+}
+
+func TestStartupDiagnosticsSummarizesConfig(t *testing.T) {
+	dbtest.Open(t)
+	dbtest.Redis(t)
+
+	record := captureDiagnostics(t, diagnosticsConfig(false))
+	if record["level"] != "INFO" || record["msg"] != "启动诊断" || record["mode"] != "release" || record["port"] != "8080" {
+		t.Errorf("启动诊断 = %v", record)
+	}
+	mysql := record["mysql"].(map[string]interface{})
+	if mysql["addr"] != "db.internal:3306" || mysql["database"] != "app" || mysql["max_open_conns"] != float64(20) || mysql["status"] != "ok" {
+		t.Errorf("mysql = %v", mysql)
+	}
+	redis := record["redis"].(map[string]interface{})
+	if redis["addr"] != "cache.internal:6379" || redis["pool_size"] != float64(10) || redis["status"] != "ok" {
+		t.Errorf("redis = %v", redis)
+	}
+	if tracing := record["tracing"].(map[string]interface{}); tracing["enabled"] != false {
+		t.Errorf("tracing = %v", tracing)
+	}
+}
+
+func TestStartupDiagnosticsWarnsWhenDependencyDown(t *testing.T) {
+	dbtest.Open(t)
+	mr := dbtest.Redis(t)
+	mr.Close()
+
+	record := captureDiagnostics(t, diagnosticsConfig(false))
+	if record["level"] != "WARN" {
+		t.Errorf("依赖不可用时 level = %v, want WARN", record["level"])
+	}
+	if status := record["redis"].(map[string]interface{})["status"].(string); !strings.HasPrefix(status, "down: ") {
+		t.Errorf("redis.status = %q, want down: <原因>", status)
+	}
+	if status := record["mysql"].(map[string]interface{})["status"]; status != "ok" {
+		t.Errorf("mysql.status = %v, want ok", status)
+	}
+}
+
+func TestStartupDiagnosticsFailFast(t *testing.T) {
+	// log.Fatalf 会退出进程，在子进程中执行（未初始化数据库和 Redis）
+	if os.Getenv("STARTUP_DIAGNOSTICS_FAIL_FAST") == "1" {
+		runStartupDiagnostics(diagnosticsConfig(true), "8080")
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestStartupDiagnosticsFailFast$")
+	cmd.Env = append(os.Environ(), "STARTUP_DIAGNOSTICS_FAIL_FAST=1")
+	out, err := cmd.CombinedOutput()
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.Success() {
+		t.Fatalf("failFast 模式下依赖不可用时应退出进程，err = %v, output = %s", err, out)
+	}
+	if !strings.Contains(string(out), "启动诊断失败") || !strings.Contains(string(out), "数据库未初始化") {
+		t.Errorf("退出前应输出原因，output = %s", out)
+	}
+}
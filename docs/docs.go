@@ -0,0 +1,1197 @@
+// Package docs Code generated by swaggo/swag. DO NOT EDIT
+package docs
+
+import "github.com/swaggo/swag"
+
+const docTemplate = `{
+    "schemes": {{ marshal .Schemes }},
+    "swagger": "2.0",
+    "info": {
+        "description": "{{escape .Description}}",
+        "title": "{{.Title}}",
+        "contact": {},
+        "version": "{{.Version}}"
+    },
+    "host": "{{.Host}}",
+    "basePath": "{{.BasePath}}",
+    "paths": {
+        "/admin/maintenance": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "运维"
+                ],
+                "summary": "查询维护模式",
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/controller.MaintenanceResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "令牌无效",
+                        "schema": {
+                            "$ref": "#/definitions/controller.APIResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "运维"
+                ],
+                "summary": "切换维护模式",
+                "parameters": [
+                    {
+                        "description": "维护模式开关",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.MaintenanceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功，返回切换后的状态",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/controller.MaintenanceResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/controller.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "令牌无效",
+                        "schema": {
+                            "$ref": "#/definitions/controller.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/cache/warm": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户"
+                ],
+                "summary": "用户缓存预热",
+                "responses": {
+                    "200": {
+                        "description": "已启动或正在预热，返回进度",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/logic.CacheWarmProgress"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "启动预热失败",
+                        "schema": {
+                            "$ref": "#/definitions/controller.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "令牌无效",
+                        "schema": {
+                            "$ref": "#/definitions/controller.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/count": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户"
+                ],
+                "summary": "用户统计",
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/controller.CountUsersResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "统计失败",
+                        "schema": {
+                            "$ref": "#/definitions/controller.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/create": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户"
+                ],
+                "summary": "创建用户",
+                "parameters": [
+                    {
+                        "description": "用户信息",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.User"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误或创建失败（数据不合法时 code=10004）",
+                        "schema": {
+                            "$ref": "#/definitions/controller.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "邮箱已存在（code=10002）",
+                        "schema": {
+                            "$ref": "#/definitions/controller.APIResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "超过并发上限（app.createBulkhead）",
+                        "schema": {
+                            "$ref": "#/definitions/controller.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/exists": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户"
+                ],
+                "summary": "邮箱是否已使用",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "zhangsan@example.com",
+                        "description": "用户邮箱",
+                        "name": "email",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/controller.UserExistsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误或查询失败",
+                        "schema": {
+                            "$ref": "#/definitions/controller.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/export": {
+            "get": {
+                "produces": [
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "用户"
+                ],
+                "summary": "用户导出",
+                "responses": {
+                    "200": {
+                        "description": "每行一个用户 JSON（model.User）",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "查询失败",
+                        "schema": {
+                            "$ref": "#/definitions/controller.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/list": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户"
+                ],
+                "summary": "用户列表",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 0,
+                        "description": "游标（游标分页，上一页的 next_cursor）",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "offset",
+                            "cursor"
+                        ],
+                        "type": "string",
+                        "description": "分页模式，默认 offset",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "created_at:desc",
+                        "description": "排序（偏移分页）：id、name、created_at，可加 :asc/:desc，默认 id 升序",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "页码（偏移分页）",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 20,
+                        "description": "每页条数",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "仅返回列表元素的指定字段（逗号分隔），如 id,name",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "游标分页结果（mode=cursor）",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/controller.CursorUsersResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误或查询失败",
+                        "schema": {
+                            "$ref": "#/definitions/controller.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/query": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户"
+                ],
+                "summary": "查询用户",
+                "parameters": [
+                    {
+                        "description": "查询参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.GetUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功（trace_id 用于链路追踪）",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.User"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误或查询失败",
+                        "schema": {
+                            "$ref": "#/definitions/controller.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/update": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户"
+                ],
+                "summary": "更新用户",
+                "parameters": [
+                    {
+                        "description": "用户信息（包含读取到的版本号）",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.UpdateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功，返回更新后的数据",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.User"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误或更新失败",
+                        "schema": {
+                            "$ref": "#/definitions/controller.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "用户不存在（code=10001）",
+                        "schema": {
+                            "$ref": "#/definitions/controller.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "版本冲突（code=10003）",
+                        "schema": {
+                            "$ref": "#/definitions/controller.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户"
+                ],
+                "summary": "读取用户（支持 ETag）",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "上次响应的 ETag",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "仅返回指定字段（逗号分隔），如 id,name",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.User"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "304": {
+                        "description": "未修改"
+                    },
+                    "400": {
+                        "description": "参数错误或查询失败",
+                        "schema": {
+                            "$ref": "#/definitions/controller.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "用户不存在（code=10001）",
+                        "schema": {
+                            "$ref": "#/definitions/controller.APIResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户"
+                ],
+                "summary": "部分更新用户",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "需要更新的字段",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.PatchUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功，返回更新后的数据",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.User"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误或更新失败",
+                        "schema": {
+                            "$ref": "#/definitions/controller.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "用户不存在（code=10001）",
+                        "schema": {
+                            "$ref": "#/definitions/controller.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "版本冲突（code=10003）",
+                        "schema": {
+                            "$ref": "#/definitions/controller.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/debug/tracing/sample-rate": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "运维"
+                ],
+                "summary": "查询追踪采样率",
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/controller.SampleRateResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "令牌无效",
+                        "schema": {
+                            "$ref": "#/definitions/controller.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "追踪未启用",
+                        "schema": {
+                            "$ref": "#/definitions/controller.APIResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "运维"
+                ],
+                "summary": "调整追踪采样率",
+                "parameters": [
+                    {
+                        "description": "采样率",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.SampleRateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功，返回调整后的采样率",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/controller.SampleRateResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/controller.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "令牌无效",
+                        "schema": {
+                            "$ref": "#/definitions/controller.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "追踪未启用",
+                        "schema": {
+                            "$ref": "#/definitions/controller.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "健康检查"
+                ],
+                "summary": "健康检查",
+                "responses": {
+                    "200": {
+                        "description": "服务正常",
+                        "schema": {
+                            "$ref": "#/definitions/controller.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health/detailed": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "健康检查"
+                ],
+                "summary": "详细健康状态（MySQL、Redis、下游依赖、熔断器）",
+                "responses": {
+                    "200": {
+                        "description": "各组件状态",
+                        "schema": {
+                            "$ref": "#/definitions/controller.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/liveness": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "健康检查"
+                ],
+                "summary": "存活检查",
+                "responses": {
+                    "200": {
+                        "description": "服务存活",
+                        "schema": {
+                            "$ref": "#/definitions/controller.APIResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "心跳超时（进程可能被阻塞）",
+                        "schema": {
+                            "$ref": "#/definitions/controller.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/readiness": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "健康检查"
+                ],
+                "summary": "就绪检查（MySQL、Redis、下游依赖）",
+                "responses": {
+                    "200": {
+                        "description": "服务就绪",
+                        "schema": {
+                            "$ref": "#/definitions/controller.APIResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "依赖不可用",
+                        "schema": {
+                            "$ref": "#/definitions/controller.APIResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "controller.APIResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "状态码（成功时为 200，app.responseCodeConvention 为 zero 时为 0）",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "数据字段"
+                },
+                "message": {
+                    "description": "消息提示",
+                    "type": "string"
+                },
+                "trace_id": {
+                    "description": "追踪ID（链路追踪，用于日志关联和问题排查）",
+                    "type": "string"
+                }
+            }
+        },
+        "controller.CountUsersResponse": {
+            "type": "object",
+            "properties": {
+                "by_status": {
+                    "description": "各状态的用户数",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "total": {
+                    "description": "用户总数",
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.CreateUserRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "name"
+            ],
+            "properties": {
+                "age": {
+                    "description": "用户年龄",
+                    "type": "integer",
+                    "example": 25
+                },
+                "email": {
+                    "description": "用户邮箱",
+                    "type": "string",
+                    "example": "zhangsan@example.com"
+                },
+                "name": {
+                    "description": "用户姓名",
+                    "type": "string",
+                    "example": "张三"
+                },
+                "status": {
+                    "description": "用户状态 1-正常 0-禁用",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "controller.CursorUsersResponse": {
+            "type": "object",
+            "properties": {
+                "has_more": {
+                    "description": "是否还有更多数据",
+                    "type": "boolean"
+                },
+                "list": {
+                    "description": "当前页数据",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.User"
+                    }
+                },
+                "next_cursor": {
+                    "description": "下一页游标，没有更多数据时为 0",
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.GetUserRequest": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "description": "用户ID",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "controller.ListUsersResponse": {
+            "type": "object",
+            "properties": {
+                "list": {
+                    "description": "当前页数据",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.User"
+                    }
+                },
+                "total": {
+                    "description": "总数",
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.MaintenanceRequest": {
+            "type": "object",
+            "required": [
+                "enabled"
+            ],
+            "properties": {
+                "enabled": {
+                    "description": "是否开启维护模式",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "controller.MaintenanceResponse": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "description": "是否处于维护模式",
+                    "type": "boolean"
+                }
+            }
+        },
+        "controller.PatchUserRequest": {
+            "type": "object",
+            "properties": {
+                "age": {
+                    "description": "用户年龄",
+                    "type": "integer",
+                    "example": 30
+                },
+                "email": {
+                    "description": "用户邮箱",
+                    "type": "string",
+                    "example": "zhangsan@example.com"
+                },
+                "name": {
+                    "description": "用户姓名",
+                    "type": "string",
+                    "minLength": 1,
+                    "example": "张三"
+                },
+                "status": {
+                    "description": "用户状态 1-正常 0-禁用",
+                    "type": "integer",
+                    "example": 1
+                },
+                "version": {
+                    "description": "可选，提供时启用乐观锁校验",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "controller.SampleRateRequest": {
+            "type": "object",
+            "required": [
+                "rate"
+            ],
+            "properties": {
+                "rate": {
+                    "description": "采样率：0.0-1.0，1 表示全部采样",
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0,
+                    "example": 1
+                }
+            }
+        },
+        "controller.SampleRateResponse": {
+            "type": "object",
+            "properties": {
+                "rate": {
+                    "description": "当前生效的采样率",
+                    "type": "number"
+                }
+            }
+        },
+        "controller.UpdateUserRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "id",
+                "name"
+            ],
+            "properties": {
+                "age": {
+                    "description": "用户年龄",
+                    "type": "integer",
+                    "example": 26
+                },
+                "email": {
+                    "description": "用户邮箱",
+                    "type": "string",
+                    "example": "zhangsan@example.com"
+                },
+                "id": {
+                    "description": "用户ID",
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "description": "用户姓名",
+                    "type": "string",
+                    "example": "张三"
+                },
+                "status": {
+                    "description": "用户状态 1-正常 0-禁用",
+                    "type": "integer",
+                    "example": 1
+                },
+                "version": {
+                    "description": "读取到的版本号（乐观锁）",
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "controller.UserExistsResponse": {
+            "type": "object",
+            "properties": {
+                "exists": {
+                    "description": "邮箱是否已被使用",
+                    "type": "boolean"
+                }
+            }
+        },
+        "logic.CacheWarmProgress": {
+            "type": "object",
+            "properties": {
+                "batches": {
+                    "description": "已完成的批次数",
+                    "type": "integer"
+                },
+                "error": {
+                    "description": "预热失败原因",
+                    "type": "string"
+                },
+                "finished_at": {
+                    "description": "结束时间",
+                    "type": "string"
+                },
+                "running": {
+                    "description": "是否正在预热",
+                    "type": "boolean"
+                },
+                "skipped": {
+                    "description": "跳过的用户数（序列化失败或超过缓存值大小上限）",
+                    "type": "integer"
+                },
+                "started_at": {
+                    "description": "开始时间",
+                    "type": "string"
+                },
+                "total": {
+                    "description": "开始时的用户总数",
+                    "type": "integer"
+                },
+                "warmed": {
+                    "description": "已写入缓存的用户数",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.User": {
+            "type": "object",
+            "properties": {
+                "age": {
+                    "description": "用户年龄",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "description": "创建人（由 GORM 回调根据 context 中的操作人填充）",
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "email": {
+                    "description": "用户邮箱",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "用户姓名",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "用户状态 1-正常 0-禁用",
+                    "type": "integer"
+                },
+                "tenant_id": {
+                    "description": "租户ID（多租户部署时由 context 中的租户填充，单租户时为空）",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "description": "最后修改人（由 GORM 回调根据 context 中的操作人填充）",
+                    "type": "string"
+                },
+                "version": {
+                    "description": "版本号（乐观锁，每次更新递增）",
+                    "type": "integer"
+                }
+            }
+        }
+    }
+}`
+
+// SwaggerInfo holds exported Swagger Info so clients can modify it
+var SwaggerInfo = &swag.Spec{
+	Version:          "1.0",
+	Host:             "",
+	BasePath:         "/",
+	Schemes:          []string{},
+	Title:            "Gin项目 - 用户管理API",
+	Description:      "基于 Gin、GORM 和 Redis 的用户管理 API，所有响应使用统一的 APIResponse 格式（包含 trace_id）",
+	InfoInstanceName: "swagger",
+	SwaggerTemplate:  docTemplate,
+	LeftDelim:        "{{",
+	RightDelim:       "}}",
+}
+
+func init() {
+	swag.Register(SwaggerInfo.InstanceName(), SwaggerInfo)
+}
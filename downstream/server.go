@@ -0,0 +1,85 @@
+// Package downstream 内置的演示下游服务（模拟服务C）
+// 实现 /api/calculate 和 /api/process，响应遵循 service.APIResponse 约定（code 为 0 表示成功），
+// 通过 app.demoDownstream.enabled 开启后在独立端口启动，使完整的调用链路无需外部服务即可运行
+package downstream
+
+import (
+	"net/http"
+	"strings"
+
+	"gin-project/middleware"
+	"gin-project/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// calculateRequest 计算接口请求体
+type calculateRequest struct {
+	Number int `json:"number"`
+}
+
+// processRequest 处理接口请求体
+type processRequest struct {
+	Content string `json:"content" binding:"required"`
+}
+
+// SetupRouter 配置演示下游服务路由
+// 使用追踪中间件，从请求头中提取上游 trace 上下文，与主服务的 span 串联为同一条链路
+func SetupRouter() *gin.Engine {
+	r := gin.New()
+	r.Use(middleware.RecoveryMiddleware())
+	r.Use(middleware.TracingMiddleware())
+
+	api := r.Group("/api")
+	{
+		api.POST("/calculate", calculate)
+		api.POST("/process", process)
+	}
+
+	return r
+}
+
+// calculate 计算接口：返回输入数字的平方
+func calculate(c *gin.Context) {
+	var req calculateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		fail(c, "请求参数格式错误: "+err.Error())
+		return
+	}
+
+	succeed(c, map[string]interface{}{
+		"number": req.Number,
+		"result": req.Number * req.Number,
+	})
+}
+
+// process 处理接口：返回转换为大写的内容
+func process(c *gin.Context) {
+	var req processRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		fail(c, "请求参数格式错误: "+err.Error())
+		return
+	}
+
+	succeed(c, map[string]interface{}{
+		"content": req.Content,
+		"result":  strings.ToUpper(req.Content),
+	})
+}
+
+// succeed 成功响应（code 为 0）
+func succeed(c *gin.Context, data map[string]interface{}) {
+	c.JSON(http.StatusOK, service.APIResponse{
+		Code:    0,
+		Message: "success",
+		Data:    data,
+	})
+}
+
+// fail 参数错误响应（HTTP 状态码 200，业务错误码 400，由调用方解析为业务错误）
+func fail(c *gin.Context, message string) {
+	c.JSON(http.StatusOK, service.APIResponse{
+		Code:    http.StatusBadRequest,
+		Message: message,
+	})
+}
@@ -0,0 +1,49 @@
+package downstream
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"gin-project/config"
+	"gin-project/middleware"
+	"gin-project/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+	middleware.InitTracing(&config.Config{})
+}
+
+func TestServiceCLoopback(t *testing.T) {
+	server := httptest.NewServer(SetupRouter())
+	defer server.Close()
+	s := service.NewServiceC(server.URL)
+	ctx := context.Background()
+
+	calculated, err := s.CalculateTyped(ctx, 7)
+	if err != nil {
+		t.Fatalf("CalculateTyped: %v", err)
+	}
+	if calculated.Number != 7 || calculated.Result != 49 {
+		t.Errorf("CalculateTyped = %+v, want 7 -> 49", calculated)
+	}
+
+	processed, err := s.ProcessTyped(ctx, "hello")
+	if err != nil {
+		t.Fatalf("ProcessTyped: %v", err)
+	}
+	if processed.Content != "hello" || processed.Result != "HELLO" {
+		t.Errorf("ProcessTyped = %+v, want hello -> HELLO", processed)
+	}
+
+	// 缺少 content 时返回业务错误
+	_, err = s.ProcessTyped(ctx, "")
+	var bizErr *service.BusinessError
+	if !errors.As(err, &bizErr) || bizErr.Code != 400 {
+		t.Errorf("空内容 err = %v, want BusinessError 400", err)
+	}
+}
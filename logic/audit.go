@@ -0,0 +1,39 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+
+	"gin-project/model"
+
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// 审计操作类型
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+)
+
+// AuditEntityUser 用户实体的审计类型
+const AuditEntityUser = "user"
+
+// RecordAudit 记录一条审计日志
+// tx 必须是写操作所在的事务，审计记录与业务数据一起提交或回滚
+func RecordAudit(ctx context.Context, tx *gorm.DB, action, entity string, entityID uint, actor string) error {
+	audit := &model.AuditLog{
+		Action:   action,
+		Entity:   entity,
+		EntityID: entityID,
+		Actor:    actor,
+	}
+	if spanCtx := trace.SpanFromContext(ctx).SpanContext(); spanCtx.IsValid() {
+		audit.TraceID = spanCtx.TraceID().String()
+	}
+
+	if err := tx.WithContext(ctx).Create(audit).Error; err != nil {
+		return fmt.Errorf("记录审计日志失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package logic
+
+import (
+	"context"
+	"testing"
+
+	"gin-project/model"
+	"gin-project/pkg"
+)
+
+func TestCreateUserRecordsAudit(t *testing.T) {
+	useConfig(t, nil)
+	db, _ := setupStores(t)
+
+	ctx := pkg.WithActor(context.Background(), "admin")
+	user := &model.User{Name: "alice", Email: "alice@example.com"}
+	if err := CreateUser(ctx, user); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	var audits []model.AuditLog
+	db.Find(&audits)
+	if len(audits) != 1 {
+		t.Fatalf("审计记录数 = %d, want 1", len(audits))
+	}
+	got := audits[0]
+	if got.Action != AuditActionCreate || got.Entity != AuditEntityUser || got.EntityID != user.ID || got.Actor != "admin" {
+		t.Errorf("审计记录 = %+v", got)
+	}
+}
+
+func TestUpdateUserAuditsAnonymousActor(t *testing.T) {
+	useConfig(t, nil)
+	db, mr := setupStores(t)
+	user := createUser(t, db, mr, "alice", 1)
+
+	user.Name = "bob"
+	if _, err := UpdateUser(context.Background(), user); err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+
+	var audit model.AuditLog
+	if err := db.Where("action = ?", AuditActionUpdate).First(&audit).Error; err != nil {
+		t.Fatalf("未找到更新的审计记录: %v", err)
+	}
+	if audit.EntityID != user.ID || audit.Actor != pkg.AnonymousActor {
+		t.Errorf("审计记录 = %+v", audit)
+	}
+}
+
+func TestAuditRollsBackWithWrite(t *testing.T) {
+	useConfig(t, nil)
+	db, mr := setupStores(t)
+
+	// 版本冲突：用户未更新，也不写审计记录
+	user := createUser(t, db, mr, "alice", 1)
+	stale := *user
+	stale.Name = "bob"
+	stale.Version = user.Version + 1
+	if _, err := UpdateUser(context.Background(), &stale); err != ErrVersionConflict {
+		t.Fatalf("err = %v, want ErrVersionConflict", err)
+	}
+	var count int64
+	db.Model(&model.AuditLog{}).Count(&count)
+	if count != 0 {
+		t.Errorf("更新失败后审计记录数 = %d, want 0", count)
+	}
+
+	// 审计写入失败：用户插入随事务回滚
+	if err := db.Migrator().DropTable(&model.AuditLog{}); err != nil {
+		t.Fatal(err)
+	}
+	err := CreateUser(context.Background(), &model.User{Name: "carol", Email: "carol@example.com"})
+	if err == nil {
+		t.Fatal("审计表不存在时 CreateUser 应失败")
+	}
+	db.Model(&model.User{}).Where("email = ?", "carol@example.com").Count(&count)
+	if count != 0 {
+		t.Errorf("审计失败后仍写入了用户，count = %d", count)
+	}
+}
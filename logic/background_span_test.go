@@ -0,0 +1,41 @@
+package logic
+
+import (
+	"context"
+	"testing"
+
+	"gin-project/pkg"
+)
+
+func TestCacheFillSpanLinksToRequest(t *testing.T) {
+	db, mr := setupStores(t)
+	user := createUser(t, db, mr, "linked", 1)
+	mr.FlushAll()
+	spanRecorder.Reset()
+	t.Cleanup(spanRecorder.Reset)
+
+	ctx, request := pkg.StartSpan(context.Background(), pkg.Tracer, "request")
+	if _, err := GetUserByID(ctx, user.ID); err != nil {
+		t.Fatalf("GetUserByID: %v", err)
+	}
+	request.End()
+	drainBackground(t)
+
+	if !mr.Exists(userCacheKey("", user.ID)) {
+		t.Fatal("后台缓存写入未执行")
+	}
+	for _, span := range spanRecorder.Ended() {
+		if span.Name() != "background.task" {
+			continue
+		}
+		links := span.Links()
+		if len(links) != 1 || links[0].SpanContext.SpanID() != request.SpanContext().SpanID() {
+			t.Fatalf("后台缓存写入 span 应链接到请求 span，links = %v", links)
+		}
+		if span.Parent().TraceID() == request.SpanContext().TraceID() {
+			t.Error("后台 span 应为新的根 span，不应延续请求 trace")
+		}
+		return
+	}
+	t.Fatal("未找到后台任务 span")
+}
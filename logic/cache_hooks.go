@@ -0,0 +1,161 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gin-project/database"
+	"gin-project/model"
+	"gin-project/pkg"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// userCacheEvictCallback 用户缓存失效回调名称
+const userCacheEvictCallback = "app:evict_user_cache"
+
+// RegisterUserCacheHooks 注册 GORM 回调：users 表的创建、更新、删除成功后自动删除对应的用户缓存和邮箱负缓存
+// 集中失效，避免新增的写路径（包括直接使用 GORM 的写入）遗漏清除缓存；
+// 回调在事务中执行，Redis 删除提交到后台任务异步完成，不阻塞事务（Redis 不可用时跳过，仅清除本地缓存）；
+// 各写函数提交后仍会再次清除缓存，避免并发读取在提交前回填旧数据
+func RegisterUserCacheHooks(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Create().After("gorm:create").Register(userCacheEvictCallback, evictUserCache); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("gorm:update").Register(userCacheEvictCallback, evictUserCache); err != nil {
+		return err
+	}
+	return callbacks.Delete().After("gorm:delete").Register(userCacheEvictCallback, evictUserCache)
+}
+
+// evictUserCache 删除本次写入涉及的用户缓存键：本地缓存立即清除，Redis 缓存键在后台任务中删除
+func evictUserCache(db *gorm.DB) {
+	stmt := db.Statement
+	if db.Error != nil || db.RowsAffected == 0 || stmt.Schema == nil || stmt.Schema.Table != (model.User{}).TableName() {
+		return
+	}
+
+	// 多租户部署下缓存键按 context 中的租户隔离
+	tenantID, _ := pkg.TenantFromContext(stmt.Context)
+	ids, emails := writtenUsers(stmt)
+	keys := make([]string, 0, len(ids)+len(emails)+1)
+	for _, id := range ids {
+		keys = append(keys, userCacheKey(tenantID, id))
+		invalidateLocalUser(id)
+	}
+	for _, email := range emails {
+		keys = append(keys, fmt.Sprintf(UserEmailAbsentKey, email))
+	}
+	if len(keys) == 0 || !database.RedisHealthy() {
+		return
+	}
+	keys = append(keys, userCountCacheKey(tenantID))
+
+	submitted := pkg.Background().Submit(stmt.Context, func(ctx context.Context) {
+		if err := database.RedisClient.Del(ctx, keys...).Err(); err != nil {
+			pkg.LoggerFromContext(ctx).Warn("自动清除用户缓存失败", "keys", keys, "error", err)
+		}
+	})
+	if !submitted {
+		pkg.LoggerFromContext(stmt.Context).Warn("自动清除用户缓存任务被丢弃", "keys", keys)
+	}
+}
+
+// writtenUsers 提取本次写入涉及的用户ID和邮箱
+// 来源：写入的模型（结构体或切片）、更新的列值（map），以及 WHERE 中的主键条件（如 Where("id = ?", id)）
+func writtenUsers(stmt *gorm.Statement) ([]uint, []string) {
+	var ids []uint
+	var emails []string
+	addID := func(value interface{}) {
+		if id, ok := toUint(value); ok && id > 0 {
+			ids = append(ids, id)
+		}
+	}
+	addEmail := func(value interface{}) {
+		if email, ok := value.(string); ok && email != "" {
+			emails = append(emails, email)
+		}
+	}
+
+	// 写入的模型
+	collect := func(rv reflect.Value) {
+		for rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				return
+			}
+			rv = rv.Elem()
+		}
+		if user, ok := rv.Interface().(model.User); ok {
+			addID(user.ID)
+			addEmail(user.Email)
+		}
+	}
+	if rv := stmt.ReflectValue; rv.IsValid() {
+		switch rv.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < rv.Len(); i++ {
+				collect(rv.Index(i))
+			}
+		case reflect.Struct:
+			collect(rv)
+		}
+	}
+
+	// 更新的列值
+	if values, ok := stmt.Dest.(map[string]interface{}); ok {
+		addEmail(values["email"])
+	}
+
+	// WHERE 中的主键条件
+	if where, ok := stmt.Clauses["WHERE"].Expression.(clause.Where); ok {
+		for _, expr := range where.Exprs {
+			switch e := expr.(type) {
+			case clause.Eq:
+				if isIDColumn(e.Column) {
+					addID(e.Value)
+				}
+			case clause.IN:
+				if isIDColumn(e.Column) {
+					for _, v := range e.Values {
+						addID(v)
+					}
+				}
+			case clause.Expr:
+				if len(e.Vars) == 1 && strings.ReplaceAll(e.SQL, " ", "") == "id=?" {
+					addID(e.Vars[0])
+				}
+			}
+		}
+	}
+
+	return ids, emails
+}
+
+// isIDColumn 判断条件列是否为主键 id
+func isIDColumn(column interface{}) bool {
+	switch c := column.(type) {
+	case clause.Column:
+		return c.Name == clause.PrimaryKey || c.Name == "id"
+	case string:
+		return c == "id"
+	}
+	return false
+}
+
+// toUint 将主键值转换为 uint
+func toUint(value interface{}) (uint, bool) {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return uint(rv.Uint()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if rv.Int() >= 0 {
+			return uint(rv.Int()), true
+		}
+	}
+	return 0, false
+}
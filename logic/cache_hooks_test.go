@@ -0,0 +1,51 @@
+package logic
+
+import (
+	"fmt"
+	"testing"
+
+	"gin-project/model"
+)
+
+func TestUserWritesEvictCacheInBackground(t *testing.T) {
+	db, mr := setupStores(t)
+	user := createUser(t, db, mr, "alice", 1)
+
+	key := fmt.Sprintf(UserCacheKey, user.ID)
+	for _, k := range []string{key, UserCountCacheKey, "unrelated"} {
+		if err := mr.Set(k, "cached"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := db.Model(&model.User{}).Where("id = ?", user.ID).Update("name", "bob").Error; err != nil {
+		t.Fatalf("更新用户失败: %v", err)
+	}
+	waitFor(t, "更新后清除用户缓存", func() bool { return !mr.Exists(key) && !mr.Exists(UserCountCacheKey) })
+	if !mr.Exists("unrelated") {
+		t.Error("不应清除无关的缓存键")
+	}
+
+	if err := mr.Set(key, "cached"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Delete(&model.User{}, user.ID).Error; err != nil {
+		t.Fatalf("删除用户失败: %v", err)
+	}
+	waitFor(t, "删除后清除用户缓存", func() bool { return !mr.Exists(key) })
+}
+
+func TestUserWriteWithoutRowsKeepsCache(t *testing.T) {
+	db, mr := setupStores(t)
+
+	key := fmt.Sprintf(UserCacheKey, 42)
+	if err := mr.Set(key, "cached"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&model.User{}).Where("id = ?", 42).Update("name", "nobody").Error; err != nil {
+		t.Fatalf("更新失败: %v", err)
+	}
+	if !mr.Exists(key) {
+		t.Error("未影响任何行的写入不应清除缓存")
+	}
+}
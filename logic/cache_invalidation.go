@@ -0,0 +1,57 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"gin-project/database"
+)
+
+// UserInvalidationChannel 用户缓存失效通知频道
+// 写操作发布被失效的缓存键，所有实例订阅后删除各自的进程内缓存条目
+const UserInvalidationChannel = "cache:invalidate:user"
+
+// publishUserInvalidation 失效本实例的进程内缓存，并通知其他实例
+// 仅在启用进程内缓存时发布（使用带追踪的 Redis 客户端，自动追踪）
+func publishUserInvalidation(ctx context.Context, id uint) {
+	if userLocalCache() == nil {
+		return
+	}
+	invalidateLocalUser(id)
+
+	cacheKey := fmt.Sprintf(UserCacheKey, id)
+	if err := database.RedisClient.Publish(ctx, UserInvalidationChannel, cacheKey).Err(); err != nil {
+		log.Printf("发布缓存失效通知失败: %s, %v", cacheKey, err)
+	}
+}
+
+// SubscribeUserInvalidation 订阅用户缓存失效通知，收到后删除进程内缓存条目
+// 仅在启用进程内缓存时订阅；返回的函数用于取消订阅，返回前等待正在处理的通知完成
+func SubscribeUserInvalidation(ctx context.Context) func() {
+	if userLocalCache() == nil {
+		return func() {}
+	}
+
+	pubsub := database.RedisClient.Subscribe(ctx, UserInvalidationChannel)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for msg := range pubsub.Channel() {
+			var id uint
+			if _, err := fmt.Sscanf(msg.Payload, UserCacheKey, &id); err != nil {
+				log.Printf("无法解析缓存失效通知: %s", msg.Payload)
+				continue
+			}
+			invalidateLocalUser(id)
+		}
+	}()
+	log.Printf("已订阅缓存失效通知: %s", UserInvalidationChannel)
+
+	return func() {
+		if err := pubsub.Close(); err != nil {
+			log.Printf("取消订阅缓存失效通知失败: %v", err)
+		}
+		<-done
+	}
+}
@@ -0,0 +1,62 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"gin-project/model"
+)
+
+func TestUserInvalidationAcrossSubscribers(t *testing.T) {
+	useConfig(t, localCacheConfig())
+	_, mr := setupStores(t)
+
+	// 两个实例各自订阅失效通知
+	for i := 0; i < 2; i++ {
+		unsubscribe := SubscribeUserInvalidation(context.Background())
+		t.Cleanup(unsubscribe)
+	}
+	waitFor(t, "两个订阅者就绪", func() bool {
+		return mr.PubSubNumSub(UserInvalidationChannel)[UserInvalidationChannel] == 2
+	})
+
+	setLocalUser(&model.User{ID: 1, Name: "alice"})
+	setLocalUser(&model.User{ID: 2, Name: "bob"})
+
+	// 其他实例更新用户 1 后发布失效通知，本实例的订阅者删除进程内缓存条目
+	if got := mr.Publish(UserInvalidationChannel, fmt.Sprintf(UserCacheKey, 1)); got != 2 {
+		t.Fatalf("通知送达 %d 个订阅者, want 2", got)
+	}
+	waitFor(t, "删除收到通知的用户", func() bool {
+		_, ok := getLocalUser(1)
+		return !ok
+	})
+	if _, ok := getLocalUser(2); !ok {
+		t.Error("未收到通知的用户不应被删除")
+	}
+
+	// 无法解析的通知被忽略
+	mr.Publish(UserInvalidationChannel, "garbage")
+	setLocalUser(&model.User{ID: 1, Name: "alice"})
+	if _, ok := getLocalUser(1); !ok {
+		t.Error("无法解析的通知不应影响缓存")
+	}
+}
+
+func TestPublishUserInvalidation(t *testing.T) {
+	useConfig(t, localCacheConfig())
+	_, mr := setupStores(t)
+	unsubscribe := SubscribeUserInvalidation(context.Background())
+	t.Cleanup(unsubscribe)
+	waitFor(t, "订阅者就绪", func() bool {
+		return mr.PubSubNumSub(UserInvalidationChannel)[UserInvalidationChannel] == 1
+	})
+
+	setLocalUser(&model.User{ID: 7, Name: "alice"})
+	publishUserInvalidation(context.Background(), 7)
+
+	if _, ok := getLocalUser(7); ok {
+		t.Error("发布方应立即删除本实例的进程内缓存")
+	}
+}
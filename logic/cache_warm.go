@@ -0,0 +1,160 @@
+package logic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"gin-project/config"
+	"gin-project/database"
+	"gin-project/model"
+	"gin-project/pkg"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultCacheWarmBatchSize 缓存预热每批加载的用户数
+	DefaultCacheWarmBatchSize = 500
+	// DefaultCacheWarmTTLJitter 缓存预热写入时附加的最大随机过期时间，避免预热的键同时过期
+	DefaultCacheWarmTTLJitter = 5 * time.Minute
+)
+
+// CacheWarmProgress 用户缓存预热进度
+type CacheWarmProgress struct {
+	Running    bool       `json:"running"`               // 是否正在预热
+	Total      int64      `json:"total"`                 // 开始时的用户总数
+	Warmed     int64      `json:"warmed"`                // 已写入缓存的用户数
+	Skipped    int64      `json:"skipped"`               // 跳过的用户数（序列化失败或超过缓存值大小上限）
+	Batches    int        `json:"batches"`               // 已完成的批次数
+	StartedAt  *time.Time `json:"started_at,omitempty"`  // 开始时间
+	FinishedAt *time.Time `json:"finished_at,omitempty"` // 结束时间
+	Error      string     `json:"error,omitempty"`       // 预热失败原因
+}
+
+var (
+	cacheWarmMu       sync.Mutex
+	cacheWarmProgress CacheWarmProgress
+)
+
+// cacheWarmBatchSize 获取预热批次大小（cache.warm.batchSize，未配置时使用默认值）
+func cacheWarmBatchSize() int {
+	if config.Cfg != nil && config.Cfg.Cache.Warm.BatchSize > 0 {
+		return config.Cfg.Cache.Warm.BatchSize
+	}
+	return DefaultCacheWarmBatchSize
+}
+
+// cacheWarmTTL 获取预热写入的过期时间：UserCacheTTL 加上随机抖动（cache.warm.ttlJitter 秒）
+func cacheWarmTTL() time.Duration {
+	jitter := DefaultCacheWarmTTLJitter
+	if config.Cfg != nil && config.Cfg.Cache.Warm.TTLJitter > 0 {
+		jitter = time.Duration(config.Cfg.Cache.Warm.TTLJitter) * time.Second
+	}
+	return UserCacheTTL + time.Duration(rand.Int63n(int64(jitter)))
+}
+
+// WarmUserCache 启动用户缓存预热，返回当前进度
+// 预热在有界后台执行器中执行：按主键分批加载用户，每批通过 Redis Pipeline 写入；
+// 已有预热在执行时不会重复启动，直接返回其进度
+func WarmUserCache(ctx context.Context) (CacheWarmProgress, error) {
+	cacheWarmMu.Lock()
+	defer cacheWarmMu.Unlock()
+
+	if cacheWarmProgress.Running {
+		return cacheWarmProgress, nil
+	}
+
+	total, err := userRepo.Count(ctx)
+	if err != nil {
+		return cacheWarmProgress, err
+	}
+
+	startedAt := time.Now()
+	progress := CacheWarmProgress{Running: true, Total: total, StartedAt: &startedAt}
+	if !pkg.Background().Submit(ctx, warmUserCache) {
+		return cacheWarmProgress, fmt.Errorf("后台任务队列已满，请稍后重试")
+	}
+	cacheWarmProgress = progress
+	return cacheWarmProgress, nil
+}
+
+// warmUserCache 分批加载用户并写入缓存，直到没有更多数据或出错
+func warmUserCache(ctx context.Context) {
+	logger := pkg.LoggerFromContext(ctx)
+	batchSize := cacheWarmBatchSize()
+
+	var cursor uint
+	var warmErr error
+	for {
+		users, err := userRepo.List(ctx, orderByID, func(db *gorm.DB) *gorm.DB {
+			return db.Where("id > ?", cursor).Limit(batchSize)
+		})
+		if err != nil {
+			warmErr = err
+			break
+		}
+		if len(users) == 0 {
+			break
+		}
+
+		warmed, skipped, err := cacheUserBatch(ctx, users)
+		if err != nil {
+			warmErr = err
+			break
+		}
+		cursor = users[len(users)-1].ID
+
+		cacheWarmMu.Lock()
+		cacheWarmProgress.Warmed += warmed
+		cacheWarmProgress.Skipped += skipped
+		cacheWarmProgress.Batches++
+		cacheWarmMu.Unlock()
+
+		if len(users) < batchSize {
+			break
+		}
+	}
+
+	finishedAt := time.Now()
+	cacheWarmMu.Lock()
+	cacheWarmProgress.Running = false
+	cacheWarmProgress.FinishedAt = &finishedAt
+	if warmErr != nil {
+		cacheWarmProgress.Error = warmErr.Error()
+	}
+	progress := cacheWarmProgress
+	cacheWarmMu.Unlock()
+
+	if warmErr != nil {
+		logger.Warn("用户缓存预热失败", "warmed", progress.Warmed, "error", warmErr)
+		return
+	}
+	logger.Info("用户缓存预热完成", "warmed", progress.Warmed, "skipped", progress.Skipped,
+		"batches", progress.Batches, "elapsed", finishedAt.Sub(*progress.StartedAt))
+}
+
+// cacheUserBatch 通过 Pipeline 将一批用户写入缓存，超过缓存值大小上限的用户跳过
+func cacheUserBatch(ctx context.Context, users []model.User) (warmed, skipped int64, err error) {
+	pipe := database.RedisClient.Pipeline()
+	for i := range users {
+		cacheKey := userCacheKey(users[i].TenantID, users[i].ID)
+		jsonData, err := json.Marshal(&users[i])
+		if err != nil || cacheValueTooLarge(ctx, cacheKey, len(jsonData)) {
+			skipped++
+			continue
+		}
+		pipe.Set(ctx, cacheKey, string(jsonData), cacheWarmTTL())
+		warmed++
+	}
+	if warmed == 0 {
+		return 0, skipped, nil
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, skipped, err
+	}
+	return warmed, skipped, nil
+}
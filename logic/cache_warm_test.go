@@ -0,0 +1,53 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"gin-project/config"
+)
+
+// currentCacheWarmProgress 读取当前预热进度
+func currentCacheWarmProgress() CacheWarmProgress {
+	cacheWarmMu.Lock()
+	defer cacheWarmMu.Unlock()
+	return cacheWarmProgress
+}
+
+func TestWarmUserCache(t *testing.T) {
+	useConfig(t, &config.Config{Cache: config.Cache{Warm: config.CacheWarm{BatchSize: 2, TTLJitter: 10}}})
+	db, mr := setupStores(t)
+	ids := make([]uint, 5)
+	for i := range ids {
+		ids[i] = createUser(t, db, mr, fmt.Sprintf("warm%d", i), 1).ID
+	}
+	mr.FlushAll()
+
+	progress, err := WarmUserCache(context.Background())
+	if err != nil {
+		t.Fatalf("WarmUserCache: %v", err)
+	}
+	if !progress.Running || progress.Total != int64(len(ids)) || progress.StartedAt == nil {
+		t.Errorf("启动时进度 = %+v", progress)
+	}
+
+	waitFor(t, "预热完成", func() bool { return !currentCacheWarmProgress().Running })
+	progress = currentCacheWarmProgress()
+	if progress.Error != "" || progress.Warmed != int64(len(ids)) || progress.Batches != 3 || progress.FinishedAt == nil {
+		t.Fatalf("完成时进度 = %+v, want warmed=5 batches=3", progress)
+	}
+
+	for _, id := range ids {
+		key := userCacheKey("", id)
+		if !mr.Exists(key) {
+			t.Errorf("缺少缓存键 %s", key)
+			continue
+		}
+		// 过期时间为 UserCacheTTL 加随机抖动
+		if ttl := mr.TTL(key); ttl < UserCacheTTL || ttl >= UserCacheTTL+10*time.Second {
+			t.Errorf("%s 过期时间 = %s", key, ttl)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package logic
+
+import (
+	"context"
+	"strconv"
+
+	"gin-project/config"
+	"gin-project/model"
+	"gin-project/pkg"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
+)
+
+// userLoads 合并同一用户的并发查询
+var userLoads singleflight.Group
+
+// coalesceEnabled 是否合并并发查询（cache.coalesce）
+func coalesceEnabled() bool {
+	return config.Cfg != nil && config.Cfg.Cache.Coalesce
+}
+
+// coalesceGetUser 合并同一用户ID的并发查询：同时到达的 N 个请求只执行一次查询，共享结果
+// 缓存击穿时避免同一用户的请求同时回源数据库；查询的 span 位于首个请求的链路下，
+// 其他请求在当前 span 上记录 user.coalesced 事件。查询使用不随首个请求取消的 context，
+// 避免首个请求断开导致其他请求一起失败
+func coalesceGetUser(ctx context.Context, id uint) (*model.User, error) {
+	// 按租户隔离，不同租户对同一 ID 的查询不合并
+	tenantID, _ := pkg.TenantFromContext(ctx)
+	value, err, shared := userLoads.Do(tenantID+":"+strconv.FormatUint(uint64(id), 10), func() (interface{}, error) {
+		return getUserByID(context.WithoutCancel(ctx), id)
+	})
+	if shared {
+		trace.SpanFromContext(ctx).AddEvent("user.coalesced", trace.WithAttributes(attribute.Int("user.id", int(id))))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 返回副本，避免共享结果的请求之间相互修改
+	user := *value.(*model.User)
+	return &user, nil
+}
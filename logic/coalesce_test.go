@@ -0,0 +1,51 @@
+package logic
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"gin-project/config"
+	"gin-project/model"
+)
+
+func TestCoalesceQueriesDatabaseOnce(t *testing.T) {
+	useConfig(t, &config.Config{Cache: config.Cache{Coalesce: true}})
+	db, mr := setupStores(t)
+	user := createUser(t, db, mr, "stampede", 1)
+	mr.FlushAll()
+
+	gate := make(chan struct{})
+	queries := countUserQueries(t, db, gate)
+
+	const n = 20
+	var wg sync.WaitGroup
+	results := make([]*model.User, n)
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = GetUserByID(context.Background(), user.ID)
+		}(i)
+	}
+
+	// 首个查询阻塞在 gate 上，其余请求在此期间到达并等待共享结果
+	waitFor(t, "首个数据库查询", func() bool { return queries.Load() == 1 })
+	time.Sleep(50 * time.Millisecond)
+	close(gate)
+	wg.Wait()
+
+	if got := queries.Load(); got != 1 {
+		t.Errorf("%d 个并发查询访问数据库 %d 次, want 1", n, got)
+	}
+	for i := range results {
+		if errs[i] != nil || results[i] == nil || results[i].Name != "stampede" {
+			t.Fatalf("请求 %d = %+v, %v", i, results[i], errs[i])
+		}
+		if i > 0 && results[i] == results[0] {
+			t.Fatal("共享结果的请求应各自返回副本")
+		}
+	}
+}
@@ -1,9 +1,63 @@
 package logic
 
-import "time"
+import (
+	"context"
+	"time"
+
+	"gin-project/config"
+	"gin-project/pkg"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
 
 const (
-	UserCacheKey = "user:%d"        // 用户缓存键格式
-	UserCacheTTL = 30 * time.Minute // 用户缓存过期时间
+	UserCacheKey       = "user:%d"           // 用户缓存键格式
+	TenantUserCacheKey = "tenant:%s:user:%d" // 多租户部署下的用户缓存键格式（按租户隔离）
+	UserCacheTTL       = 30 * time.Minute    // 用户缓存过期时间
+
+	UserTombstone    = "<not_found>"    // 用户不存在时写入缓存的占位值（防止缓存穿透）
+	UserTombstoneTTL = 60 * time.Second // 占位值过期时间，应较短，避免新建用户后仍被判定为不存在
+
+	UserCountCacheKey        = "user:count"           // 用户统计缓存键
+	TenantUserCountCacheKey  = "tenant:%s:user:count" // 多租户部署下的用户统计缓存键格式
+	DefaultUserCountCacheTTL = 60 * time.Second       // 用户统计缓存默认过期时间
+
+	DefaultMaxCacheValueSize = 64 * 1024 // 单个缓存值的默认最大字节数
+
+	UserEmailAbsentKey = "user:email:absent:%s" // 邮箱未被使用的负缓存键格式
+	UserEmailAbsentTTL = 30 * time.Second       // 邮箱负缓存过期时间，应较短，抑制枚举请求对数据库的压力
 )
 
+// maxCacheValueSize 获取单个缓存值的最大字节数（cache.maxValueSize，未配置时使用默认值）
+func maxCacheValueSize() int {
+	if config.Cfg != nil && config.Cfg.Cache.MaxValueSize > 0 {
+		return config.Cfg.Cache.MaxValueSize
+	}
+	return DefaultMaxCacheValueSize
+}
+
+// cacheValueTooLarge 判断序列化后的缓存值是否超过上限，超过时记录日志和 span 事件
+// 调用方应跳过写入缓存，避免过大的值占用 Redis 内存
+func cacheValueTooLarge(ctx context.Context, cacheKey string, size int) bool {
+	limit := maxCacheValueSize()
+	if size <= limit {
+		return false
+	}
+
+	pkg.LoggerFromContext(ctx).Debug("缓存值超过大小上限，跳过缓存", "key", cacheKey, "size", size, "limit", limit)
+	trace.SpanFromContext(ctx).AddEvent("cache.skip_oversized", trace.WithAttributes(
+		attribute.String("cache.key", cacheKey),
+		attribute.Int("cache.value_size", size),
+		attribute.Int("cache.max_value_size", limit),
+	))
+	return true
+}
+
+// userCountCacheTTL 获取用户统计缓存过期时间（cache.userCountTTL，未配置时使用默认值）
+func userCountCacheTTL() time.Duration {
+	if config.Cfg != nil && config.Cfg.Cache.UserCountTTL > 0 {
+		return time.Duration(config.Cfg.Cache.UserCountTTL) * time.Second
+	}
+	return DefaultUserCountCacheTTL
+}
@@ -0,0 +1,49 @@
+package logic
+
+import (
+	"context"
+	"testing"
+
+	"gin-project/config"
+)
+
+func TestGetUserByIDSkipsOversizedCacheValue(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxSize    int
+		wantCached bool
+	}{
+		{"未超过默认上限", 0, true},
+		{"超过上限", 16, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useConfig(t, &config.Config{Cache: config.Cache{MaxValueSize: tt.maxSize}})
+			db, mr := setupStores(t)
+			user := createUser(t, db, mr, "alice", 1)
+
+			if _, err := GetUserByID(context.Background(), user.ID); err != nil {
+				t.Fatalf("GetUserByID: %v", err)
+			}
+			drainBackground(t)
+			if cached := mr.Exists(userCacheKey("", user.ID)); cached != tt.wantCached {
+				t.Errorf("缓存是否写入 = %v, want %v", cached, tt.wantCached)
+			}
+		})
+	}
+}
+
+func TestCacheValueTooLarge(t *testing.T) {
+	useConfig(t, &config.Config{Cache: config.Cache{MaxValueSize: 10}})
+	if cacheValueTooLarge(context.Background(), "k", 10) {
+		t.Error("等于上限时不应跳过")
+	}
+	if !cacheValueTooLarge(context.Background(), "k", 11) {
+		t.Error("超过上限时应跳过")
+	}
+
+	useConfig(t, nil)
+	if cacheValueTooLarge(context.Background(), "k", DefaultMaxCacheValueSize) {
+		t.Error("未配置时使用默认上限")
+	}
+}
@@ -0,0 +1,60 @@
+package logic
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gin-project/model"
+)
+
+// UserEventType 用户生命周期事件类型
+type UserEventType string
+
+const (
+	UserCreated UserEventType = "user.created" // 用户已创建
+	UserUpdated UserEventType = "user.updated" // 用户已更新（全量或部分更新）
+)
+
+// UserEvent 用户生命周期事件，写操作成功提交后发布
+type UserEvent struct {
+	Type       UserEventType `json:"type"`
+	User       model.User    `json:"user"`
+	Actor      string        `json:"actor"`
+	OccurredAt time.Time     `json:"occurred_at"`
+}
+
+// UserEventHandler 用户事件处理函数，在发布方的请求协程中同步调用，耗时操作应自行异步执行
+type UserEventHandler func(ctx context.Context, event UserEvent)
+
+var (
+	userEventMu       sync.RWMutex
+	userEventHandlers []UserEventHandler
+)
+
+// OnUserEvent 订阅用户生命周期事件
+func OnUserEvent(handler UserEventHandler) {
+	userEventMu.Lock()
+	defer userEventMu.Unlock()
+	userEventHandlers = append(userEventHandlers, handler)
+}
+
+// publishUserEvent 发布用户生命周期事件（写操作事务提交后调用）
+func publishUserEvent(ctx context.Context, eventType UserEventType, user *model.User, actor string) {
+	userEventMu.RLock()
+	handlers := userEventHandlers
+	userEventMu.RUnlock()
+	if len(handlers) == 0 {
+		return
+	}
+
+	event := UserEvent{
+		Type:       eventType,
+		User:       *user,
+		Actor:      actor,
+		OccurredAt: time.Now(),
+	}
+	for _, handler := range handlers {
+		handler(ctx, event)
+	}
+}
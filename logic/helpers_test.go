@@ -0,0 +1,103 @@
+package logic
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"gin-project/config"
+	"gin-project/database/dbtest"
+	"gin-project/model"
+	"gin-project/pkg"
+
+	"github.com/alicebob/miniredis/v2"
+	"gorm.io/gorm"
+)
+
+// setupStores 使用内存 SQLite 和 miniredis 作为全局 DB/Redis，并注册用户缓存失效回调
+// 测试结束时先等待已提交的后台任务执行完毕，再恢复全局 DB/Redis
+func setupStores(t testing.TB) (*gorm.DB, *miniredis.Miniredis) {
+	t.Helper()
+	db := dbtest.Open(t, &model.User{}, &model.AuditLog{})
+	if err := RegisterUserCacheHooks(db); err != nil {
+		t.Fatalf("RegisterUserCacheHooks: %v", err)
+	}
+	mr := dbtest.Redis(t)
+	t.Cleanup(func() { drainBackground(t) })
+	return db, mr
+}
+
+// drainBackground 等待全局后台执行器中已提交的任务执行完毕
+// 向每个 worker 提交一个屏障任务，所有屏障同时运行时，之前出队的任务都已执行完毕
+func drainBackground(t testing.TB) {
+	t.Helper()
+	var arrived sync.WaitGroup
+	arrived.Add(pkg.DefaultBackgroundWorkers)
+	release := make(chan struct{})
+	for i := 0; i < pkg.DefaultBackgroundWorkers; i++ {
+		if !pkg.Background().Submit(context.Background(), func(context.Context) {
+			arrived.Done()
+			<-release
+		}) {
+			t.Fatal("后台任务队列已满")
+		}
+	}
+	arrived.Wait()
+	close(release)
+}
+
+// createUser 直接插入状态为 status 的测试用户
+// 每次写入后等待写入触发的后台缓存失效执行完毕，避免与后续的缓存断言竞争
+func createUser(t testing.TB, db *gorm.DB, mr *miniredis.Miniredis, name string, status int) *model.User {
+	t.Helper()
+	user := &model.User{Name: name, Email: name + "@example.com"}
+	write(t, mr, func() error { return db.Create(user).Error })
+	// status 列默认值为 1，零值需要在插入后单独更新
+	if status != user.Status {
+		write(t, mr, func() error { return db.Model(user).Update("status", status).Error })
+	}
+	return user
+}
+
+// write 执行一次用户写入，并等待其触发的后台缓存失效（以统计缓存键被删除为准）
+func write(t testing.TB, mr *miniredis.Miniredis, fn func() error) {
+	t.Helper()
+	if err := mr.Set(UserCountCacheKey, "seed"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fn(); err != nil {
+		t.Fatalf("写入用户失败: %v", err)
+	}
+	waitFor(t, "写入后的缓存失效", func() bool { return !mr.Exists(UserCountCacheKey) })
+}
+
+// waitFor 等待 cond 成立（后台任务异步执行），超时后终止用例
+func waitFor(t testing.TB, desc string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("等待超时: %s", desc)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// useConfig 将全局配置替换为 cfg，并重置依赖配置的进程内缓存，测试结束时恢复
+func useConfig(t testing.TB, cfg *config.Config) {
+	t.Helper()
+	previous := config.Cfg
+	config.Cfg = cfg
+	resetLocalCache()
+	t.Cleanup(func() {
+		config.Cfg = previous
+		resetLocalCache()
+	})
+}
+
+// resetLocalCache 丢弃进程内缓存，下次使用时按当前配置重新创建
+func resetLocalCache() {
+	localUserCache = nil
+	localUserCacheOnce = sync.Once{}
+}
@@ -0,0 +1,68 @@
+package logic
+
+import (
+	"sync"
+	"time"
+
+	"gin-project/config"
+	"gin-project/model"
+	"gin-project/pkg"
+)
+
+const (
+	DefaultLocalCacheSize = 1000             // 本地缓存默认最大条目数
+	DefaultLocalCacheTTL  = 10 * time.Second // 本地缓存默认过期时间（每个实例独立，需使用较短的过期时间）
+)
+
+var (
+	// localUserCache 进程内用户缓存（cache.local.enabled 未开启时为 nil）
+	localUserCache     *pkg.LRU[uint, model.User]
+	localUserCacheOnce sync.Once
+)
+
+// userLocalCache 获取进程内用户缓存，未启用时返回 nil
+func userLocalCache() *pkg.LRU[uint, model.User] {
+	localUserCacheOnce.Do(func() {
+		if config.Cfg == nil || !config.Cfg.Cache.Local.Enabled {
+			return
+		}
+
+		size := config.Cfg.Cache.Local.Size
+		if size <= 0 {
+			size = DefaultLocalCacheSize
+		}
+		ttl := DefaultLocalCacheTTL
+		if config.Cfg.Cache.Local.TTL > 0 {
+			ttl = time.Duration(config.Cfg.Cache.Local.TTL) * time.Second
+		}
+		localUserCache = pkg.NewLRU[uint, model.User](size, ttl)
+	})
+	return localUserCache
+}
+
+// getLocalUser 从进程内缓存获取用户（返回副本，避免调用方修改缓存数据）
+func getLocalUser(id uint) (*model.User, bool) {
+	cache := userLocalCache()
+	if cache == nil {
+		return nil, false
+	}
+	user, ok := cache.Get(id)
+	if !ok {
+		return nil, false
+	}
+	return &user, true
+}
+
+// setLocalUser 将用户写入进程内缓存
+func setLocalUser(user *model.User) {
+	if cache := userLocalCache(); cache != nil {
+		cache.Set(user.ID, *user)
+	}
+}
+
+// invalidateLocalUser 删除进程内缓存中的用户
+func invalidateLocalUser(id uint) {
+	if cache := userLocalCache(); cache != nil {
+		cache.Delete(id)
+	}
+}
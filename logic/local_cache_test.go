@@ -0,0 +1,94 @@
+package logic
+
+import (
+	"context"
+	"testing"
+
+	"gin-project/config"
+	"gin-project/model"
+)
+
+// localCacheConfig 启用进程内缓存的配置
+func localCacheConfig() *config.Config {
+	return &config.Config{Cache: config.Cache{Local: config.LocalCache{Enabled: true}}}
+}
+
+func TestLocalCacheServesRepeatedReads(t *testing.T) {
+	useConfig(t, localCacheConfig())
+	db, mr := setupStores(t)
+	user := createUser(t, db, mr, "alice", 1)
+
+	if _, err := GetUserByID(context.Background(), user.ID); err != nil {
+		t.Fatal(err)
+	}
+	// 进程内缓存命中时不访问 Redis 和数据库
+	mr.FlushAll()
+	if err := db.Exec("UPDATE users SET name = 'changed' WHERE id = ?", user.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	got, err := GetUserByID(context.Background(), user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "alice" {
+		t.Errorf("name = %q, want 进程内缓存中的 alice", got.Name)
+	}
+}
+
+func TestLocalCacheInvalidatedOnUpdate(t *testing.T) {
+	useConfig(t, localCacheConfig())
+	db, mr := setupStores(t)
+	user := createUser(t, db, mr, "alice", 1)
+
+	if _, err := GetUserByID(context.Background(), user.ID); err != nil {
+		t.Fatal(err)
+	}
+	// 等待读取触发的异步回填完成，避免旧值在更新清除缓存之后才写入 Redis
+	drainBackground(t)
+	if _, err := UpdateUser(context.Background(), &model.User{ID: user.ID, Name: "bob", Email: user.Email, Status: 1, Version: user.Version}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := GetUserByID(context.Background(), user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "bob" {
+		t.Errorf("更新后 name = %q, want bob", got.Name)
+	}
+}
+
+func TestLocalCacheDisabledByDefault(t *testing.T) {
+	useConfig(t, &config.Config{})
+	if userLocalCache() != nil {
+		t.Error("未开启 cache.local.enabled 时不应创建进程内缓存")
+	}
+}
+
+// BenchmarkGetUserByID 对比进程内缓存命中与 Redis 缓存命中的读取开销
+func BenchmarkGetUserByID(b *testing.B) {
+	for _, bc := range []struct {
+		name string
+		cfg  *config.Config
+	}{
+		{"redis", &config.Config{}},
+		{"local", localCacheConfig()},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			useConfig(b, bc.cfg)
+			db, mr := setupStores(b)
+			user := createUser(b, db, mr, "alice", 1)
+			ctx := context.Background()
+			if _, err := GetUserByID(ctx, user.ID); err != nil {
+				b.Fatal(err)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := GetUserByID(ctx, user.ID); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package logic
+
+import (
+	"os"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	sdktracetest "go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// spanRecorder 包内测试共用的 span 记录器
+// pkg.Tracer 只会绑定到第一次设置的全局 TracerProvider，因此整个测试进程只安装一次，用例之间通过 Reset 清空
+var spanRecorder = sdktracetest.NewSpanRecorder()
+
+func TestMain(m *testing.M) {
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithSpanProcessor(spanRecorder),
+	))
+	os.Exit(m.Run())
+}
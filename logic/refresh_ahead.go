@@ -0,0 +1,78 @@
+package logic
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"gin-project/config"
+	"gin-project/database"
+	"gin-project/pkg"
+)
+
+// DefaultRefreshAheadThreshold 提前刷新的默认剩余过期时间阈值
+const DefaultRefreshAheadThreshold = 5 * time.Minute
+
+// refreshingUsers 正在提前刷新的用户缓存键，避免同一用户并发重复刷新
+var refreshingUsers sync.Map
+
+// refreshAheadThreshold 获取提前刷新阈值，未开启 cache.refreshAhead 时返回 0
+func refreshAheadThreshold() time.Duration {
+	if config.Cfg == nil || !config.Cfg.Cache.RefreshAhead.Enabled {
+		return 0
+	}
+	if config.Cfg.Cache.RefreshAhead.Threshold > 0 {
+		return time.Duration(config.Cfg.Cache.RefreshAhead.Threshold) * time.Second
+	}
+	return DefaultRefreshAheadThreshold
+}
+
+// getCachedUser 读取用户缓存；开启提前刷新时在同一个 pipeline 中读取剩余过期时间（一次往返），否则 ttl 为 0
+func getCachedUser(ctx context.Context, cacheKey string) (string, time.Duration, error) {
+	if refreshAheadThreshold() <= 0 {
+		value, err := database.RedisClient.Get(ctx, cacheKey).Result()
+		return value, 0, err
+	}
+
+	pipe := database.RedisClient.Pipeline()
+	getCmd := pipe.Get(ctx, cacheKey)
+	ttlCmd := pipe.TTL(ctx, cacheKey)
+	// Exec 的错误已记录在各命令上：以 GET 的结果为准，TTL 失败时只是不触发提前刷新
+	_, _ = pipe.Exec(ctx)
+	value, err := getCmd.Result()
+	return value, ttlCmd.Val(), err
+}
+
+// maybeRefreshUser 缓存命中后根据剩余过期时间（与读取缓存同一个 pipeline 获取）判断是否提前刷新，
+// 低于阈值时异步从数据库重新加载并重置缓存；在有界后台执行器中执行（使用不随请求取消的 context，进程退出时排空），不阻塞当前请求
+func maybeRefreshUser(ctx context.Context, tenantID string, id uint, ttl time.Duration) {
+	threshold := refreshAheadThreshold()
+	if threshold <= 0 || ttl <= 0 || ttl > threshold {
+		return
+	}
+	cacheKey := userCacheKey(tenantID, id)
+	if _, loaded := refreshingUsers.LoadOrStore(cacheKey, struct{}{}); loaded {
+		return
+	}
+
+	submitted := pkg.Background().Submit(ctx, func(ctx context.Context) {
+		defer refreshingUsers.Delete(cacheKey)
+
+		user, err := userRepo.FindByID(ctx, id, tenantScope(tenantID))
+		if err != nil {
+			pkg.LoggerFromContext(ctx).Warn("提前刷新用户缓存失败", "user_id", id, "error", err)
+			return
+		}
+
+		jsonData, err := json.Marshal(user)
+		if err != nil || cacheValueTooLarge(ctx, cacheKey, len(jsonData)) {
+			return
+		}
+		database.RedisClient.Set(ctx, cacheKey, string(jsonData), UserCacheTTL)
+		setLocalUser(user)
+	})
+	if !submitted {
+		refreshingUsers.Delete(cacheKey)
+	}
+}
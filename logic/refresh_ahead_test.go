@@ -0,0 +1,86 @@
+package logic
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gin-project/config"
+
+	"github.com/alicebob/miniredis/v2"
+	"gorm.io/gorm"
+)
+
+// countUserQueries 统计 users 表的查询次数，每次查询完成后等待 gate 可读（关闭 gate 即不再阻塞）
+func countUserQueries(t *testing.T, db *gorm.DB, gate <-chan struct{}) *atomic.Int32 {
+	t.Helper()
+	var queries atomic.Int32
+	err := db.Callback().Query().After("gorm:query").Register("test:count_user_queries", func(tx *gorm.DB) {
+		if tx.Statement.Table == "users" {
+			queries.Add(1)
+			<-gate
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &queries
+}
+
+// seedUserCache 将用户写入 Redis 缓存并设置剩余过期时间
+func seedUserCache(t *testing.T, mr *miniredis.Miniredis, key string, value interface{}, ttl time.Duration) {
+	t.Helper()
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mr.Set(key, string(data)); err != nil {
+		t.Fatal(err)
+	}
+	mr.SetTTL(key, ttl)
+}
+
+func TestRefreshAheadRefreshesNearExpiryHitOnce(t *testing.T) {
+	useConfig(t, &config.Config{Cache: config.Cache{RefreshAhead: config.RefreshAhead{Enabled: true, Threshold: 60}}})
+	db, mr := setupStores(t)
+	user := createUser(t, db, mr, "alice", 1)
+	// 刷新在所有并发命中返回之前保持进行中，确保只有在途去重在起作用
+	gate := make(chan struct{})
+	queries := countUserQueries(t, db, gate)
+	key := userCacheKey("", user.ID)
+
+	// 剩余过期时间低于阈值：并发命中只触发一次刷新
+	seedUserCache(t, mr, key, user, 10*time.Second)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := GetUserByID(context.Background(), user.ID); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	close(gate)
+	drainBackground(t)
+
+	if got := queries.Load(); got != 1 {
+		t.Errorf("临近过期时查询数据库 %d 次, want 1", got)
+	}
+	if ttl := mr.TTL(key); ttl <= time.Minute {
+		t.Errorf("刷新后 TTL = %v, want 重置为 %v", ttl, UserCacheTTL)
+	}
+
+	// 剩余过期时间高于阈值：不刷新
+	if _, err := GetUserByID(context.Background(), user.ID); err != nil {
+		t.Fatal(err)
+	}
+	drainBackground(t)
+	if got := queries.Load(); got != 1 {
+		t.Errorf("剩余时间充足时再次查询了数据库，共 %d 次, want 1", got)
+	}
+}
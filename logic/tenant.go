@@ -0,0 +1,59 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+
+	"gin-project/config"
+	"gin-project/database"
+	"gin-project/model"
+	"gin-project/pkg"
+	"gin-project/pkg/errcode"
+
+	"gorm.io/gorm"
+)
+
+// ErrTenantRequired 多租户部署（app.requireTenant）下 context 中缺少租户ID
+var ErrTenantRequired = errcode.New(errcode.TenantRequired, "")
+
+// currentTenant 获取当前请求的租户ID（由 middleware.TenantMiddleware 存入 context）
+// 开启 app.requireTenant 而 context 中没有租户时返回 ErrTenantRequired；单租户部署返回空字符串
+func currentTenant(ctx context.Context) (string, error) {
+	tenantID, ok := pkg.TenantFromContext(ctx)
+	if !ok && config.Cfg != nil && config.Cfg.App.RequireTenant {
+		return "", ErrTenantRequired
+	}
+	return tenantID, nil
+}
+
+// tenantScope 按租户过滤的查询条件，租户为空（单租户部署）时不过滤
+func tenantScope(tenantID string) database.Scope {
+	return func(db *gorm.DB) *gorm.DB {
+		if tenantID == "" {
+			return db
+		}
+		return db.Where("tenant_id = ?", tenantID)
+	}
+}
+
+// tenantMatches 用户是否属于指定租户，租户为空时不校验
+// 进程内缓存按用户ID存储，命中后需校验租户，避免跨租户读取
+func tenantMatches(tenantID string, user *model.User) bool {
+	return tenantID == "" || user.TenantID == tenantID
+}
+
+// userCacheKey 用户缓存键，有租户时按租户隔离（tenant:%s:user:%d）
+func userCacheKey(tenantID string, id uint) string {
+	if tenantID == "" {
+		return fmt.Sprintf(UserCacheKey, id)
+	}
+	return fmt.Sprintf(TenantUserCacheKey, tenantID, id)
+}
+
+// userCountCacheKey 用户统计缓存键，有租户时按租户隔离
+func userCountCacheKey(tenantID string) string {
+	if tenantID == "" {
+		return UserCountCacheKey
+	}
+	return fmt.Sprintf(TenantUserCountCacheKey, tenantID)
+}
@@ -0,0 +1,61 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"gin-project/config"
+	"gin-project/model"
+	"gin-project/pkg"
+)
+
+func TestTenantIsolation(t *testing.T) {
+	_, mr := setupStores(t)
+	tenantA := pkg.WithTenant(context.Background(), "tenant-a")
+	tenantB := pkg.WithTenant(context.Background(), "tenant-b")
+
+	user := &model.User{Name: "alice", Email: "alice@a.example.com"}
+	if err := CreateUser(tenantA, user); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if user.TenantID != "tenant-a" {
+		t.Fatalf("TenantID = %q, want tenant-a", user.TenantID)
+	}
+	drainBackground(t)
+
+	got, err := GetUserByID(tenantA, user.ID)
+	if err != nil || got.Email != user.Email {
+		t.Fatalf("租户 A 读取 = %+v, %v", got, err)
+	}
+	drainBackground(t)
+	if key := fmt.Sprintf(TenantUserCacheKey, "tenant-a", user.ID); !mr.Exists(key) {
+		t.Errorf("缓存键应按租户隔离，缺少 %s，keys = %v", key, mr.Keys())
+	}
+
+	// 租户 B 无法读取租户 A 的用户（缓存命中时同样隔离）
+	if _, err := GetUserByID(tenantB, user.ID); !isUserNotFound(err) {
+		t.Errorf("租户 B 读取租户 A 的用户 err = %v, want 用户不存在", err)
+	}
+	users, err := GetAllUsers(tenantB)
+	if err != nil || len(users) != 0 {
+		t.Errorf("租户 B 的用户列表 = %v, %v, want 空", userIDs(users), err)
+	}
+	if users, _ := GetAllUsers(tenantA); len(users) != 1 {
+		t.Errorf("租户 A 的用户列表 = %v, want 1 个用户", userIDs(users))
+	}
+}
+
+func TestTenantRequired(t *testing.T) {
+	useConfig(t, &config.Config{App: config.App{RequireTenant: true}})
+	setupStores(t)
+
+	if _, err := GetUserByID(context.Background(), 1); !errors.Is(err, ErrTenantRequired) {
+		t.Errorf("GetUserByID err = %v, want ErrTenantRequired", err)
+	}
+	err := CreateUser(context.Background(), &model.User{Name: "bob", Email: "bob@example.com"})
+	if !errors.Is(err, ErrTenantRequired) {
+		t.Errorf("CreateUser err = %v, want ErrTenantRequired", err)
+	}
+}
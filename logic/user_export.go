@@ -0,0 +1,69 @@
+package logic
+
+import (
+	"context"
+	"database/sql"
+
+	"gin-project/database"
+	"gin-project/model"
+
+	"gorm.io/gorm"
+)
+
+// UserExportRows 用户导出游标，按 id 升序逐行读取，内存占用与表大小无关
+// 调用方必须调用 Close 释放数据库连接
+type UserExportRows struct {
+	db    *gorm.DB
+	rows  *sql.Rows
+	count int
+	err   error
+}
+
+// ExportUsers 打开用户导出游标（GORM Rows() 逐行迭代，多租户部署下仅导出当前租户的用户）
+func ExportUsers(ctx context.Context) (*UserExportRows, error) {
+	tenantID, err := currentTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 使用带追踪的数据库客户端（自动追踪）；导出耗时与表大小相关，不使用 database.mysql.queryTimeout
+	db := database.DB.WithContext(ctx)
+	rows, err := db.Model(&model.User{}).Scopes(tenantScope(tenantID), orderByID).Rows()
+	if err != nil {
+		return nil, err
+	}
+	return &UserExportRows{db: db, rows: rows}, nil
+}
+
+// Next 读取下一个用户，没有更多数据或出错时返回 false（错误通过 Err 获取）
+func (r *UserExportRows) Next() (*model.User, bool) {
+	if r.err != nil || !r.rows.Next() {
+		return nil, false
+	}
+
+	user := &model.User{}
+	if err := r.db.ScanRows(r.rows, user); err != nil {
+		r.err = err
+		return nil, false
+	}
+	r.count++
+	return user, true
+}
+
+// Count 已读取的用户数
+func (r *UserExportRows) Count() int {
+	return r.count
+}
+
+// Err 迭代过程中的错误
+func (r *UserExportRows) Err() error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.rows.Err()
+}
+
+// Close 关闭游标，释放数据库连接
+func (r *UserExportRows) Close() error {
+	return r.rows.Close()
+}
@@ -3,58 +3,261 @@ package logic
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"gin-project/database"
 	"gin-project/model"
+	"gin-project/pkg"
+	"gin-project/pkg/errcode"
 
 	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
 )
 
+// userRepo 用户仓储（使用全局 DB，带 otelgorm 追踪）
+var userRepo = database.NewRepository[model.User](nil)
+
 // GetUserByID 根据ID查询用户，优先从缓存获取
-// 使用带追踪的数据库和缓存客户端，自动追踪所有操作
+// 使用带追踪的数据库和缓存客户端，自动追踪所有操作；多租户部署下仅返回当前租户的用户，
+// 其他租户的用户视为不存在；
+// 开启 cache.coalesce 时同一用户的并发查询合并为一次（见 coalesceGetUser）
 func GetUserByID(ctx context.Context, id uint) (*model.User, error) {
+	if coalesceEnabled() {
+		return coalesceGetUser(ctx, id)
+	}
+	return getUserByID(ctx, id)
+}
+
+// getUserByID 根据ID查询用户：进程内缓存 -> Redis -> 数据库
+func getUserByID(ctx context.Context, id uint) (*model.User, error) {
+	tenantID, err := currentTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 优先从进程内缓存获取（cache.local.enabled 开启时，按 ID 存储，需校验租户）
+	if user, ok := getLocalUser(id); ok && tenantMatches(tenantID, user) {
+		return user, nil
+	}
 
-	// 先从缓存获取
-	cacheKey := fmt.Sprintf(UserCacheKey, id)
+	// 再从Redis缓存获取（按租户隔离缓存键；后台探测发现 Redis 不可用时跳过缓存，直接查询数据库）
+	cacheKey := userCacheKey(tenantID, id)
 	user := &model.User{}
+	useCache := database.RedisHealthy()
 
 	// 从Redis获取数据（使用带追踪的客户端，自动追踪）
-	jsonData, err := database.RedisClient.Get(ctx, cacheKey).Result()
-	if err == nil {
-		// 缓存命中，解析数据
-		if err := json.Unmarshal([]byte(jsonData), user); err == nil {
-			return user, nil
+	if useCache {
+		jsonData, ttl, err := getCachedUser(ctx, cacheKey)
+		if err == nil {
+			// 命中不存在占位值：用户不存在，直接返回，避免重复查询数据库
+			if jsonData == UserTombstone {
+				return nil, errcode.Wrap(errcode.UserNotFound, gorm.ErrRecordNotFound)
+			}
+
+			// 缓存命中，解析数据
+			if err := json.Unmarshal([]byte(jsonData), user); err == nil {
+				setLocalUser(user)
+				maybeRefreshUser(ctx, tenantID, id, ttl)
+				return user, nil
+			}
+			// 反序列化错误已由 Redis 追踪自动记录
+		} else if err != redis.Nil {
+			// Redis 错误已由追踪自动记录
 		}
-		// 反序列化错误已由 Redis 追踪自动记录
-	} else if err != redis.Nil {
-		// Redis 错误已由追踪自动记录
 	}
 
 	// 缓存未命中，从数据库查询（使用带追踪的客户端，自动追踪）
-	err = database.DB.WithContext(ctx).First(user, id).Error
+	logger := pkg.LoggerFromContext(ctx)
+	logger.Debug("用户缓存未命中，查询数据库", "user_id", id)
+	user, err = userRepo.FindByID(ctx, id, tenantScope(tenantID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// 用户不存在：写入短期占位值（负缓存），CreateUser 会清除该键
+		if useCache {
+			logger.Debug("用户不存在，写入负缓存", "user_id", id)
+			pkg.Background().Submit(ctx, func(ctx context.Context) {
+				database.RedisClient.Set(ctx, cacheKey, UserTombstone, UserTombstoneTTL)
+			})
+		}
+		return nil, errcode.Wrap(errcode.UserNotFound, err)
+	}
 	if err != nil {
+		logger.Warn("查询用户失败", "user_id", id, "error", err)
 		return nil, err
 	}
+	setLocalUser(user)
 
-	// 将查询结果存入缓存（异步执行，使用带追踪的客户端，自动追踪）
-	go func() {
-		jsonData, _ := json.Marshal(user)
-		database.RedisClient.Set(ctx, cacheKey, string(jsonData), UserCacheTTL)
-	}()
+	// 将查询结果存入缓存（通过有界后台执行器异步执行，使用带追踪的客户端，自动追踪）
+	if !useCache {
+		return user, nil
+	}
+	jsonBytes, _ := json.Marshal(user)
+	if cacheValueTooLarge(ctx, cacheKey, len(jsonBytes)) {
+		return user, nil
+	}
+	pkg.Background().Submit(ctx, func(ctx context.Context) {
+		database.RedisClient.Set(ctx, cacheKey, string(jsonBytes), UserCacheTTL)
+	})
 
 	return user, nil
 }
 
-// GetAllUsers 查询所有用户
+// UserExistsByEmail 判断邮箱是否已被使用（仅查询是否存在，不读取整行）
+// 不存在的结果短暂缓存，抑制枚举式请求对数据库的压力；CreateUser 会清除对应的负缓存；
+// 邮箱在所有租户间唯一（唯一索引），因此不按租户过滤
+func UserExistsByEmail(ctx context.Context, email string) (bool, error) {
+	absentKey := fmt.Sprintf(UserEmailAbsentKey, email)
+	useCache := database.RedisHealthy()
+	if useCache {
+		if n, err := database.RedisClient.Exists(ctx, absentKey).Result(); err == nil && n > 0 {
+			return false, nil
+		}
+	}
+
+	exists, err := userRepo.Exists(ctx, func(db *gorm.DB) *gorm.DB {
+		return db.Where("email = ?", email)
+	})
+	if err != nil {
+		return false, err
+	}
+	if !exists && useCache {
+		pkg.Background().Submit(ctx, func(ctx context.Context) {
+			database.RedisClient.Set(ctx, absentKey, 1, UserEmailAbsentTTL)
+		})
+	}
+	return exists, nil
+}
+
+// GetAllUsers 查询所有用户（多租户部署下为当前租户的所有用户）
 func GetAllUsers(ctx context.Context) ([]model.User, error) {
-	var users []model.User
+	tenantID, err := currentTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 使用带追踪的数据库客户端（自动追踪）
+	return userRepo.List(ctx, tenantScope(tenantID))
+}
+
+// UserSortColumns 用户列表允许排序的列
+var UserSortColumns = []string{"id", "name", "created_at"}
+
+// ListUsers 分页查询用户（偏移分页），返回当前页数据和总数
+// order 为排序参数（如 created_at:desc，见 database.ParseOrder），为空时按 id 升序，列不在 UserSortColumns 中时返回错误
+// 适合跳页访问；深度翻页时 OFFSET 性能下降，应使用 ListUsersByCursor
+func ListUsers(ctx context.Context, page, pageSize int, order string) ([]model.User, int64, error) {
+	tenantID, err := currentTenant(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	orderBy, err := database.ParseOrder(order, UserSortColumns, "id")
+	if err != nil {
+		return nil, 0, err
+	}
 
 	// 使用带追踪的数据库客户端（自动追踪）
-	err := database.DB.WithContext(ctx).Find(&users).Error
+	total, err := userRepo.Count(ctx, tenantScope(tenantID))
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	users, err := userRepo.List(ctx, tenantScope(tenantID), orderBy, database.Paginate(page, pageSize))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// ListUsersByCursor 游标分页查询用户，以 id 作为游标（WHERE id > cursor ORDER BY id LIMIT n）
+// 查询耗时只与 limit 相关，适合深度滚动；返回下一页游标，没有更多数据时为 0
+func ListUsersByCursor(ctx context.Context, cursor uint, limit int) ([]model.User, uint, error) {
+	tenantID, err := currentTenant(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	_, limit = database.NormalizePage(1, limit)
+
+	// 多查一条用于判断是否还有下一页（使用带追踪的数据库客户端，自动追踪）
+	users, err := userRepo.List(ctx, tenantScope(tenantID), orderByID, func(db *gorm.DB) *gorm.DB {
+		return db.Where("id > ?", cursor).Limit(limit + 1)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var nextCursor uint
+	if len(users) > limit {
+		users = users[:limit]
+		nextCursor = users[limit-1].ID
+	}
+
+	return users, nextCursor, nil
+}
+
+// orderByID 按主键升序排列
+func orderByID(db *gorm.DB) *gorm.DB {
+	return db.Order("id")
+}
+
+// userCount 用户统计缓存结构
+type userCount struct {
+	Total    int64         `json:"total"`
+	ByStatus map[int]int64 `json:"by_status"`
+}
+
+// CountUsers 统计用户总数及各状态的用户数，优先从缓存获取
+// 统计查询较重且变化缓慢，结果短暂缓存，创建用户时失效
+func CountUsers(ctx context.Context) (int64, map[int]int64, error) {
+	tenantID, err := currentTenant(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// 先从缓存获取（按租户隔离缓存键，使用带追踪的客户端，自动追踪；Redis 不可用时跳过）
+	countKey := userCountCacheKey(tenantID)
+	useCache := database.RedisHealthy()
+	if useCache {
+		jsonData, err := database.RedisClient.Get(ctx, countKey).Result()
+		if err == nil {
+			var cached userCount
+			if err := json.Unmarshal([]byte(jsonData), &cached); err == nil {
+				return cached.Total, cached.ByStatus, nil
+			}
+		}
+	}
+
+	// 缓存未命中，从数据库统计总数（使用带追踪的数据库客户端，自动追踪）
+	total, err := userRepo.Count(ctx, tenantScope(tenantID))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// 按状态分组统计
+	var rows []struct {
+		Status int
+		Count  int64
+	}
+	db, cancel := database.Conn(ctx)
+	defer cancel()
+	err = db.Model(&model.User{}).
+		Scopes(tenantScope(tenantID)).
+		Select("status, COUNT(*) AS count").
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return 0, nil, err
+	}
+
+	byStatus := make(map[int]int64, len(rows))
+	for _, row := range rows {
+		byStatus[row.Status] = row.Count
+	}
+
+	// 将统计结果存入缓存
+	if data, err := json.Marshal(userCount{Total: total, ByStatus: byStatus}); err == nil && useCache {
+		database.RedisClient.Set(ctx, countKey, string(data), userCountCacheTTL())
 	}
 
-	return users, nil
+	return total, byStatus, nil
 }
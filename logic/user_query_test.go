@@ -0,0 +1,239 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"gin-project/model"
+	"gin-project/pkg/errcode"
+)
+
+func TestCountUsersAggregatesByStatus(t *testing.T) {
+	db, mr := setupStores(t)
+	createUser(t, db, mr, "alice", 1)
+	createUser(t, db, mr, "bob", 1)
+	createUser(t, db, mr, "carol", 0)
+
+	total, byStatus, err := CountUsers(context.Background())
+	if err != nil {
+		t.Fatalf("CountUsers: %v", err)
+	}
+	if total != 3 {
+		t.Errorf("total = %d, want 3", total)
+	}
+	if want := map[int]int64{0: 1, 1: 2}; !reflect.DeepEqual(byStatus, want) {
+		t.Errorf("byStatus = %v, want %v", byStatus, want)
+	}
+}
+
+func TestCountUsersCachesAggregate(t *testing.T) {
+	db, mr := setupStores(t)
+	createUser(t, db, mr, "alice", 1)
+
+	if _, _, err := CountUsers(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if ttl := mr.TTL(UserCountCacheKey); ttl != DefaultUserCountCacheTTL {
+		t.Errorf("缓存 TTL = %s, want %s", ttl, DefaultUserCountCacheTTL)
+	}
+
+	// 原生 SQL 不经过 GORM 写回调，缓存不会失效：命中缓存时返回旧的统计结果
+	if err := db.Exec("INSERT INTO users (name, email, status, version) VALUES ('bob', 'bob@example.com', 1, 0)").Error; err != nil {
+		t.Fatal(err)
+	}
+	if total, _, _ := CountUsers(context.Background()); total != 1 {
+		t.Errorf("缓存有效期内 total = %d, want 1（缓存值）", total)
+	}
+
+	mr.FastForward(DefaultUserCountCacheTTL)
+	if total, _, _ := CountUsers(context.Background()); total != 2 {
+		t.Errorf("缓存过期后 total = %d, want 2", total)
+	}
+}
+
+func TestCountUsersCacheInvalidatedOnCreate(t *testing.T) {
+	db, mr := setupStores(t)
+	createUser(t, db, mr, "alice", 1)
+	if _, _, err := CountUsers(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	createUser(t, db, mr, "bob", 1)
+	if total, _, _ := CountUsers(context.Background()); total != 2 {
+		t.Errorf("创建用户后 total = %d, want 2", total)
+	}
+}
+
+// seedUsers 插入 n 个测试用户，返回按插入顺序排列的用户ID
+func seedUsers(t *testing.T, n int) []uint {
+	t.Helper()
+	db, mr := setupStores(t)
+	ids := make([]uint, n)
+	for i := range ids {
+		ids[i] = createUser(t, db, mr, fmt.Sprintf("user%d", i), 1).ID
+	}
+	return ids
+}
+
+// userIDs 提取用户ID
+func userIDs(users []model.User) []uint {
+	ids := make([]uint, len(users))
+	for i, user := range users {
+		ids[i] = user.ID
+	}
+	return ids
+}
+
+func TestListUsersOffsetPagination(t *testing.T) {
+	ids := seedUsers(t, 5)
+
+	tests := []struct {
+		page int
+		want []uint
+	}{
+		{1, ids[0:2]},
+		{3, ids[4:5]},
+		{4, []uint{}},
+	}
+	for _, tt := range tests {
+		users, total, err := ListUsers(context.Background(), tt.page, 2, "id:asc")
+		if err != nil {
+			t.Fatalf("ListUsers(page=%d): %v", tt.page, err)
+		}
+		if total != 5 {
+			t.Errorf("page=%d total = %d, want 5", tt.page, total)
+		}
+		if got := userIDs(users); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("page=%d ids = %v, want %v", tt.page, got, tt.want)
+		}
+	}
+}
+
+func TestListUsersByCursor(t *testing.T) {
+	ids := seedUsers(t, 4)
+
+	users, next, err := ListUsersByCursor(context.Background(), 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := userIDs(users); !reflect.DeepEqual(got, ids[0:2]) || next != ids[1] {
+		t.Fatalf("第一页 ids = %v, next = %d, want %v, %d", got, next, ids[0:2], ids[1])
+	}
+
+	// 最后一页恰好取满时也能判断已无更多数据，不需要再请求一次空页
+	users, next, err = ListUsersByCursor(context.Background(), next, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := userIDs(users); !reflect.DeepEqual(got, ids[2:4]) || next != 0 {
+		t.Errorf("最后一页 ids = %v, next = %d, want %v, 0", got, next, ids[2:4])
+	}
+
+	users, next, err = ListUsersByCursor(context.Background(), ids[3], 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 0 || next != 0 {
+		t.Errorf("游标之后没有数据时 = %v, next = %d", userIDs(users), next)
+	}
+}
+
+func TestGetUserByIDTombstone(t *testing.T) {
+	useConfig(t, nil)
+	db, mr := setupStores(t)
+	ctx := context.Background()
+	key := userCacheKey("", 1)
+
+	// 不存在的用户写入占位值
+	if _, err := GetUserByID(ctx, 1); !isUserNotFound(err) {
+		t.Fatalf("err = %v, want UserNotFound", err)
+	}
+	waitFor(t, "写入占位值", func() bool { return mr.Exists(key) })
+	if got, _ := mr.Get(key); got != UserTombstone {
+		t.Fatalf("缓存值 = %q, want 占位值", got)
+	}
+	if ttl := mr.TTL(key); ttl <= 0 || ttl > UserTombstoneTTL {
+		t.Errorf("占位值 TTL = %v", ttl)
+	}
+
+	// 命中占位值时不查询数据库（绕过 GORM 回调直接插入的行不可见）
+	if err := db.Exec("INSERT INTO users (id, name, email, status, version) VALUES (1, 'ghost', 'ghost@example.com', 1, 1)").Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetUserByID(ctx, 1); !isUserNotFound(err) {
+		t.Fatalf("命中占位值 err = %v, want UserNotFound", err)
+	}
+
+	// 创建用户后清除占位值（下一个自增 ID 为 2）
+	key = userCacheKey("", 2)
+	if _, err := GetUserByID(ctx, 2); !isUserNotFound(err) {
+		t.Fatalf("err = %v, want UserNotFound", err)
+	}
+	waitFor(t, "写入占位值", func() bool { return mr.Exists(key) })
+	user := &model.User{Name: "alice", Email: "alice@example.com"}
+	if err := CreateUser(ctx, user); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if user.ID != 2 {
+		t.Fatalf("user.ID = %d, want 2", user.ID)
+	}
+	waitFor(t, "清除占位值", func() bool { return !mr.Exists(key) })
+	got, err := GetUserByID(ctx, 2)
+	if err != nil || got.Name != "alice" {
+		t.Fatalf("GetUserByID = (%v, %v), want alice", got, err)
+	}
+}
+
+// isUserNotFound 错误是否为用户不存在的业务错误
+func isUserNotFound(err error) bool {
+	e, ok := errcode.FromError(err)
+	return ok && e.Code == errcode.UserNotFound
+}
+
+func TestUserExistsByEmail(t *testing.T) {
+	db, mr := setupStores(t)
+	createUser(t, db, mr, "taken", 1)
+	closed := make(chan struct{})
+	close(closed)
+	queries := countUserQueries(t, db, closed)
+	ctx := context.Background()
+
+	exists, err := UserExistsByEmail(ctx, "taken@example.com")
+	if err != nil || !exists {
+		t.Fatalf("已使用的邮箱 exists = %v, err = %v", exists, err)
+	}
+	drainBackground(t)
+	if mr.Exists(fmt.Sprintf(UserEmailAbsentKey, "taken@example.com")) {
+		t.Error("已存在的邮箱不应写入负缓存")
+	}
+
+	absentKey := fmt.Sprintf(UserEmailAbsentKey, "free@example.com")
+	exists, err = UserExistsByEmail(ctx, "free@example.com")
+	if err != nil || exists {
+		t.Fatalf("未使用的邮箱 exists = %v, err = %v", exists, err)
+	}
+	waitFor(t, "写入邮箱负缓存", func() bool { return mr.Exists(absentKey) })
+	if ttl := mr.TTL(absentKey); ttl <= 0 || ttl > UserEmailAbsentTTL {
+		t.Errorf("负缓存过期时间 = %s, want (0, %s]", ttl, UserEmailAbsentTTL)
+	}
+
+	// 负缓存有效期内不再查询数据库
+	before := queries.Load()
+	if exists, _ := UserExistsByEmail(ctx, "free@example.com"); exists {
+		t.Error("负缓存命中时应返回不存在")
+	}
+	if queries.Load() != before {
+		t.Error("负缓存命中时不应查询数据库")
+	}
+
+	// 创建用户后清除负缓存
+	if err := CreateUser(ctx, &model.User{Name: "free", Email: "free@example.com"}); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	waitFor(t, "清除邮箱负缓存", func() bool { return !mr.Exists(absentKey) })
+	if exists, err := UserExistsByEmail(ctx, "free@example.com"); err != nil || !exists {
+		t.Errorf("创建后 exists = %v, err = %v", exists, err)
+	}
+}
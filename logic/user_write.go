@@ -2,56 +2,258 @@ package logic
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"sync"
 
 	"gin-project/database"
 	"gin-project/model"
+	"gin-project/pkg"
+	"gin-project/pkg/errcode"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
 )
 
-// CreateUser 创建用户
+// ErrVersionConflict 乐观锁冲突：用户已被其他请求修改，需重新读取后再更新
+var ErrVersionConflict = errcode.New(errcode.VersionConflict, "")
+
+// CreateUser 创建用户（多租户部署下归属当前租户）
 func CreateUser(ctx context.Context, user *model.User) error {
 	// 验证数据合法性
 	if user.Name == "" || user.Email == "" {
-		return fmt.Errorf("用户姓名和邮箱不能为空")
+		return errcode.New(errcode.InvalidUser, "用户姓名和邮箱不能为空")
 	}
+	tenantID, err := currentTenant(ctx)
+	if err != nil {
+		return err
+	}
+	user.TenantID = tenantID
 
-	// 检查邮箱是否已存在（使用带追踪的数据库客户端，自动追踪）
-	var existingUser model.User
-	err := database.DB.WithContext(ctx).Where("email = ?", user.Email).First(&existingUser).Error
+	// 检查邮箱是否已存在（邮箱在所有租户间唯一，使用带追踪的数据库客户端，自动追踪）
+	_, err = userRepo.First(ctx, func(db *gorm.DB) *gorm.DB {
+		return db.Where("email = ?", user.Email)
+	})
 	if err == nil {
 		// 用户已存在
-		return fmt.Errorf("邮箱 %s 已存在", user.Email)
+		return errcode.New(errcode.DuplicateEmail, fmt.Sprintf("邮箱 %s 已存在", user.Email))
 	}
 
-	// 插入数据库（使用带追踪的数据库客户端，自动追踪）
-	err = database.DB.WithContext(ctx).Create(user).Error
+	// 插入数据库并记录审计日志（同一事务，使用带追踪的数据库客户端，自动追踪）
+	logger := pkg.LoggerFromContext(ctx)
+	// 遇到死锁时整体重试，重试前清空上一次回滚的事务写入的主键
+	err = database.Transaction(ctx, func(tx *gorm.DB) error {
+		user.ID = 0
+		if err := userRepo.WithDB(tx).Create(ctx, user); err != nil {
+			return err
+		}
+		return RecordAudit(ctx, tx, AuditActionCreate, AuditEntityUser, user.ID, pkg.ActorFromContext(ctx))
+	})
 	if err != nil {
+		logger.Error("创建用户失败", "email", user.Email, "error", err)
 		return err
 	}
+	logger.Info("创建用户成功", "user_id", user.ID)
 
-	// 清除相关的缓存（使用带追踪的 Redis 客户端，自动追踪）
-	cacheKey := fmt.Sprintf(UserCacheKey, user.ID)
-	database.RedisClient.Del(ctx, cacheKey)
+	// 清除相关的缓存（包括该 ID 的不存在占位值，使用带追踪的 Redis 客户端，自动追踪）
+	cacheKey := userCacheKey(tenantID, user.ID)
+	database.RedisClient.Del(ctx, cacheKey, userCountCacheKey(tenantID), fmt.Sprintf(UserEmailAbsentKey, user.Email))
+	publishUserInvalidation(ctx, user.ID)
+	publishUserEvent(ctx, UserCreated, user, pkg.ActorFromContext(ctx))
 
 	return nil
 }
 
 // UpdateUser 更新用户信息
-func UpdateUser(ctx context.Context, user *model.User) error {
+// 使用乐观锁：user.Version 必须是调用方读取到的版本号，版本不一致时返回 ErrVersionConflict
+// 返回更新后实际持久化的用户数据（包含 UpdatedAt 等服务端设置的字段）
+func UpdateUser(ctx context.Context, user *model.User) (*model.User, error) {
 	// 验证数据合法性
 	if user.ID == 0 {
-		return fmt.Errorf("用户ID不能为空")
+		return nil, fmt.Errorf("用户ID不能为空")
+	}
+	tenantID, err := currentTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 校验更新列与模型一致，避免列被重命名后静默失效
+	if err := checkUserColumns(userUpdateColumns); err != nil {
+		return nil, err
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.StringSlice("db.update.columns", userUpdateColumns))
+
+	// 更新数据库并记录审计日志（同一事务，仅更新当前租户的用户），但不更新CreatedAt字段，同时递增版本号
+	err = database.Transaction(ctx, func(tx *gorm.DB) error {
+		rows, err := userRepo.WithDB(tx).Update(ctx, user.ID, map[string]interface{}{
+			"name":    user.Name,
+			"email":   user.Email,
+			"age":     user.Age,
+			"status":  user.Status,
+			"version": gorm.Expr("version + 1"),
+		}, tenantScope(tenantID), func(db *gorm.DB) *gorm.DB {
+			return db.Select(userUpdateColumns).Where("version = ?", user.Version)
+		})
+		if err != nil {
+			return err
+		}
+
+		// 未更新任何行：版本号已变化（被其他请求修改）或用户不存在
+		if rows == 0 {
+			return updateMissed(ctx, tx, tenantID, user.ID)
+		}
+
+		return RecordAudit(ctx, tx, AuditActionUpdate, AuditEntityUser, user.ID, pkg.ActorFromContext(ctx))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := reloadUser(ctx, tenantID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	publishUserEvent(ctx, UserUpdated, updated, pkg.ActorFromContext(ctx))
+	return updated, nil
+}
+
+// PatchUser 部分更新用户信息，仅更新 fields 中提供的字段
+// fields 的键为列名（仅允许 name、email、age、status）；
+// expectedVersion 不为 nil 时启用乐观锁校验，版本不一致时返回 ErrVersionConflict
+func PatchUser(ctx context.Context, id uint, fields map[string]interface{}, expectedVersion *int) (*model.User, error) {
+	// 验证数据合法性
+	if id == 0 {
+		return nil, fmt.Errorf("用户ID不能为空")
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("没有需要更新的字段")
+	}
+	tenantID, err := currentTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]interface{}, len(fields)+1)
+	columns := make([]string, 0, len(fields))
+	for column, value := range fields {
+		if !patchableColumns[column] {
+			return nil, fmt.Errorf("字段 %s 不允许更新", column)
+		}
+		updates[column] = value
+		columns = append(columns, column)
+	}
+	updates["version"] = gorm.Expr("version + 1")
+	sort.Strings(columns)
+	trace.SpanFromContext(ctx).SetAttributes(attribute.StringSlice("db.update.columns", columns))
+
+	// 仅更新提供的字段并记录审计日志（同一事务，仅更新当前租户的用户），同时递增版本号
+	err = database.Transaction(ctx, func(tx *gorm.DB) error {
+		scopes := []database.Scope{tenantScope(tenantID)}
+		if expectedVersion != nil {
+			scopes = append(scopes, func(db *gorm.DB) *gorm.DB {
+				return db.Where("version = ?", *expectedVersion)
+			})
+		}
+		rows, err := userRepo.WithDB(tx).Update(ctx, id, updates, scopes...)
+		if err != nil {
+			return err
+		}
+
+		// 未更新任何行：版本号已变化（被其他请求修改）或用户不存在
+		if rows == 0 {
+			if expectedVersion != nil {
+				return updateMissed(ctx, tx, tenantID, id)
+			}
+			return errcode.Wrap(errcode.UserNotFound, gorm.ErrRecordNotFound)
+		}
+
+		return RecordAudit(ctx, tx, AuditActionUpdate, AuditEntityUser, id, pkg.ActorFromContext(ctx))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := reloadUser(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
 	}
+	publishUserEvent(ctx, UserUpdated, updated, pkg.ActorFromContext(ctx))
+	return updated, nil
+}
 
-	// 更新数据库，但不更新CreatedAt字段（使用带追踪的数据库客户端，自动追踪）
-	err := database.DB.WithContext(ctx).Model(&model.User{}).Select("name", "email", "age", "status", "updated_at").Where("id = ?", user.ID).Updates(user).Error
+// updateMissed 带版本号校验的更新未命中任何行时区分原因：
+// 用户不存在（或不属于当前租户）返回 UserNotFound，用户存在说明版本号已变化，返回 ErrVersionConflict
+func updateMissed(ctx context.Context, tx *gorm.DB, tenantID string, id uint) error {
+	exists, err := userRepo.WithDB(tx).Exists(ctx, tenantScope(tenantID), func(db *gorm.DB) *gorm.DB {
+		return db.Where("id = ?", id)
+	})
 	if err != nil {
 		return err
 	}
+	if !exists {
+		return errcode.Wrap(errcode.UserNotFound, gorm.ErrRecordNotFound)
+	}
+	return ErrVersionConflict
+}
+
+// userUpdateColumns UpdateUser 更新的列（CreatedAt 不更新）
+var userUpdateColumns = []string{"name", "email", "age", "status", "version", "updated_at", "updated_by"}
 
-	// 清除相关的缓存（使用带追踪的 Redis 客户端，自动追踪）
-	cacheKey := fmt.Sprintf(UserCacheKey, user.ID)
-	database.RedisClient.Del(ctx, cacheKey)
+// userSchemaCache User 模型解析结果缓存
+var userSchemaCache sync.Map
+
+// ValidateUserColumns 校验更新使用的列是否都存在于 User 模型中
+// 在启动时调用，尽早发现模型字段重命名导致的不一致
+func ValidateUserColumns() error {
+	columns := append([]string{}, userUpdateColumns...)
+	for column := range patchableColumns {
+		columns = append(columns, column)
+	}
+	return checkUserColumns(columns)
+}
 
+// checkUserColumns 校验列是否存在于 User 模型中，不存在时返回明确的错误
+func checkUserColumns(columns []string) error {
+	userSchema, err := schema.Parse(&model.User{}, &userSchemaCache, schema.NamingStrategy{})
+	if err != nil {
+		return fmt.Errorf("解析 User 模型失败: %v", err)
+	}
+
+	for _, column := range columns {
+		if userSchema.LookUpField(column) == nil {
+			return fmt.Errorf("User 模型中不存在列 %s（表 %s）", column, userSchema.Table)
+		}
+	}
 	return nil
 }
+
+// patchableColumns 允许部分更新的列
+var patchableColumns = map[string]bool{
+	"name":   true,
+	"email":  true,
+	"age":    true,
+	"status": true,
+}
+
+// reloadUser 重新查询更新后的用户数据并刷新缓存（缓存键按租户隔离）
+func reloadUser(ctx context.Context, tenantID string, id uint) (*model.User, error) {
+	// 重新查询更新后的数据（使用带追踪的数据库客户端，自动追踪）
+	updated, err := userRepo.FindByID(ctx, id, tenantScope(tenantID))
+	if err != nil {
+		return nil, err
+	}
+
+	// 用最新数据刷新缓存，失败时删除缓存避免脏数据（使用带追踪的 Redis 客户端，自动追踪）
+	publishUserInvalidation(ctx, id)
+	cacheKey := userCacheKey(tenantID, id)
+	jsonData, err := json.Marshal(updated)
+	if err != nil || cacheValueTooLarge(ctx, cacheKey, len(jsonData)) ||
+		database.RedisClient.Set(ctx, cacheKey, string(jsonData), UserCacheTTL).Err() != nil {
+		database.RedisClient.Del(ctx, cacheKey)
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,176 @@
+package logic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"gin-project/model"
+)
+
+func TestUpdateUserConcurrentUpdatesConflict(t *testing.T) {
+	db, mr := setupStores(t)
+	user := createUser(t, db, mr, "alice", 1)
+
+	// 两个请求读取到同一版本后并发更新，只有一个能成功
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i, name := range []string{"bob", "carol"} {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			_, errs[i] = UpdateUser(context.Background(), &model.User{
+				ID: user.ID, Name: name, Email: user.Email, Status: 1, Version: user.Version,
+			})
+		}(i, name)
+	}
+	wg.Wait()
+
+	var succeeded, conflicted int
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case errors.Is(err, ErrVersionConflict):
+			conflicted++
+		default:
+			t.Fatalf("UpdateUser: %v", err)
+		}
+	}
+	if succeeded != 1 || conflicted != 1 {
+		t.Fatalf("成功 %d 次、冲突 %d 次，want 各 1 次", succeeded, conflicted)
+	}
+
+	var stored model.User
+	if err := db.First(&stored, user.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored.Version != user.Version+1 {
+		t.Errorf("version = %d, want %d", stored.Version, user.Version+1)
+	}
+}
+
+func TestUpdateUserStaleVersion(t *testing.T) {
+	db, mr := setupStores(t)
+	user := createUser(t, db, mr, "alice", 1)
+
+	updated, err := UpdateUser(context.Background(), &model.User{ID: user.ID, Name: "bob", Email: user.Email, Status: 1, Version: user.Version})
+	if err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+	if updated.Version != user.Version+1 || updated.Name != "bob" {
+		t.Errorf("更新后 version = %d, name = %q", updated.Version, updated.Name)
+	}
+
+	_, err = UpdateUser(context.Background(), &model.User{ID: user.ID, Name: "carol", Email: user.Email, Status: 1, Version: user.Version})
+	if !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("使用旧版本号更新应返回 ErrVersionConflict，got %v", err)
+	}
+}
+
+func TestUpdateUserNotFound(t *testing.T) {
+	db, mr := setupStores(t)
+	user := createUser(t, db, mr, "alice", 1)
+
+	// 用户不存在时返回 UserNotFound（404），而不是版本冲突
+	_, err := UpdateUser(context.Background(), &model.User{ID: user.ID + 100, Name: "bob", Email: "bob@example.com", Status: 1, Version: 1})
+	if !isUserNotFound(err) || errors.Is(err, ErrVersionConflict) {
+		t.Errorf("更新不存在的用户应返回 UserNotFound，got %v", err)
+	}
+	version := 1
+	if _, err := PatchUser(context.Background(), user.ID+100, map[string]interface{}{"age": 1}, &version); !isUserNotFound(err) {
+		t.Errorf("带版本号部分更新不存在的用户应返回 UserNotFound，got %v", err)
+	}
+}
+
+func TestUpdateUserReturnsAndCachesPersistedRow(t *testing.T) {
+	db, mr := setupStores(t)
+	user := createUser(t, db, mr, "alice", 1)
+	time.Sleep(2 * time.Millisecond) // 确保 UpdatedAt 可区分
+
+	updated, err := UpdateUser(context.Background(), &model.User{ID: user.ID, Name: "bob", Email: user.Email, Status: 1, Version: user.Version})
+	if err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+	if !updated.UpdatedAt.After(user.UpdatedAt) {
+		t.Errorf("UpdatedAt 应更新: before %s, after %s", user.UpdatedAt, updated.UpdatedAt)
+	}
+	if !updated.CreatedAt.Equal(user.CreatedAt) {
+		t.Errorf("CreatedAt 不应变化: before %s, after %s", user.CreatedAt, updated.CreatedAt)
+	}
+
+	// 缓存刷新为持久化后的数据，而不是仅删除
+	cached, err := mr.Get(fmt.Sprintf(UserCacheKey, user.ID))
+	if err != nil {
+		t.Fatalf("更新后应写入缓存: %v", err)
+	}
+	var fromCache model.User
+	if err := json.Unmarshal([]byte(cached), &fromCache); err != nil {
+		t.Fatal(err)
+	}
+	if fromCache.Name != "bob" || fromCache.Version != updated.Version || !fromCache.UpdatedAt.Equal(updated.UpdatedAt) {
+		t.Errorf("缓存数据 = %+v, want 更新后的用户", fromCache)
+	}
+}
+
+func TestPatchUserUpdatesOnlyProvidedFields(t *testing.T) {
+	db, mr := setupStores(t)
+	user := createUser(t, db, mr, "alice", 1)
+	if err := db.Model(user).Update("age", 20).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := PatchUser(context.Background(), user.ID, map[string]interface{}{"age": 30}, nil)
+	if err != nil {
+		t.Fatalf("PatchUser(age): %v", err)
+	}
+	if updated.Age != 30 || updated.Status != 1 || updated.Name != "alice" || updated.Email != user.Email {
+		t.Errorf("仅更新 age 后 = %+v", updated)
+	}
+
+	// 零值同样视为提供的字段
+	updated, err = PatchUser(context.Background(), user.ID, map[string]interface{}{"status": 0}, nil)
+	if err != nil {
+		t.Fatalf("PatchUser(status): %v", err)
+	}
+	if updated.Status != 0 || updated.Age != 30 || updated.Name != "alice" {
+		t.Errorf("仅更新 status 后 = %+v", updated)
+	}
+	if updated.Version != user.Version+2 {
+		t.Errorf("version = %d, want %d", updated.Version, user.Version+2)
+	}
+}
+
+func TestPatchUserRejects(t *testing.T) {
+	db, mr := setupStores(t)
+	user := createUser(t, db, mr, "alice", 1)
+
+	if _, err := PatchUser(context.Background(), user.ID, map[string]interface{}{"version": 9}, nil); err == nil {
+		t.Error("不允许部分更新的字段应返回错误")
+	}
+	if _, err := PatchUser(context.Background(), user.ID, map[string]interface{}{}, nil); err == nil {
+		t.Error("没有字段时应返回错误")
+	}
+	stale := user.Version + 1
+	if _, err := PatchUser(context.Background(), user.ID, map[string]interface{}{"age": 1}, &stale); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("版本不一致时应返回 ErrVersionConflict，got %v", err)
+	}
+}
+
+func TestCheckUserColumns(t *testing.T) {
+	if err := ValidateUserColumns(); err != nil {
+		t.Fatalf("内置更新列应与模型一致: %v", err)
+	}
+
+	err := checkUserColumns([]string{"name", "nickname"})
+	if err == nil {
+		t.Fatal("不存在的列应返回错误")
+	}
+	if want := "User 模型中不存在列 nickname（表 users）"; err.Error() != want {
+		t.Errorf("错误信息 = %q, want %q", err.Error(), want)
+	}
+}
@@ -0,0 +1,125 @@
+package logic
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"gin-project/config"
+	"gin-project/pkg"
+
+	"github.com/google/uuid"
+	"github.com/imroc/req/v3"
+)
+
+const (
+	// WebhookSignatureHeader 请求体的 HMAC-SHA256 签名（sha256=<hex>），接收方使用相同密钥校验
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	// WebhookEventHeader 事件类型
+	WebhookEventHeader = "X-Webhook-Event"
+	// WebhookIDHeader 投递ID，重试时不变，接收方据此去重
+	WebhookIDHeader = "X-Webhook-ID"
+
+	// DefaultWebhookRetries webhook 投递失败后的默认重试次数
+	DefaultWebhookRetries = 3
+	// DefaultWebhookTimeout webhook 单次投递默认超时
+	DefaultWebhookTimeout = 5 * time.Second
+)
+
+// webhookPayload webhook 请求体
+type webhookPayload struct {
+	ID string `json:"id"` // 投递ID
+	UserEvent
+}
+
+// InitWebhooks 根据 webhook 配置订阅用户事件，事件发布后异步投递到所有配置的地址
+// 未配置地址时不订阅
+func InitWebhooks(cfg config.Webhook) {
+	if len(cfg.URLs) == 0 {
+		return
+	}
+	if cfg.Secret == "" {
+		log.Println("警告: webhook.secret 未配置，webhook 请求将不签名")
+	}
+
+	client := pkg.NewHTTPClient(durationOrDefault(cfg.Timeout, DefaultWebhookTimeout))
+	retries := cfg.Retries
+	if retries <= 0 {
+		retries = DefaultWebhookRetries
+	}
+
+	OnUserEvent(func(ctx context.Context, event UserEvent) {
+		payload := webhookPayload{ID: uuid.NewString(), UserEvent: event}
+		body, err := json.Marshal(payload)
+		if err != nil {
+			pkg.LoggerFromContext(ctx).Error("序列化 webhook 请求体失败", "event", event.Type, "error", err)
+			return
+		}
+
+		for _, url := range cfg.URLs {
+			url := url
+			submitted := pkg.Background().Submit(ctx, func(ctx context.Context) {
+				deliverWebhook(ctx, client, url, payload.ID, event.Type, body, cfg.Secret, retries)
+			})
+			if !submitted {
+				deadLetterWebhook(ctx, url, payload.ID, event.Type, body, "后台任务队列已满")
+			}
+		}
+	})
+	log.Printf("用户事件 webhook 已启用：%d 个投递地址", len(cfg.URLs))
+}
+
+// deliverWebhook 投递 webhook（使用带追踪的 HTTP 客户端），网络错误、5xx、429 时按退避重试，最终失败时写入死信日志
+func deliverWebhook(ctx context.Context, client *req.Client, url, id string, eventType UserEventType, body []byte, secret string, retries int) {
+	request := client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetHeader(WebhookIDHeader, id).
+		SetHeader(WebhookEventHeader, string(eventType)).
+		SetBodyBytes(body).
+		SetRetryCount(retries).
+		SetRetryBackoffInterval(200*time.Millisecond, 5*time.Second).
+		SetRetryCondition(func(resp *req.Response, err error) bool {
+			return err != nil || resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+		})
+	if secret != "" {
+		request.SetHeader(WebhookSignatureHeader, SignWebhook(secret, body))
+	}
+
+	resp, err := request.Post(url)
+	if err != nil {
+		deadLetterWebhook(ctx, url, id, eventType, body, err.Error())
+		return
+	}
+	if !resp.IsSuccessState() {
+		deadLetterWebhook(ctx, url, id, eventType, body, resp.Status)
+		return
+	}
+	pkg.LoggerFromContext(ctx).Debug("webhook 投递成功", "url", url, "id", id, "event", eventType)
+}
+
+// SignWebhook 计算 webhook 请求体签名：sha256=<hex(HMAC-SHA256(secret, body))>
+func SignWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// deadLetterWebhook 记录最终投递失败的 webhook（死信日志，包含完整请求体，便于人工补发）
+func deadLetterWebhook(ctx context.Context, url, id string, eventType UserEventType, body []byte, reason string) {
+	pkg.LoggerFromContext(ctx).Error("webhook 投递失败（死信）",
+		"url", url, "id", id, "event", eventType, "reason", reason, "body", string(body))
+}
+
+// durationOrDefault 配置值大于 0 时返回配置值，否则返回默认值
+func durationOrDefault(value, fallback time.Duration) time.Duration {
+	if value > 0 {
+		return value
+	}
+	return fallback
+}
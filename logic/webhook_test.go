@@ -0,0 +1,136 @@
+package logic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gin-project/config"
+	"gin-project/model"
+)
+
+// webhookRequest webhook 接收方收到的请求
+type webhookRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// webhookReceiver 启动 webhook 接收方，按 statuses 顺序返回状态码（用完后返回最后一个），收到的请求发送到返回的 channel
+func webhookReceiver(t *testing.T, statuses ...int) (string, <-chan webhookRequest) {
+	t.Helper()
+	received := make(chan webhookRequest, 16)
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		status := statuses[0]
+		if len(statuses) > 1 {
+			statuses = statuses[1:]
+		}
+		mu.Unlock()
+		received <- webhookRequest{header: r.Header.Clone(), body: body}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL, received
+}
+
+// useWebhooks 按 cfg 订阅用户事件，测试结束时移除订阅
+func useWebhooks(t *testing.T, cfg config.Webhook) {
+	t.Helper()
+	userEventMu.Lock()
+	previous := userEventHandlers
+	userEventMu.Unlock()
+	t.Cleanup(func() {
+		userEventMu.Lock()
+		userEventHandlers = previous
+		userEventMu.Unlock()
+	})
+	InitWebhooks(cfg)
+}
+
+// nextWebhook 等待下一个 webhook 请求
+func nextWebhook(t *testing.T, received <-chan webhookRequest) webhookRequest {
+	t.Helper()
+	select {
+	case req := <-received:
+		return req
+	case <-time.After(3 * time.Second):
+		t.Fatal("等待 webhook 请求超时")
+		return webhookRequest{}
+	}
+}
+
+func TestSignWebhook(t *testing.T) {
+	// echo -n '{"id":"1"}' | openssl dgst -sha256 -hmac secret
+	got := SignWebhook("secret", []byte(`{"id":"1"}`))
+	if got != "sha256=6146142a2ce0159e84c0767881e4ec80bc397da62526e7d19f70795eb79460c0" {
+		t.Errorf("SignWebhook = %s", got)
+	}
+	if SignWebhook("other", []byte(`{"id":"1"}`)) == got {
+		t.Error("不同密钥的签名不应相同")
+	}
+}
+
+func TestWebhookDeliversSignedUserEvent(t *testing.T) {
+	setupStores(t)
+	url, received := webhookReceiver(t, http.StatusOK)
+	useWebhooks(t, config.Webhook{URLs: []string{url}, Secret: "s3cret"})
+
+	if err := CreateUser(context.Background(), &model.User{Name: "hook", Email: "hook@example.com"}); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	req := nextWebhook(t, received)
+
+	if got := req.header.Get(WebhookSignatureHeader); got != SignWebhook("s3cret", req.body) {
+		t.Errorf("签名 = %q, 与请求体不匹配", got)
+	}
+	if got := req.header.Get(WebhookEventHeader); got != string(UserCreated) {
+		t.Errorf("事件类型 = %q, want %s", got, UserCreated)
+	}
+	var payload webhookPayload
+	if err := json.Unmarshal(req.body, &payload); err != nil {
+		t.Fatalf("解析请求体失败: %v", err)
+	}
+	if payload.ID == "" || payload.ID != req.header.Get(WebhookIDHeader) {
+		t.Errorf("投递ID = %q, header = %q", payload.ID, req.header.Get(WebhookIDHeader))
+	}
+	if payload.Type != UserCreated || payload.User.Email != "hook@example.com" {
+		t.Errorf("payload = %+v", payload)
+	}
+}
+
+func TestWebhookRetriesThenDeadLetters(t *testing.T) {
+	setupStores(t)
+	var logs bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	url, received := webhookReceiver(t, http.StatusInternalServerError)
+	useWebhooks(t, config.Webhook{URLs: []string{url}, Retries: 1})
+
+	if err := CreateUser(context.Background(), &model.User{Name: "dead", Email: "dead@example.com"}); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	first, retry := nextWebhook(t, received), nextWebhook(t, received)
+	if first.header.Get(WebhookIDHeader) != retry.header.Get(WebhookIDHeader) {
+		t.Error("重试时投递ID应保持不变")
+	}
+	if first.header.Get(WebhookSignatureHeader) != "" {
+		t.Error("未配置密钥时不应签名")
+	}
+
+	drainBackground(t)
+	if !strings.Contains(logs.String(), "webhook 投递失败（死信）") || !strings.Contains(logs.String(), "dead@example.com") {
+		t.Errorf("重试耗尽后应写入包含请求体的死信日志，logs = %s", logs.String())
+	}
+}
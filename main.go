@@ -1,28 +1,111 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"gin-project/config"
 	"gin-project/database"
+	"gin-project/downstream"
+	"gin-project/logic"
 	"gin-project/middleware"
+	"gin-project/model"
 	"gin-project/pkg"
+	"gin-project/pkg/grpcclient"
+	"gin-project/pkg/metrics"
 	"gin-project/router"
 	"log"
+	"math"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
+// version 应用版本（与 Swagger 文档版本一致），用于出站请求的默认 User-Agent
+const version = "1.0"
+
+// main 应用入口（以下为 Swagger 文档全局信息）
+//
+//	@title			Gin项目 - 用户管理API
+//	@version		1.0
+//	@description	基于 Gin、GORM 和 Redis 的用户管理 API，所有响应使用统一的 APIResponse 格式（包含 trace_id）
+//	@BasePath		/
 func main() {
+	if err := run(); err != nil {
+		log.Printf("服务器异常退出: %v", err)
+		os.Exit(1)
+	}
+}
+
+// run 初始化各组件并启动服务器，收到 SIGINT/SIGTERM 后优雅关闭
+// 返回后 main 才调用 os.Exit，确保这里注册的 defer（排空后台任务、取消订阅、关闭追踪等）全部执行
+func run() error {
 	// 加载配置文件
 	config.LoadConfig()
 
+	// 初始化指标（必须在追踪和数据库之前，导出错误和连接池指标在初始化时注册）
+	metrics.Init(config.Cfg.Metrics.Enabled)
+
 	// 初始化追踪（必须在数据库和HTTP客户端之前）
 	middleware.InitTracing(config.Cfg)
 
+	// 初始化出站重试预算（所有 HTTP 客户端共享，httpClient.retryBudget.rate 为 0 时不限制）
+	pkg.InitRetryBudget(config.Cfg.HTTPClient.RetryBudget.Rate, retryBudgetBurst(config.Cfg.HTTPClient.RetryBudget))
+
+	// 初始化请求体脱敏规则（必须在 HTTP 客户端和路由之前，失败请求记录和出站报文共用）
+	pkg.InitRedaction(config.Cfg.Log.RedactFields)
+
 	// 初始化 HTTP 客户端（根据追踪开关优化性能）
-	pkg.InitHTTPClient(config.Cfg.Tracing.Enabled)
+	pkg.InitHTTPClient(config.Cfg.Tracing.Enabled, httpClientOptions(config.Cfg)...)
+
+	// 初始化 gRPC 客户端（内部服务调用，根据追踪开关优化性能）
+	grpcclient.Init(config.Cfg.Tracing.Enabled, grpcTarget(config.Cfg.App.GRPCPort))
+
+	// 初始化存活看门狗（health.watchdog.enabled 开启时）
+	watchdogCfg := config.Cfg.Health.Watchdog
+	pkg.InitWatchdog(watchdogCfg.Enabled, watchdogCfg.Interval, watchdogCfg.Threshold)
+
+	// 初始化后台任务执行器（有界 worker 池，用于异步缓存写入等）
+	pkg.InitBackgroundRunner(config.Cfg.Background.Workers, config.Cfg.Background.QueueSize, config.Cfg.Background.TaskTimeout)
 
 	// 初始化数据库连接（根据追踪开关优化性能）
 	database.InitMysql(config.Cfg)
 	database.InitRedis(config.Cfg)
+	stopRedisHealthCheck := database.StartRedisHealthCheck(config.Cfg.Redis.HealthCheckInterval)
+	defer stopRedisHealthCheck()
+
+	// 预热 MySQL、Redis 连接池（database.mysql.warmup 开启时）
+	database.Warmup(config.Cfg)
+
+	// 表结构迁移（DryRun 模式下打印计划执行的 DDL 后退出）
+	runMigrations(config.Cfg.Database.Mysql)
+
+	// users 表写入后自动清除用户缓存（覆盖直接使用 GORM 的写路径）
+	if err := logic.RegisterUserCacheHooks(database.DB); err != nil {
+		log.Fatalf("注册缓存失效回调失败: %v", err)
+	}
+
+	// 校验更新使用的列与模型一致（尽早发现字段重命名）
+	if err := logic.ValidateUserColumns(); err != nil {
+		log.Fatalf("模型校验失败: %v", err)
+	}
+
+	// 订阅用户事件并投递 webhook（webhook.urls 配置时）
+	logic.InitWebhooks(config.Cfg.Webhook)
+
+	// 订阅缓存失效通知（多实例部署时同步删除各实例的进程内缓存）
+	unsubscribe := logic.SubscribeUserInvalidation(context.Background())
+	defer unsubscribe()
+
+	// 启动演示下游服务（app.demoDownstream.enabled 开启时）
+	startDemoDownstream(config.Cfg.App.DemoDownstream)
+
+	// 维护模式初始状态（运行时可通过 PUT /admin/maintenance 切换）
+	pkg.SetMaintenance(config.Cfg.App.Maintenance.Enabled)
 
 	// 创建路由
 	r := router.SetupRouter()
@@ -33,6 +116,9 @@ func main() {
 		port = "8080"
 	}
 
+	// 启动诊断：汇总生效配置和依赖连接状态（health.startupCheck.failFast 开启时依赖不可用即退出）
+	runStartupDiagnostics(config.Cfg, port)
+
 	// 确保在程序退出时关闭追踪提供者
 	defer func() {
 		if config.Cfg.Tracing.Cleanup != nil {
@@ -40,10 +126,230 @@ func main() {
 		}
 	}()
 
+	// 确保在程序退出时排空后台任务（在关闭追踪提供者之前执行，后台任务的 span 仍能导出）
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := pkg.Background().Shutdown(ctx); err != nil {
+			log.Printf("后台任务未能全部完成: %v", err)
+		}
+	}()
+
 	// 启动服务器
-	log.Printf("服务器启动在端口: %s", port)
-	if err := r.Run(":" + port); err != nil {
-		log.Printf("服务器启动失败: %v", err)
-		os.Exit(1)
+	srv := newServer(":"+port, serverHandler(r, config.Cfg.App), config.Cfg.App.Server)
+
+	tlsCfg := config.Cfg.App.TLS
+	if tlsCfg.Enabled {
+		// 启动前校验证书文件，快速失败并给出明确提示
+		if err := checkTLSFiles(tlsCfg.CertFile, tlsCfg.KeyFile); err != nil {
+			return fmt.Errorf("TLS 配置错误: %w", err)
+		}
+	}
+
+	// 收到 SIGINT/SIGTERM 时停止接收新请求，等待进行中的请求完成后返回，再依次执行上面注册的 defer
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		if tlsCfg.Enabled {
+			log.Printf("服务器启动在端口: %s（HTTPS）", port)
+			serveErr <- srv.ListenAndServeTLS(tlsCfg.CertFile, tlsCfg.KeyFile)
+			return
+		}
+		log.Printf("服务器启动在端口: %s", port)
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("服务器启动失败: %w", err)
+	case <-ctx.Done():
+	}
+
+	log.Println("收到退出信号，开始优雅关闭")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), durationOr(config.Cfg.App.Server.ShutdownTimeout, defaultShutdownTimeout))
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("优雅关闭超时: %w", err)
+	}
+	log.Println("服务器已停止接收请求")
+	return nil
+}
+
+// 服务器默认超时与请求头限制（app.server 未配置时使用）
+const (
+	defaultReadHeaderTimeout = 5 * time.Second
+	defaultReadTimeout       = 30 * time.Second
+	defaultWriteTimeout      = 60 * time.Second
+	defaultIdleTimeout       = 120 * time.Second
+	defaultMaxHeaderBytes    = 1 << 20
+	defaultShutdownTimeout   = 15 * time.Second
+)
+
+// newServer 创建 HTTP 服务器，按 app.server 配置超时和请求头大小限制（未配置时使用默认值）
+// 避免慢速客户端（如 slowloris）长时间占用连接
+func newServer(addr string, handler http.Handler, cfg config.Server) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: durationOr(cfg.ReadHeaderTimeout, defaultReadHeaderTimeout),
+		ReadTimeout:       durationOr(cfg.ReadTimeout, defaultReadTimeout),
+		WriteTimeout:      durationOr(cfg.WriteTimeout, defaultWriteTimeout),
+		IdleTimeout:       durationOr(cfg.IdleTimeout, defaultIdleTimeout),
+		MaxHeaderBytes:    intOr(cfg.MaxHeaderBytes, defaultMaxHeaderBytes),
+	}
+}
+
+// serverHandler 按 app 配置包装路由：去除路径末尾斜杠（app.trimTrailingSlash）、明文 HTTP/2（app.http2.h2c）
+func serverHandler(r http.Handler, cfg config.App) http.Handler {
+	handler := r
+	if cfg.TrimTrailingSlash {
+		// 路由匹配前去除路径末尾斜杠，/api/user/query/ 与 /api/user/query 命中同一路由
+		handler = middleware.TrimTrailingSlash(handler)
+	}
+	if cfg.HTTP2.H2C {
+		// 包装为 h2c 处理器，仅改变传输协议，中间件链（包括追踪）不受影响
+		handler = h2c.NewHandler(handler, &http2.Server{})
+		log.Println("已启用明文 HTTP/2（h2c）")
+	}
+	return handler
+}
+
+// durationOr 配置值大于 0 时返回配置值，否则返回默认值
+func durationOr(value, fallback time.Duration) time.Duration {
+	if value > 0 {
+		return value
+	}
+	return fallback
+}
+
+// intOr 配置值大于 0 时返回配置值，否则返回默认值
+func intOr(value, fallback int) int {
+	if value > 0 {
+		return value
+	}
+	return fallback
+}
+
+// runMigrations 根据 database.mysql.autoMigrate / migrateDryRun 执行表结构迁移
+func runMigrations(cfg config.Mysql) {
+	if !cfg.AutoMigrate && !cfg.MigrateDryRun {
+		return
+	}
+
+	statements, err := database.Migrate(database.DB, cfg.MigrateDryRun, &model.User{}, &model.AuditLog{})
+	if err != nil {
+		log.Fatalf("表结构迁移失败: %v", err)
+	}
+
+	if !cfg.MigrateDryRun {
+		log.Println("表结构迁移完成")
+		return
+	}
+
+	if len(statements) == 0 {
+		log.Println("[DryRun] 表结构已是最新，无需迁移")
+	}
+	for _, stmt := range statements {
+		log.Printf("[DryRun] %s;", stmt)
+	}
+	os.Exit(0)
+}
+
+// startDemoDownstream 在独立端口后台启动演示下游服务（模拟服务C）
+func startDemoDownstream(cfg config.DemoDownstream) {
+	if !cfg.Enabled {
+		return
+	}
+
+	port := cfg.Port
+	if port == "" {
+		port = "8081"
+	}
+
+	srv := newServer(":"+port, downstream.SetupRouter(), config.Cfg.App.Server)
+	go func() {
+		log.Printf("演示下游服务启动在端口: %s", port)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Printf("演示下游服务启动失败: %v", err)
+		}
+	}()
+}
+
+// checkTLSFiles 校验证书和私钥文件是否配置且可读
+func checkTLSFiles(certFile, keyFile string) error {
+	files := []struct {
+		name string
+		path string
+	}{
+		{"证书文件 app.tls.certFile", certFile},
+		{"私钥文件 app.tls.keyFile", keyFile},
+	}
+
+	for _, file := range files {
+		if file.path == "" {
+			return fmt.Errorf("%s 未配置", file.name)
+		}
+		f, err := os.Open(file.path)
+		if err != nil {
+			return fmt.Errorf("%s 不可读: %v", file.name, err)
+		}
+		f.Close()
+	}
+	return nil
+}
+
+// retryBudgetBurst 获取重试预算突发上限（httpClient.retryBudget.burst），未配置时等于每秒重试次数（至少为 1）
+func retryBudgetBurst(cfg config.RetryBudget) int {
+	if cfg.Burst > 0 {
+		return cfg.Burst
+	}
+	if burst := int(math.Ceil(cfg.Rate)); burst > 0 {
+		return burst
+	}
+	return 1
+}
+
+// httpClientOptions 根据 httpClient 配置生成 HTTP 客户端选项
+// 报文记录（httpClient.dump）仅在 debug 模式下生效，避免在 release 模式泄露数据
+func httpClientOptions(appCfg *config.Config) []pkg.HTTPClientOption {
+	cfg := appCfg.HTTPClient
+	mode := appCfg.App.Mode
+
+	minBackoff := durationOr(cfg.RetryMinBackoff, pkg.DefaultRetryMinBackoff)
+	maxBackoff := durationOr(cfg.RetryMaxBackoff, pkg.DefaultRetryMaxBackoff)
+	// 未配置 User-Agent 时默认为 <app.name>/<version>，如 gin-project/1.0
+	userAgent := cfg.UserAgent
+	if userAgent == "" {
+		name := appCfg.App.Name
+		if name == "" {
+			name = "gin-project"
+		}
+		userAgent = name + "/" + version
+	}
+
+	opts := []pkg.HTTPClientOption{
+		pkg.WithRetry(cfg.RetryCount, minBackoff, maxBackoff),
+		pkg.WithUserAgent(userAgent),
+		pkg.WithHeaders(cfg.Headers),
+	}
+
+	if cfg.Dump {
+		if mode == "debug" {
+			opts = append(opts, pkg.WithDump())
+			log.Println("已开启出站 HTTP 报文记录（仅 debug 模式）")
+		} else {
+			log.Println("httpClient.dump 仅在 debug 模式下生效，已忽略")
+		}
+	}
+	return opts
+}
+
+// grpcTarget 根据 app.grpcPort 生成内部 gRPC 服务地址，未配置时返回空
+func grpcTarget(port string) string {
+	if port == "" {
+		return ""
 	}
+	return "localhost:" + port
 }
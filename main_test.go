@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gin-project/config"
+	"gin-project/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/imroc/req/v3"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	sdktracetest "go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"golang.org/x/net/http2"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// writeSelfSignedCert 生成 127.0.0.1 的自签名证书和私钥文件，返回文件路径和证书
+func writeSelfSignedCert(t *testing.T) (certFile, keyFile string, cert *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "gin-project-test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err = x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	writePEM(t, certFile, "CERTIFICATE", der)
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyDER)
+	return certFile, keyFile, cert
+}
+
+// writePEM 将 DER 数据以 PEM 格式写入文件
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// listen 在随机端口监听本地地址
+func listen(t *testing.T) net.Listener {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ln
+}
+
+func TestCheckTLSFiles(t *testing.T) {
+	certFile, keyFile, _ := writeSelfSignedCert(t)
+
+	if err := checkTLSFiles(certFile, keyFile); err != nil {
+		t.Errorf("证书文件可读时不应返回错误: %v", err)
+	}
+	if err := checkTLSFiles("", keyFile); err == nil || !strings.Contains(err.Error(), "app.tls.certFile 未配置") {
+		t.Errorf("未配置证书时应明确提示，got %v", err)
+	}
+	missing := filepath.Join(t.TempDir(), "missing.pem")
+	if err := checkTLSFiles(certFile, missing); err == nil || !strings.Contains(err.Error(), "app.tls.keyFile 不可读") {
+		t.Errorf("私钥不可读时应明确提示，got %v", err)
+	}
+}
+
+func TestServerServesTLSWithSelfSignedCert(t *testing.T) {
+	certFile, keyFile, cert := writeSelfSignedCert(t)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil {
+			t.Error("请求应通过 TLS 到达")
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	ln := listen(t)
+	srv := newServer(ln.Addr().String(), handler, config.Server{})
+	go func() { _ = srv.ServeTLS(ln, certFile, keyFile) }()
+	t.Cleanup(func() { _ = srv.Close() })
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+	resp, err := client.Get("https://" + ln.Addr().String() + "/")
+	if err != nil {
+		t.Fatalf("HTTPS 请求失败: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("status = %d, want 204", resp.StatusCode)
+	}
+}
+
+func TestNewServerAppliesTimeouts(t *testing.T) {
+	srv := newServer(":0", http.NotFoundHandler(), config.Server{
+		ReadHeaderTimeout: 2 * time.Second,
+		ReadTimeout:       10 * time.Second,
+		WriteTimeout:      20 * time.Second,
+		IdleTimeout:       30 * time.Second,
+		MaxHeaderBytes:    4096,
+	})
+	if srv.ReadHeaderTimeout != 2*time.Second || srv.ReadTimeout != 10*time.Second ||
+		srv.WriteTimeout != 20*time.Second || srv.IdleTimeout != 30*time.Second || srv.MaxHeaderBytes != 4096 {
+		t.Errorf("server = %+v, 未使用配置的超时", srv)
+	}
+
+	// 未配置时使用安全默认值，不能为 0（0 表示不限制）
+	srv = newServer(":0", http.NotFoundHandler(), config.Server{})
+	if srv.ReadHeaderTimeout != defaultReadHeaderTimeout || srv.ReadTimeout != defaultReadTimeout ||
+		srv.WriteTimeout != defaultWriteTimeout || srv.IdleTimeout != defaultIdleTimeout || srv.MaxHeaderBytes != defaultMaxHeaderBytes {
+		t.Errorf("server = %+v, 未使用默认值", srv)
+	}
+}
+
+// recordSpans 将 HTTP 请求追踪器替换为内存 span 记录器，测试结束时恢复为未启用追踪
+func recordSpans(t *testing.T) *sdktracetest.SpanRecorder {
+	t.Helper()
+	recorder := sdktracetest.NewSpanRecorder()
+	middleware.UseTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)), "main-test")
+	t.Cleanup(func() { middleware.InitTracing(&config.Config{}) })
+	return recorder
+}
+
+func TestServerHandlerServesH2C(t *testing.T) {
+	recorder := recordSpans(t)
+	r := gin.New()
+	r.Use(middleware.TracingMiddleware())
+	r.GET("/ping", func(c *gin.Context) { c.String(http.StatusOK, c.Request.Proto) })
+
+	ln := listen(t)
+	srv := newServer(ln.Addr().String(), serverHandler(r, config.App{HTTP2: config.HTTP2{H2C: true}}), config.Server{})
+	go func() { _ = srv.Serve(ln) }()
+	t.Cleanup(func() { _ = srv.Close() })
+
+	// 明文 HTTP/2 客户端（不经过 TLS 协商，直接使用 HTTP/2 帧）
+	client := &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		},
+	}}
+	resp, err := client.Get("http://" + ln.Addr().String() + "/ping")
+	if err != nil {
+		t.Fatalf("h2c 请求失败: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || resp.ProtoMajor != 2 {
+		t.Errorf("status = %d, proto = %s, want 200 over HTTP/2", resp.StatusCode, resp.Proto)
+	}
+	if resp.Header.Get(middleware.RequestIDHeader) == "" {
+		t.Error("h2c 响应应携带追踪中间件写入的请求ID")
+	}
+	if spans := recorder.Ended(); len(spans) != 1 || spans[0].Name() != "/ping" {
+		t.Errorf("h2c 请求应产生一个服务端 span，got %d", len(spans))
+	}
+}
+
+func TestHTTPClientDumpOnlyInDebug(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"code":0,"data":{"token":"resp-secret"}}`))
+	}))
+	defer server.Close()
+
+	for mode, wantDump := range map[string]bool{"debug": true, "release": false} {
+		var logs bytes.Buffer
+		previous := slog.Default()
+		slog.SetDefault(slog.New(slog.NewJSONHandler(&logs, nil)))
+
+		client := req.C()
+		for _, opt := range httpClientOptions(&config.Config{App: config.App{Mode: mode}, HTTPClient: config.HTTPClient{Dump: true}}) {
+			opt(client)
+		}
+		_, err := client.R().
+			SetHeader("Authorization", "Bearer req-secret").
+			SetBodyJsonString(`{"password":"body-secret","name":"alice"}`).
+			Post(server.URL + "/api/process")
+		slog.SetDefault(previous)
+		if err != nil {
+			t.Fatalf("%s: 请求失败: %v", mode, err)
+		}
+
+		dumped := strings.Contains(logs.String(), "出站 HTTP 报文")
+		if dumped != wantDump {
+			t.Errorf("%s 模式: 是否记录报文 = %v, want %v, logs = %s", mode, dumped, wantDump, logs.String())
+		}
+		for _, secret := range []string{"req-secret", "body-secret", "resp-secret"} {
+			if strings.Contains(logs.String(), secret) {
+				t.Errorf("%s 模式: 报文未脱敏 %s", mode, secret)
+			}
+		}
+		if wantDump && !strings.Contains(logs.String(), "alice") {
+			t.Errorf("%s 模式: 报文缺少请求体: %s", mode, logs.String())
+		}
+	}
+}
+
+func TestHTTPClientSendsUserAgentAndHeaders(t *testing.T) {
+	received := make(chan http.Header, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Clone()
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		cfg           *config.Config
+		wantUserAgent string
+	}{
+		{"默认", &config.Config{}, "gin-project/" + version},
+		{"按应用名", &config.Config{App: config.App{Name: "orders"}}, "orders/" + version},
+		{"配置覆盖", &config.Config{HTTPClient: config.HTTPClient{
+			UserAgent: "custom/2.0",
+			Headers:   map[string]string{"X-Caller": "gin-project"},
+		}}, "custom/2.0"},
+	}
+	for _, tt := range tests {
+		client := req.C()
+		for _, opt := range httpClientOptions(tt.cfg) {
+			opt(client)
+		}
+		if _, err := client.R().Get(server.URL); err != nil {
+			t.Fatalf("%s: 请求失败: %v", tt.name, err)
+		}
+		header := <-received
+		if got := header.Get("User-Agent"); got != tt.wantUserAgent {
+			t.Errorf("%s: User-Agent = %q, want %q", tt.name, got, tt.wantUserAgent)
+		}
+		for key, want := range tt.cfg.HTTPClient.Headers {
+			if got := header.Get(key); got != want {
+				t.Errorf("%s: %s = %q, want %q", tt.name, key, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"gin-project/config"
+	"gin-project/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireBearerToken Bearer Token 校验中间件
+// 请求头 Authorization 必须为 "Bearer <token>"，否则返回 401（统一响应格式）
+// token 为空时不做校验，直接放行（便于本地调试）
+func RequireBearerToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.Next()
+			return
+		}
+
+		provided, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			baseCtrl := &controller.BaseController{}
+			baseCtrl.Error(c, http.StatusUnauthorized, "未授权：缺少或无效的 Bearer Token")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireAdminToken 管理接口令牌校验中间件（未配置令牌时拒绝访问）
+// 配置了 token 时与 RequireBearerToken 相同；token 为空时仅在 debug 模式下放行（本地调试），
+// 其他模式返回 403，避免忘记配置 app.adminToken 时管理接口在生产环境对外开放
+func RequireAdminToken(token string) gin.HandlerFunc {
+	if token != "" {
+		return RequireBearerToken(token)
+	}
+	debug := config.Cfg != nil && config.Cfg.App.Mode == "debug"
+	return func(c *gin.Context) {
+		if debug {
+			c.Next()
+			return
+		}
+		baseCtrl := &controller.BaseController{}
+		baseCtrl.Error(c, http.StatusForbidden, "禁止访问：未配置 app.adminToken，管理接口已关闭")
+		c.Abort()
+	}
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gin-project/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequireBearerToken(t *testing.T) {
+	tests := []struct {
+		name          string
+		token         string
+		authorization string
+		want          int
+	}{
+		{"未配置令牌时放行", "", "", http.StatusOK},
+		{"令牌正确", "s3cret", "Bearer s3cret", http.StatusOK},
+		{"缺少请求头", "s3cret", "", http.StatusUnauthorized},
+		{"令牌错误", "s3cret", "Bearer wrong", http.StatusUnauthorized},
+		{"缺少 Bearer 前缀", "s3cret", "s3cret", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		r := gin.New()
+		r.GET("/debug/pprof/", RequireBearerToken(tt.token), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		req := httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil)
+		if tt.authorization != "" {
+			req.Header.Set("Authorization", tt.authorization)
+		}
+		if w := serve(r, req); w.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, w.Code, tt.want)
+		}
+	}
+}
+
+func TestRequireAdminTokenWithoutToken(t *testing.T) {
+	for mode, want := range map[string]int{"debug": http.StatusOK, "release": http.StatusForbidden} {
+		useConfig(t, &config.Config{App: config.App{Mode: mode}})
+		r := gin.New()
+		r.GET("/admin", RequireAdminToken(""), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		if w := serve(r, httptest.NewRequest(http.MethodGet, "/admin", nil)); w.Code != want {
+			t.Errorf("%s 模式未配置令牌: status = %d, want %d", mode, w.Code, want)
+		}
+	}
+}
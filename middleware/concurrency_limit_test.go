@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// blockingEngine 创建带追踪、受 ConcurrencyLimit 限制的路由，处理函数在 release 关闭前阻塞，每进入一个请求向 entered 发送一次
+func blockingEngine(limit gin.HandlerFunc) (r *gin.Engine, entered chan struct{}, release chan struct{}) {
+	entered = make(chan struct{}, 16)
+	release = make(chan struct{})
+	r = gin.New()
+	r.Use(TracingMiddleware())
+	r.POST("/bulk", limit, func(c *gin.Context) {
+		entered <- struct{}{}
+		<-release
+		c.Status(http.StatusOK)
+	})
+	return r, entered, release
+}
+
+// fillSlots 并发发送 n 个请求并等待它们全部进入处理函数，返回等待这些请求完成的函数
+func fillSlots(t *testing.T, r *gin.Engine, entered chan struct{}, n int) func() []int {
+	t.Helper()
+	var wg sync.WaitGroup
+	codes := make([]int, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			codes[i] = serve(r, httptest.NewRequest(http.MethodPost, "/bulk", nil)).Code
+		}(i)
+	}
+	for i := 0; i < n; i++ {
+		select {
+		case <-entered:
+		case <-time.After(2 * time.Second):
+			t.Fatal("等待请求进入处理函数超时")
+		}
+	}
+	return func() []int {
+		wg.Wait()
+		return codes
+	}
+}
+
+func TestConcurrencyLimitRejectsWhenFull(t *testing.T) {
+	recorder := recordSpans(t)
+	r, entered, release := blockingEngine(ConcurrencyLimit(2))
+	wait := fillSlots(t, r, entered, 2)
+
+	before := testutil.ToFloat64(concurrencyRejected.WithLabelValues("/bulk"))
+	w := serve(r, httptest.NewRequest(http.MethodPost, "/bulk", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("超过并发上限 status = %d, want 503", w.Code)
+	}
+	if got := testutil.ToFloat64(concurrencyRejected.WithLabelValues("/bulk")) - before; got != 1 {
+		t.Errorf("拒绝计数增加 %v, want 1", got)
+	}
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("span 数 = %d, want 1（被拒绝的请求）", len(spans))
+	}
+	if rejected, _ := spanAttribute(spans[0], "concurrency.rejected"); !rejected.AsBool() {
+		t.Error("被拒绝请求的 span 应记录 concurrency.rejected")
+	}
+
+	close(release)
+	for i, code := range wait() {
+		if code != http.StatusOK {
+			t.Errorf("请求 %d status = %d, want 200", i, code)
+		}
+	}
+
+	// 名额释放后可以继续处理
+	if w := serve(r, httptest.NewRequest(http.MethodPost, "/bulk", nil)); w.Code != http.StatusOK {
+		t.Errorf("名额释放后 status = %d, want 200", w.Code)
+	}
+}
+
+func TestConcurrencyLimitWaitsForSlot(t *testing.T) {
+	recordSpans(t)
+	r, entered, release := blockingEngine(ConcurrencyLimit(1, WithConcurrencyWait(2*time.Second)))
+	wait := fillSlots(t, r, entered, 1)
+
+	done := make(chan int)
+	go func() { done <- serve(r, httptest.NewRequest(http.MethodPost, "/bulk", nil)).Code }()
+	select {
+	case code := <-done:
+		t.Fatalf("名额被占用时应等待，却立即返回 %d", code)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if code := <-done; code != http.StatusOK {
+		t.Errorf("等待到名额后 status = %d, want 200", code)
+	}
+	wait()
+}
+
+func TestConcurrencyLimitWaitTimesOut(t *testing.T) {
+	recordSpans(t)
+	r, entered, release := blockingEngine(ConcurrencyLimit(1, WithConcurrencyWait(20*time.Millisecond)))
+	wait := fillSlots(t, r, entered, 1)
+	defer wait()
+	defer close(release)
+
+	if w := serve(r, httptest.NewRequest(http.MethodPost, "/bulk", nil)); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("等待超时后 status = %d, want 503", w.Code)
+	}
+}
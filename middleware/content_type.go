@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"mime"
+	"net/http"
+
+	"gin-project/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireJSON JSON 请求校验中间件
+// POST/PUT/PATCH 请求的 Content-Type 必须为 application/json，否则返回 415（统一响应格式）
+// 其他方法（GET/DELETE 等）不需要请求体，直接放行
+func RequireJSON() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+		default:
+			c.Next()
+			return
+		}
+
+		mediaType, _, err := mime.ParseMediaType(c.GetHeader("Content-Type"))
+		if err != nil || mediaType != "application/json" {
+			baseCtrl := &controller.BaseController{}
+			baseCtrl.Error(c, http.StatusUnsupportedMediaType, "Content-Type 必须为 application/json")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gin-project/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequireJSON(t *testing.T) {
+	r := gin.New()
+	r.Use(RequireJSON())
+	r.Any("/users", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		method      string
+		contentType string
+		want        int
+	}{
+		{http.MethodPost, "text/plain", http.StatusUnsupportedMediaType},
+		{http.MethodPut, "", http.StatusUnsupportedMediaType},
+		{http.MethodPatch, "application/xml", http.StatusUnsupportedMediaType},
+		{http.MethodPost, "application/json", http.StatusOK},
+		{http.MethodPost, "application/json; charset=utf-8", http.StatusOK},
+		{http.MethodGet, "", http.StatusOK},
+		{http.MethodDelete, "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "/users", strings.NewReader(`{}`))
+		if tt.contentType != "" {
+			req.Header.Set("Content-Type", tt.contentType)
+		}
+		if w := serve(r, req); w.Code != tt.want {
+			t.Errorf("%s Content-Type=%q: status = %d, want %d", tt.method, tt.contentType, w.Code, tt.want)
+		}
+	}
+}
+
+func TestRequireJSONUsesEnvelope(t *testing.T) {
+	r := gin.New()
+	r.Use(RequireJSON())
+	r.POST("/users", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader("name=alice"))
+	req.Header.Set("Content-Type", "text/plain")
+	w := serve(r, req)
+
+	var resp controller.APIResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("415 响应应使用统一响应格式: %v, body = %s", err, w.Body.String())
+	}
+	if resp.Code != http.StatusUnsupportedMediaType || resp.Message == "" {
+		t.Errorf("响应 = %+v", resp)
+	}
+}
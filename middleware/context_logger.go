@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"log/slog"
+
+	"gin-project/pkg"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ContextLoggerMiddleware 请求级日志中间件
+// 为每个请求创建绑定 trace_id、request_id 的 *slog.Logger 并存入 context，
+// 逻辑层通过 pkg.LoggerFromContext(ctx) 获取，日志自动携带关联字段
+// 必须在 TracingMiddleware 之后注册，才能获取到 trace_id
+func ContextLoggerMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger := slog.Default()
+
+		spanCtx := trace.SpanFromContext(c.Request.Context()).SpanContext()
+		if spanCtx.IsValid() {
+			logger = logger.With("trace_id", spanCtx.TraceID().String())
+		}
+
+		// 请求ID：客户端传入，或由 TracingMiddleware 生成并写入响应头
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" {
+			requestID = c.Writer.Header().Get(RequestIDHeader)
+		}
+		if requestID != "" {
+			logger = logger.With("request_id", requestID)
+		}
+
+		c.Request = c.Request.WithContext(pkg.WithLogger(c.Request.Context(), logger))
+		c.Next()
+	}
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gin-project/database/dbtest"
+	"gin-project/logic"
+	"gin-project/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// captureLogs 将默认 slog 日志（Debug 级别，JSON 格式）重定向到缓冲区，测试结束时恢复
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &buf
+}
+
+func TestLogicLogsCarryTraceID(t *testing.T) {
+	recorder := recordSpans(t)
+	dbtest.Open(t, &model.User{})
+	mr := dbtest.Redis(t)
+	logs := captureLogs(t)
+
+	engine := gin.New()
+	engine.Use(TracingMiddleware(), ContextLoggerMiddleware())
+	engine.GET("/users/:id", func(c *gin.Context) {
+		_, err := logic.GetUserByID(c.Request.Context(), 1)
+		c.String(http.StatusOK, "%v", err)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req.Header.Set(RequestIDHeader, "req-logger")
+	serve(engine, req)
+
+	// 等待负缓存写入完成，避免后台任务在恢复 RedisClient 之后执行
+	deadline := time.Now().Add(2 * time.Second)
+	for len(mr.Keys()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("spans = %d, want 1", len(spans))
+	}
+	traceID := spans[0].SpanContext().TraceID().String()
+
+	var found bool
+	for _, line := range bytes.Split(bytes.TrimSpace(logs.Bytes()), []byte("\n")) {
+		var record map[string]interface{}
+		if err := json.Unmarshal(line, &record); err != nil {
+			t.Fatalf("解析日志失败: %v, line = %s", err, line)
+		}
+		if record["msg"] != "用户缓存未命中，查询数据库" {
+			continue
+		}
+		found = true
+		if record["trace_id"] != traceID {
+			t.Errorf("trace_id = %v, want %s", record["trace_id"], traceID)
+		}
+		if record["request_id"] != "req-logger" {
+			t.Errorf("request_id = %v, want req-logger", record["request_id"])
+		}
+	}
+	if !found {
+		t.Fatalf("未找到逻辑层日志, logs = %s", logs.String())
+	}
+}
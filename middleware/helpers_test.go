@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gin-project/config"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	sdktracetest "go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// useConfig 将全局配置替换为 cfg，测试结束时恢复
+func useConfig(t *testing.T, cfg *config.Config) {
+	t.Helper()
+	previous := config.Cfg
+	config.Cfg = cfg
+	t.Cleanup(func() { config.Cfg = previous })
+}
+
+// recordSpans 将 HTTP 请求追踪器替换为内存 span 记录器（始终采样），测试结束时恢复
+func recordSpans(t *testing.T) *sdktracetest.SpanRecorder {
+	t.Helper()
+	recorder := sdktracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithSpanProcessor(recorder),
+	)
+
+	previous := tracer
+	UseTracerProvider(provider, "middleware-test")
+	t.Cleanup(func() { tracer = previous })
+	return recorder
+}
+
+// serve 使用 engine 处理一次请求并返回响应
+func serve(engine *gin.Engine, req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	return w
+}
+
+// spanAttribute 查找 span 上指定键的属性值
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) (attribute.Value, bool) {
+	for _, attr := range span.Attributes() {
+		if attr.Key == key {
+			return attr.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
@@ -4,13 +4,24 @@ import (
 	"fmt"
 	"time"
 
+	"gin-project/config"
+	"gin-project/pkg"
+
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // LoggerMiddleware 日志中间件
 // 自定义日志格式，记录请求的详细信息
+// 配置 log.slowRequestThreshold 后，耗时超过阈值的请求额外输出一条 WARN 日志；
+// 开启 log.accessLogSampling 后访问日志跟随追踪采样决定：采样的请求和 5xx 请求输出完整日志，
+// 未采样的请求只输出方法、路径、状态码和耗时（未启用追踪时始终输出完整日志）
 func LoggerMiddleware() gin.HandlerFunc {
-	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
+	sampling := accessLogSampling()
+	accessLog := gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
+		if sampling && !fullAccessLog(param) {
+			return fmt.Sprintf("%s %s %d %s\n", param.Method, param.Path, param.StatusCode, param.Latency)
+		}
 		return fmt.Sprintf("%s - [%s] \"%s %s %s %d %s \"%s\" %s\"\n",
 			param.ClientIP,
 			param.TimeStamp.Format(time.RFC1123),
@@ -23,5 +34,54 @@ func LoggerMiddleware() gin.HandlerFunc {
 			param.ErrorMessage,
 		)
 	})
+
+	threshold := slowRequestThreshold()
+	if threshold <= 0 {
+		return accessLog
+	}
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
+
+		// 访问日志中间件内部会调用 c.Next() 执行后续处理
+		accessLog(c)
+
+		latency := time.Since(start)
+		if latency <= threshold {
+			return
+		}
+
+		// 请求级日志中间件在日志中间件之后执行，此时请求上下文中的日志记录器已绑定 trace_id、request_id
+		pkg.LoggerFromContext(c.Request.Context()).Warn("慢请求",
+			"method", c.Request.Method,
+			"path", path,
+			"status", c.Writer.Status(),
+			"latency", latency.String(),
+			"threshold", threshold.String(),
+		)
+	}
+}
+
+// fullAccessLog 是否输出完整访问日志：请求被追踪采样、返回 5xx，或没有追踪信息（未启用追踪）
+// 追踪中间件在日志中间件之后执行，格式化时请求上下文中已有 span
+func fullAccessLog(param gin.LogFormatterParams) bool {
+	if param.StatusCode >= 500 || param.Request == nil {
+		return true
+	}
+	spanCtx := trace.SpanFromContext(param.Request.Context()).SpanContext()
+	return !spanCtx.IsValid() || spanCtx.IsSampled()
 }
 
+// accessLogSampling 访问日志是否跟随追踪采样决定（log.accessLogSampling）
+func accessLogSampling() bool {
+	return config.Cfg != nil && config.Cfg.Log.AccessLogSampling
+}
+
+// slowRequestThreshold 获取慢请求阈值（log.slowRequestThreshold），未配置时不启用
+func slowRequestThreshold() time.Duration {
+	if config.Cfg == nil {
+		return 0
+	}
+	return config.Cfg.Log.SlowRequestThreshold
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gin-project/config"
+
+	"github.com/gin-gonic/gin"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestLoggerWarnsOnSlowRequest(t *testing.T) {
+	useConfig(t, &config.Config{Log: config.Log{SlowRequestThreshold: 20 * time.Millisecond}})
+	recorder := recordSpans(t)
+	logs := captureLogs(t)
+	previous := gin.DefaultWriter
+	gin.DefaultWriter = io.Discard
+	t.Cleanup(func() { gin.DefaultWriter = previous })
+
+	r := gin.New()
+	r.Use(LoggerMiddleware(), TracingMiddleware(), ContextLoggerMiddleware())
+	r.GET("/fast", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/slow", func(c *gin.Context) {
+		time.Sleep(40 * time.Millisecond)
+		c.Status(http.StatusOK)
+	})
+
+	serve(r, httptest.NewRequest(http.MethodGet, "/fast", nil))
+	if logs.Len() != 0 {
+		t.Fatalf("快请求不应输出慢请求日志: %s", logs.String())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/slow", nil)
+	req.Header.Set(RequestIDHeader, "req-slow")
+	serve(r, req)
+	var record map[string]interface{}
+	if err := json.Unmarshal(bytes.TrimSpace(logs.Bytes()), &record); err != nil {
+		t.Fatalf("解析日志失败: %v, logs = %s", err, logs.String())
+	}
+	if record["level"] != "WARN" || record["msg"] != "慢请求" || record["path"] != "/slow" {
+		t.Errorf("慢请求日志 = %v", record)
+	}
+	spans := recorder.Ended()
+	if traceID := spans[len(spans)-1].SpanContext().TraceID().String(); record["trace_id"] != traceID {
+		t.Errorf("trace_id = %v, want %s", record["trace_id"], traceID)
+	}
+	if record["request_id"] != "req-slow" {
+		t.Errorf("慢请求日志应使用请求级日志记录器，request_id = %v", record["request_id"])
+	}
+	if latency, err := time.ParseDuration(record["latency"].(string)); err != nil || latency < 40*time.Millisecond {
+		t.Errorf("latency = %v", record["latency"])
+	}
+}
+
+// accessLogLines 使用 sampler 追踪请求，逐个返回每个请求输出的访问日志
+func accessLogLines(t *testing.T, cfg *config.Config, sampler sdktrace.Sampler, paths ...string) []string {
+	t.Helper()
+	useConfig(t, cfg)
+	recordSampledSpans(t, sampler, false)
+	var out bytes.Buffer
+	previous := gin.DefaultWriter
+	gin.DefaultWriter = &out
+	t.Cleanup(func() { gin.DefaultWriter = previous })
+
+	r := gin.New()
+	r.Use(LoggerMiddleware(), TracingMiddleware())
+	r.GET("/ok", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/fail", func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+
+	lines := make([]string, 0, len(paths))
+	for _, path := range paths {
+		out.Reset()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("User-Agent", "logger-test")
+		serve(r, req)
+		lines = append(lines, out.String())
+	}
+	return lines
+}
+
+func TestAccessLogSamplingReducesUnsampledRequests(t *testing.T) {
+	lines := accessLogLines(t, &config.Config{Log: config.Log{AccessLogSampling: true}}, sdktrace.NeverSample(), "/ok", "/fail")
+
+	if !strings.HasPrefix(lines[0], "GET /ok 200 ") || strings.Contains(lines[0], "logger-test") {
+		t.Errorf("未采样请求应输出精简日志，got %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "GET /fail HTTP/1.1 500") || !strings.Contains(lines[1], "logger-test") {
+		t.Errorf("未采样的 5xx 请求应输出完整日志，got %q", lines[1])
+	}
+}
+
+func TestAccessLogSamplingKeepsSampledRequests(t *testing.T) {
+	sampled := accessLogLines(t, &config.Config{Log: config.Log{AccessLogSampling: true}}, sdktrace.AlwaysSample(), "/ok")
+	if !strings.Contains(sampled[0], "GET /ok HTTP/1.1 200") || !strings.Contains(sampled[0], "logger-test") {
+		t.Errorf("采样请求应输出完整日志，got %q", sampled[0])
+	}
+
+	disabled := accessLogLines(t, &config.Config{}, sdktrace.NeverSample(), "/ok")
+	if !strings.Contains(disabled[0], "GET /ok HTTP/1.1 200") {
+		t.Errorf("未开启 accessLogSampling 时应始终输出完整日志，got %q", disabled[0])
+	}
+}
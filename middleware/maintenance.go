@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"gin-project/config"
+	"gin-project/controller"
+	"gin-project/pkg"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultMaintenanceRetryAfter 维护模式下 Retry-After 响应头的默认值
+const DefaultMaintenanceRetryAfter = 30 * time.Second
+
+// MaintenanceMode 维护模式中间件
+// 维护模式开启时拒绝写请求（POST/PUT/PATCH/DELETE），返回 503 和 Retry-After 响应头，读请求不受影响；
+// 部署期间用于保持读服务可用，健康检查路由不注册该中间件
+func MaintenanceMode() gin.HandlerFunc {
+	retryAfter := strconv.Itoa(int(maintenanceRetryAfter().Seconds()))
+
+	return func(c *gin.Context) {
+		if !pkg.InMaintenance() || !isMutatingMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+
+		c.Header("Retry-After", retryAfter)
+		baseCtrl := &controller.BaseController{}
+		baseCtrl.Error(c, http.StatusServiceUnavailable, "系统维护中，暂不支持写操作，请 "+retryAfter+" 秒后重试")
+		c.Abort()
+	}
+}
+
+// isMutatingMethod 是否为写操作方法
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// maintenanceRetryAfter 获取维护模式建议的重试间隔（app.maintenance.retryAfter，未配置时使用默认值）
+func maintenanceRetryAfter() time.Duration {
+	if config.Cfg != nil && config.Cfg.App.Maintenance.RetryAfter > 0 {
+		return config.Cfg.App.Maintenance.RetryAfter
+	}
+	return DefaultMaintenanceRetryAfter
+}
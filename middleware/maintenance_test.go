@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gin-project/config"
+	"gin-project/pkg"
+
+	"github.com/gin-gonic/gin"
+)
+
+// useMaintenance 切换维护模式，测试结束时关闭
+func useMaintenance(t *testing.T, enabled bool) {
+	t.Helper()
+	pkg.SetMaintenance(enabled)
+	t.Cleanup(func() { pkg.SetMaintenance(false) })
+}
+
+func maintenanceEngine() *gin.Engine {
+	r := gin.New()
+	r.Use(MaintenanceMode())
+	r.Any("/api/user", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func TestMaintenanceModeRejectsWrites(t *testing.T) {
+	useConfig(t, &config.Config{App: config.App{Maintenance: config.Maintenance{RetryAfter: 2 * time.Minute}}})
+	useMaintenance(t, true)
+	r := maintenanceEngine()
+
+	for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		w := serve(r, httptest.NewRequest(method, "/api/user", nil))
+		if w.Code != http.StatusServiceUnavailable {
+			t.Errorf("%s status = %d, want 503", method, w.Code)
+		}
+		if got := w.Header().Get("Retry-After"); got != "120" {
+			t.Errorf("%s Retry-After = %q, want 120", method, got)
+		}
+	}
+	if w := serve(r, httptest.NewRequest(http.MethodGet, "/api/user", nil)); w.Code != http.StatusOK {
+		t.Errorf("维护模式下读请求 status = %d, want 200", w.Code)
+	}
+}
+
+func TestMaintenanceModeOffAllowsWrites(t *testing.T) {
+	useConfig(t, &config.Config{})
+	useMaintenance(t, false)
+	r := maintenanceEngine()
+
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodDelete} {
+		w := serve(r, httptest.NewRequest(method, "/api/user", nil))
+		if w.Code != http.StatusOK || w.Header().Get("Retry-After") != "" {
+			t.Errorf("%s status = %d, Retry-After = %q", method, w.Code, w.Header().Get("Retry-After"))
+		}
+	}
+
+	// 运行时开启后立即生效（中间件不缓存开关状态）
+	pkg.SetMaintenance(true)
+	w := serve(r, httptest.NewRequest(http.MethodPost, "/api/user", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("开启后 status = %d, want 503", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "30" {
+		t.Errorf("默认 Retry-After = %q, want 30", got)
+	}
+}
@@ -1,18 +1,74 @@
 package middleware
 
 import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"strings"
+
+	"gin-project/config"
 	"gin-project/controller"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// DefaultRecoveryMessage 未配置 app.recoveryMessage 时 panic 响应的消息
+const DefaultRecoveryMessage = "服务器内部错误，请联系技术支持并提供参考编号: {trace_id}"
+
+// TraceIDPlaceholder panic 响应消息中替换为 trace_id 的占位符
+const TraceIDPlaceholder = "{trace_id}"
+
+// PanicDetail panic 详情（仅在 debug 模式下返回）
+type PanicDetail struct {
+	Panic string `json:"panic"` // panic 值
+	Stack string `json:"stack"` // 调用栈
+}
+
 // RecoveryMiddleware 恢复中间件
-// 捕获 panic 并返回统一错误响应，确保服务不会因为 panic 而崩溃
+// 捕获 panic 并返回统一错误响应（HTTP 500），确保服务不会因为 panic 而崩溃；
+// 响应消息按 app.recoveryMessage 生成，{trace_id} 替换为追踪ID（未启用追踪时为请求ID）作为排查问题的参考编号，
+// 仅在 debug 模式下在 data 中返回 panic 值和调用栈
 func RecoveryMiddleware() gin.HandlerFunc {
+	message := recoveryMessage()
+	exposeDetail := config.Cfg != nil && config.Cfg.App.Mode == "debug"
+
 	return gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
-		// 使用 BaseController 返回统一错误格式（包含 trace_id）
+		msg := strings.ReplaceAll(message, TraceIDPlaceholder, supportReference(c))
+
+		var detail interface{}
+		if exposeDetail {
+			detail = PanicDetail{
+				Panic: fmt.Sprint(recovered),
+				Stack: string(debug.Stack()),
+			}
+		}
+
+		// 使用 BaseController 返回统一错误格式（包含 trace_id，HTTP 状态码为 500）
 		baseCtrl := &controller.BaseController{}
-		baseCtrl.Error(c, 500, "服务器内部错误")
+		baseCtrl.ErrorWithData(c, http.StatusInternalServerError, msg, detail)
 		c.Abort()
 	})
 }
+
+// supportReference 排查问题的参考编号：优先使用 trace_id，未启用追踪时使用请求ID
+func supportReference(c *gin.Context) string {
+	if spanCtx := trace.SpanFromContext(c.Request.Context()).SpanContext(); spanCtx.IsValid() {
+		return spanCtx.TraceID().String()
+	}
+	if requestID := c.GetHeader(RequestIDHeader); requestID != "" {
+		return requestID
+	}
+	if requestID := c.Writer.Header().Get(RequestIDHeader); requestID != "" {
+		return requestID
+	}
+	return "-"
+}
+
+// recoveryMessage 获取 panic 响应消息模板（app.recoveryMessage，未配置时使用默认值）
+func recoveryMessage() string {
+	if config.Cfg != nil && config.Cfg.App.RecoveryMessage != "" {
+		return config.Cfg.App.RecoveryMessage
+	}
+	return DefaultRecoveryMessage
+}
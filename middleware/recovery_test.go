@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gin-project/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// panicResponse panic 响应体
+type panicResponse struct {
+	Code    int          `json:"code"`
+	Message string       `json:"message"`
+	Data    *PanicDetail `json:"data"`
+	TraceID string       `json:"trace_id"`
+}
+
+// servePanic 在指定配置下触发一次 panic 并解析响应
+func servePanic(t *testing.T, cfg *config.Config) panicResponse {
+	t.Helper()
+	useConfig(t, cfg)
+	recordSpans(t)
+	// 丢弃 gin 输出的 panic 调用栈日志（恢复中间件创建时读取 DefaultErrorWriter）
+	previous := gin.DefaultErrorWriter
+	gin.DefaultErrorWriter = io.Discard
+	t.Cleanup(func() { gin.DefaultErrorWriter = previous })
+
+	r := gin.New()
+	r.Use(RecoveryMiddleware(), TracingMiddleware())
+	r.GET("/panic", func(*gin.Context) { panic("boom") })
+
+	w := serve(r, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", w.Code)
+	}
+	var resp panicResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v, body = %s", err, w.Body.String())
+	}
+	if resp.TraceID == "" {
+		t.Fatal("panic 响应应始终包含 trace_id")
+	}
+	if !strings.Contains(resp.Message, resp.TraceID) {
+		t.Errorf("message = %q, 应包含参考编号 %s", resp.Message, resp.TraceID)
+	}
+	return resp
+}
+
+func TestRecoveryBodyDiffersBetweenDebugAndRelease(t *testing.T) {
+	debugResp := servePanic(t, &config.Config{App: config.App{Mode: "debug"}})
+	if debugResp.Data == nil || debugResp.Data.Panic != "boom" || debugResp.Data.Stack == "" {
+		t.Errorf("debug 模式应返回 panic 值和调用栈，got %+v", debugResp.Data)
+	}
+
+	releaseResp := servePanic(t, &config.Config{App: config.App{Mode: "release"}})
+	if releaseResp.Data != nil {
+		t.Errorf("release 模式不应暴露 panic 详情，got %+v", releaseResp.Data)
+	}
+	if releaseResp.Message != strings.ReplaceAll(DefaultRecoveryMessage, TraceIDPlaceholder, releaseResp.TraceID) {
+		t.Errorf("release message = %q, want 默认消息", releaseResp.Message)
+	}
+}
+
+func TestRecoveryUsesConfiguredMessage(t *testing.T) {
+	resp := servePanic(t, &config.Config{App: config.App{RecoveryMessage: "出错了，参考编号 " + TraceIDPlaceholder}})
+	if resp.Message != "出错了，参考编号 "+resp.TraceID {
+		t.Errorf("message = %q, want 配置的消息（占位符替换为 trace_id）", resp.Message)
+	}
+}
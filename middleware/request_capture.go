@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"gin-project/pkg"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DefaultCaptureMaxBodyBytes 失败请求记录中请求体的默认最大字节数
+const DefaultCaptureMaxBodyBytes = 4 * 1024
+
+// CaptureFailedRequests 失败请求记录中间件（仅在调试模式下注册）
+// 响应状态码 >= 400 时将请求方法、路径、请求体（截断、脱敏）、状态码和 trace_id 记录到 recorder，
+// 通过 GET /debug/requests 查看；请求体通过 TeeReader 在处理器读取时复制，最多保留 maxBodyBytes 字节。
+// 在恢复中间件之后注册：后续处理器 panic 时按 500 记录后继续向外抛出，由恢复中间件返回统一错误响应
+func CaptureFailedRequests(recorder *pkg.RequestRecorder, maxBodyBytes int) gin.HandlerFunc {
+	if maxBodyBytes <= 0 {
+		maxBodyBytes = DefaultCaptureMaxBodyBytes
+	}
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		body := &cappedBuffer{limit: maxBodyBytes}
+		if c.Request.Body != nil {
+			c.Request.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.TeeReader(c.Request.Body, body), c.Request.Body}
+		}
+
+		record := func(status int) {
+			var traceID string
+			if spanCtx := trace.SpanFromContext(c.Request.Context()).SpanContext(); spanCtx.IsValid() {
+				traceID = spanCtx.TraceID().String()
+			}
+			recorder.Record(pkg.FailedRequest{
+				Time:          start,
+				Method:        c.Request.Method,
+				Path:          c.Request.URL.Path,
+				Query:         pkg.RedactQuery(c.Request.URL.RawQuery),
+				ContentType:   c.ContentType(),
+				Body:          pkg.RedactBody(body.data),
+				BodyTruncated: body.truncated,
+				Status:        status,
+				Latency:       time.Since(start).String(),
+				TraceID:       traceID,
+			})
+		}
+
+		// 后续处理器 panic 时按 500 记录，再交给外层的恢复中间件处理
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				record(http.StatusInternalServerError)
+				panic(recovered)
+			}
+		}()
+
+		c.Next()
+
+		if status := c.Writer.Status(); status >= 400 {
+			record(status)
+		}
+	}
+}
+
+// cappedBuffer 最多保留 limit 字节的写缓冲区，超出部分丢弃（写入始终成功，不影响 TeeReader 读取）
+type cappedBuffer struct {
+	data      []byte
+	limit     int
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.limit - len(b.data); remaining > 0 {
+		if len(p) > remaining {
+			b.data = append(b.data, p[:remaining]...)
+			b.truncated = true
+		} else {
+			b.data = append(b.data, p...)
+		}
+	} else if len(p) > 0 {
+		b.truncated = true
+	}
+	return len(p), nil
+}
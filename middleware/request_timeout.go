@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"gin-project/config"
+	"gin-project/pkg"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestTimeoutHeader 上游调用方传入的请求耗时预算请求头（如 "3s"）
+const RequestTimeoutHeader = "X-Request-Timeout"
+
+// DefaultMaxRequestTimeout 未配置 app.maxRequestTimeout 时的请求超时上限
+const DefaultMaxRequestTimeout = 30 * time.Second
+
+// RequestTimeoutMiddleware 请求超时预算中间件
+// 解析 X-Request-Timeout 请求头并设置为请求 context 的截止时间（不超过 app.maxRequestTimeout），
+// 下游 DB/Redis/HTTP 调用通过 context 继承该截止时间；请求头缺失或格式非法时不做处理
+func RequestTimeoutMiddleware() gin.HandlerFunc {
+	maxTimeout := maxRequestTimeout()
+
+	return func(c *gin.Context) {
+		value := c.GetHeader(RequestTimeoutHeader)
+		if value == "" {
+			c.Next()
+			return
+		}
+
+		timeout, ok := parseRequestTimeout(value)
+		if !ok {
+			pkg.LoggerFromContext(c.Request.Context()).Debug("忽略非法的请求超时请求头",
+				"header", RequestTimeoutHeader, "value", value)
+			c.Next()
+			return
+		}
+		if timeout > maxTimeout {
+			timeout = maxTimeout
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// grpcTimeoutUnits grpc-timeout 的单位
+var grpcTimeoutUnits = map[byte]time.Duration{
+	'H': time.Hour,
+	'M': time.Minute,
+	'S': time.Second,
+	'm': time.Millisecond,
+	'u': time.Microsecond,
+	'n': time.Nanosecond,
+}
+
+// parseRequestTimeout 解析请求超时预算
+// 支持 gRPC grpc-timeout 格式（如 "3S"、"500m"），以及 Go 时长格式（如 "3s"、"500ms"）；
+// 先按 grpc-timeout 解析（最多 8 位数字 + 单个单位字符），因此 "500m" 表示 500 毫秒而不是 Go 的 500 分钟
+func parseRequestTimeout(value string) (time.Duration, bool) {
+	if d, ok := parseGRPCTimeout(value); ok {
+		return d, d > 0
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return d, d > 0
+	}
+	return 0, false
+}
+
+// parseGRPCTimeout 解析 grpc-timeout 格式：1-8 位十进制数字 + 单位（H/M/S/m/u/n）
+func parseGRPCTimeout(value string) (time.Duration, bool) {
+	if len(value) < 2 || len(value) > 9 {
+		return 0, false
+	}
+	unit, ok := grpcTimeoutUnits[value[len(value)-1]]
+	if !ok {
+		return 0, false
+	}
+	digits := value[:len(value)-1]
+	for i := 0; i < len(digits); i++ {
+		if digits[i] < '0' || digits[i] > '9' {
+			return 0, false
+		}
+	}
+	n, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(n) * unit, true
+}
+
+// maxRequestTimeout 获取请求超时上限（app.maxRequestTimeout），未配置时使用默认值
+func maxRequestTimeout() time.Duration {
+	if config.Cfg != nil && config.Cfg.App.MaxRequestTimeout > 0 {
+		return config.Cfg.App.MaxRequestTimeout
+	}
+	return DefaultMaxRequestTimeout
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gin-project/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestParseRequestTimeout(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"3s", 3 * time.Second, true},
+		{"250ms", 250 * time.Millisecond, true},
+		{"3S", 3 * time.Second, true},
+		{"500m", 500 * time.Millisecond, true},
+		{"2M", 2 * time.Minute, true},
+		{"100u", 100 * time.Microsecond, true},
+		{"1h30m", 90 * time.Minute, true},
+		{"", 0, false},
+		{"abc", 0, false},
+		{"-1s", 0, false},
+		{"0s", 0, false},
+		{"0S", 0, false},
+		{"123456789S", 0, false},
+		{"5x", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseRequestTimeout(tt.value)
+		if ok != tt.wantOK || (ok && got != tt.want) {
+			t.Errorf("parseRequestTimeout(%q) = (%v, %v), want (%v, %v)", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestRequestTimeoutMiddleware(t *testing.T) {
+	useConfig(t, &config.Config{App: config.App{MaxRequestTimeout: 5 * time.Second}})
+
+	tests := []struct {
+		name      string
+		header    string
+		wantLimit time.Duration // 0 表示不应设置截止时间
+	}{
+		{"合法", "2s", 2 * time.Second},
+		{"超过上限", "60s", 5 * time.Second},
+		{"缺失", "", 0},
+		{"格式非法", "soon", 0},
+	}
+	for _, tt := range tests {
+		var remaining time.Duration
+		var hasDeadline bool
+		r := gin.New()
+		r.Use(RequestTimeoutMiddleware())
+		r.GET("/", func(c *gin.Context) {
+			var deadline time.Time
+			deadline, hasDeadline = c.Request.Context().Deadline()
+			remaining = time.Until(deadline)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tt.header != "" {
+			req.Header.Set(RequestTimeoutHeader, tt.header)
+		}
+		serve(r, req)
+
+		if tt.wantLimit == 0 {
+			if hasDeadline {
+				t.Errorf("%s: 不应设置截止时间", tt.name)
+			}
+			continue
+		}
+		if !hasDeadline || remaining > tt.wantLimit || remaining < tt.wantLimit-time.Second {
+			t.Errorf("%s: 剩余时间 = %v, want ≈ %v", tt.name, remaining, tt.wantLimit)
+		}
+	}
+}
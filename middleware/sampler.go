@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"context"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ForceTraceHeader 强制采样请求头，值为 1 时无论采样率如何都采样该请求（需开启 tracing.allowForceTrace）
+const ForceTraceHeader = "X-Force-Trace"
+
+// forceSampleKey 强制采样标记的 context 键
+type forceSampleKey struct{}
+
+// allowForceTrace 是否接受 X-Force-Trace 请求头（通过 InitTracing 设置）
+var allowForceTrace bool
+
+// withForceSample 标记 context 中即将创建的 span 必须采样
+func withForceSample(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceSampleKey{}, true)
+}
+
+// isForceSampled 判断 context 是否带有强制采样标记
+func isForceSampled(ctx context.Context) bool {
+	forced, _ := ctx.Value(forceSampleKey{}).(bool)
+	return forced
+}
+
+// forceSampler 在基础采样器之上支持强制采样
+// 头部采样在请求开始时决策，无法根据 5xx、慢请求等结果补采，因此只支持通过请求头显式标记；
+// 未标记时交给基础采样器（按比例采样）决策
+type forceSampler struct {
+	base sdktrace.Sampler
+}
+
+// newForceSampler 创建支持强制采样的采样器
+func newForceSampler(base sdktrace.Sampler) sdktrace.Sampler {
+	return forceSampler{base: base}
+}
+
+// ShouldSample 带有强制采样标记时始终采样，否则使用基础采样器
+func (s forceSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	if isForceSampled(p.ParentContext) {
+		return sdktrace.SamplingResult{
+			Decision:   sdktrace.RecordAndSample,
+			Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+		}
+	}
+	return s.base.ShouldSample(p)
+}
+
+// Description 采样器描述
+func (s forceSampler) Description() string {
+	return "ForceSampler{" + s.base.Description() + "}"
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gin-project/pkg"
+
+	"github.com/gin-gonic/gin"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	sdktracetest "go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSampledSpans 使用 sampler 替换 HTTP 请求追踪器，返回只记录被采样 span 的记录器
+func recordSampledSpans(t *testing.T, sampler sdktrace.Sampler, allowForce bool) *sdktracetest.SpanRecorder {
+	t.Helper()
+	recorder := sdktracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sampler),
+		sdktrace.WithSpanProcessor(recorder),
+	)
+
+	previous, previousAllow := tracer, allowForceTrace
+	UseTracerProvider(provider, "sampler-test")
+	allowForceTrace = allowForce
+	t.Cleanup(func() {
+		tracer = previous
+		allowForceTrace = previousAllow
+	})
+	return recorder
+}
+
+// sampledRequests 发送 n 个请求（forced 时携带 X-Force-Trace: 1），返回被采样的 span 数
+func sampledRequests(t *testing.T, recorder *sdktracetest.SpanRecorder, n int, forced bool) int {
+	t.Helper()
+	recorder.Reset()
+	r := gin.New()
+	r.Use(TracingMiddleware())
+	r.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for i := 0; i < n; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		if forced {
+			req.Header.Set(ForceTraceHeader, "1")
+		}
+		serve(r, req)
+	}
+	sampled := 0
+	for _, span := range recorder.Ended() {
+		if span.SpanContext().IsSampled() {
+			sampled++
+		}
+	}
+	return sampled
+}
+
+func TestForceTraceHeaderBypassesRatio(t *testing.T) {
+	recorder := recordSampledSpans(t, newForceSampler(pkg.NewRatioSampler(0)), true)
+
+	if got := sampledRequests(t, recorder, 10, true); got != 10 {
+		t.Errorf("强制采样请求被采样 %d/10", got)
+	}
+	if got := sampledRequests(t, recorder, 10, false); got != 0 {
+		t.Errorf("采样率为 0 时普通请求被采样 %d/10", got)
+	}
+}
+
+func TestForceTraceHeaderIgnoredWhenDisabled(t *testing.T) {
+	recorder := recordSampledSpans(t, newForceSampler(pkg.NewRatioSampler(0)), false)
+
+	if got := sampledRequests(t, recorder, 10, true); got != 0 {
+		t.Errorf("未开启 allowForceTrace 时请求头不应生效，被采样 %d/10", got)
+	}
+}
+
+func TestForceSamplerRatioPath(t *testing.T) {
+	ratio := pkg.NewRatioSampler(1)
+	recorder := recordSampledSpans(t, newForceSampler(ratio), true)
+
+	if got := sampledRequests(t, recorder, 10, false); got != 10 {
+		t.Errorf("采样率为 1 时被采样 %d/10", got)
+	}
+
+	// 按比例采样：采样率 0.5 时大致一半请求被采样
+	ratio.SetRate(0.5)
+	if got := sampledRequests(t, recorder, 400, false); got < 120 || got > 280 {
+		t.Errorf("采样率 0.5 时被采样 %d/400", got)
+	}
+}
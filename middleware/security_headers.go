@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"gin-project/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SecurityHeaders 安全响应头中间件（面向浏览器的部署使用，app.securityHeaders.enabled 开启时注册）
+// 按 app.securityHeaders 设置 X-Content-Type-Options、X-Frame-Options、Referrer-Policy、
+// Content-Security-Policy 和 Strict-Transport-Security，配置为空的响应头不设置；
+// Strict-Transport-Security 仅在 HTTPS 请求（应用直接终止 TLS，或代理传入 X-Forwarded-Proto: https）上设置
+func SecurityHeaders() gin.HandlerFunc {
+	var cfg config.SecurityHeaders
+	if config.Cfg != nil {
+		cfg = config.Cfg.App.SecurityHeaders
+	}
+
+	headers := make(map[string]string)
+	for name, value := range map[string]string{
+		"X-Content-Type-Options":  cfg.ContentTypeOptions,
+		"X-Frame-Options":         cfg.FrameOptions,
+		"Referrer-Policy":         cfg.ReferrerPolicy,
+		"Content-Security-Policy": cfg.ContentSecurityPolicy,
+	} {
+		if value != "" {
+			headers[name] = value
+		}
+	}
+
+	return func(c *gin.Context) {
+		h := c.Writer.Header()
+		for name, value := range headers {
+			h.Set(name, value)
+		}
+		if cfg.StrictTransportSecurity != "" && isHTTPS(c) {
+			h.Set("Strict-Transport-Security", cfg.StrictTransportSecurity)
+		}
+		c.Next()
+	}
+}
+
+// isHTTPS 请求是否通过 HTTPS 到达（应用直接终止 TLS，或前置代理终止 TLS 后转发）
+func isHTTPS(c *gin.Context) bool {
+	return c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gin-project/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// securityHeadersEngine 注册安全响应头中间件的测试引擎
+func securityHeadersEngine(t *testing.T, cfg config.SecurityHeaders) *gin.Engine {
+	t.Helper()
+	useConfig(t, &config.Config{App: config.App{SecurityHeaders: cfg}})
+	r := gin.New()
+	r.Use(SecurityHeaders())
+	r.GET("/ok", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/fail", func(c *gin.Context) { c.AbortWithStatus(http.StatusBadRequest) })
+	return r
+}
+
+func TestSecurityHeadersPresent(t *testing.T) {
+	r := securityHeadersEngine(t, config.SecurityHeaders{
+		Enabled:                 true,
+		ContentTypeOptions:      "nosniff",
+		FrameOptions:            "DENY",
+		ReferrerPolicy:          "no-referrer",
+		ContentSecurityPolicy:   "default-src 'self'",
+		StrictTransportSecurity: "max-age=31536000",
+	})
+
+	want := map[string]string{
+		"X-Content-Type-Options":  "nosniff",
+		"X-Frame-Options":         "DENY",
+		"Referrer-Policy":         "no-referrer",
+		"Content-Security-Policy": "default-src 'self'",
+	}
+	for _, path := range []string{"/ok", "/fail"} {
+		w := serve(r, httptest.NewRequest(http.MethodGet, path, nil))
+		for name, value := range want {
+			if got := w.Header().Get(name); got != value {
+				t.Errorf("%s %s = %q, want %q", path, name, got, value)
+			}
+		}
+		if got := w.Header().Get("Strict-Transport-Security"); got != "" {
+			t.Errorf("%s 非 HTTPS 请求不应设置 Strict-Transport-Security，got %q", path, got)
+		}
+	}
+}
+
+func TestSecurityHeadersHSTSOnlyOverTLS(t *testing.T) {
+	r := securityHeadersEngine(t, config.SecurityHeaders{Enabled: true, StrictTransportSecurity: "max-age=600"})
+
+	direct := httptest.NewRequest(http.MethodGet, "/ok", nil)
+	direct.TLS = &tls.ConnectionState{}
+	if got := serve(r, direct).Header().Get("Strict-Transport-Security"); got != "max-age=600" {
+		t.Errorf("TLS 请求 Strict-Transport-Security = %q, want max-age=600", got)
+	}
+
+	proxied := httptest.NewRequest(http.MethodGet, "/ok", nil)
+	proxied.Header.Set("X-Forwarded-Proto", "https")
+	if got := serve(r, proxied).Header().Get("Strict-Transport-Security"); got != "max-age=600" {
+		t.Errorf("代理终止 TLS 的请求 Strict-Transport-Security = %q, want max-age=600", got)
+	}
+}
+
+func TestSecurityHeadersSkipsEmptyValues(t *testing.T) {
+	r := securityHeadersEngine(t, config.SecurityHeaders{Enabled: true, ContentTypeOptions: "nosniff"})
+
+	w := serve(r, httptest.NewRequest(http.MethodGet, "/ok", nil))
+	if got := w.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+		t.Errorf("X-Content-Type-Options = %q, want nosniff", got)
+	}
+	for _, name := range []string{"X-Frame-Options", "Referrer-Policy", "Content-Security-Policy"} {
+		if _, ok := w.Header()[name]; ok {
+			t.Errorf("未配置的 %s 不应设置", name)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+
+	"gin-project/controller"
+	"gin-project/pkg"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TenantHeader 租户ID请求头
+const TenantHeader = "X-Tenant-ID"
+
+// TenantBaggageKey 租户ID在 OTel baggage 和 span 属性中的键
+const TenantBaggageKey = "tenant.id"
+
+// maxTenantIDLength 租户ID最大长度
+const maxTenantIDLength = 64
+
+// TenantMiddleware 租户中间件（多租户部署时注册在 /api 路由组上，健康检查路由不注册）
+// 要求请求携带 X-Tenant-ID（仅允许字母、数字、- 和 _），缺失或格式非法时返回 400；
+// 租户ID存入 context（pkg.TenantFromContext）和 OTel baggage（随下游调用传播），
+// 并记录到当前 span 属性和请求级日志
+func TenantMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID := c.GetHeader(TenantHeader)
+		if !validTenantID(tenantID) {
+			baseCtrl := &controller.BaseController{}
+			baseCtrl.Error(c, http.StatusBadRequest, "缺少或非法的租户请求头 "+TenantHeader)
+			c.Abort()
+			return
+		}
+
+		ctx := pkg.WithTenant(c.Request.Context(), tenantID)
+		if member, err := baggage.NewMemberRaw(TenantBaggageKey, tenantID); err == nil {
+			if bag, err := baggage.FromContext(ctx).SetMember(member); err == nil {
+				ctx = baggage.ContextWithBaggage(ctx, bag)
+			}
+		}
+		trace.SpanFromContext(ctx).SetAttributes(attribute.String(TenantBaggageKey, tenantID))
+		ctx = pkg.WithLogger(ctx, pkg.LoggerFromContext(ctx).With("tenant_id", tenantID))
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// validTenantID 校验租户ID：非空、不超过 64 个字符，仅包含字母、数字、- 和 _
+func validTenantID(tenantID string) bool {
+	if tenantID == "" || len(tenantID) > maxTenantIDLength {
+		return false
+	}
+	for _, r := range tenantID {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gin-project/pkg"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+func TestTenantMiddlewareRejectsMissingHeader(t *testing.T) {
+	r := gin.New()
+	r.Use(TenantMiddleware())
+	called := false
+	r.GET("/api/user", func(c *gin.Context) { called = true })
+
+	for _, tenant := range []string{"", "acme corp", "a/b", strings.Repeat("a", maxTenantIDLength+1)} {
+		req := httptest.NewRequest(http.MethodGet, "/api/user", nil)
+		if tenant != "" {
+			req.Header.Set(TenantHeader, tenant)
+		}
+		if w := serve(r, req); w.Code != http.StatusBadRequest {
+			t.Errorf("租户 %q status = %d, want 400", tenant, w.Code)
+		}
+	}
+	if called {
+		t.Error("租户校验失败时不应执行处理函数")
+	}
+}
+
+func TestTenantMiddlewarePropagatesTenant(t *testing.T) {
+	recorder := recordSpans(t)
+	r := gin.New()
+	r.Use(TracingMiddleware())
+	api := r.Group("/api", TenantMiddleware())
+	var tenant, baggageTenant string
+	api.GET("/user", func(c *gin.Context) {
+		tenant, _ = pkg.TenantFromContext(c.Request.Context())
+		baggageTenant = baggage.FromContext(c.Request.Context()).Member(TenantBaggageKey).Value()
+		c.Status(http.StatusOK)
+	})
+	// 健康检查路由不要求租户
+	r.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/api/user", nil)
+	req.Header.Set(TenantHeader, "acme_01")
+	if w := serve(r, req); w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	if tenant != "acme_01" || baggageTenant != "acme_01" {
+		t.Errorf("context 租户 = %q, baggage 租户 = %q, want acme_01", tenant, baggageTenant)
+	}
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("span 数 = %d, want 1", len(spans))
+	}
+	if got, _ := spanAttribute(spans[0], TenantBaggageKey); got.AsString() != "acme_01" {
+		t.Errorf("span %s = %q, want acme_01", TenantBaggageKey, got.AsString())
+	}
+
+	if w := serve(r, httptest.NewRequest(http.MethodGet, "/health", nil)); w.Code != http.StatusOK {
+		t.Errorf("健康检查 status = %d, want 200", w.Code)
+	}
+}
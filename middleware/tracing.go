@@ -6,9 +6,13 @@ import (
 	"time"
 
 	"gin-project/config"
+	"gin-project/pkg"
+	"gin-project/pkg/metrics"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
@@ -17,10 +21,21 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
+// RequestIDHeader 请求ID头（用于不支持 W3C Trace Context 的系统之间关联请求）
+const RequestIDHeader = "X-Request-ID"
+
+// span 命名策略
+const (
+	SpanNameRoute       = "route"        // 使用路由模板，如 /api/user/:id（默认）
+	SpanNameMethodRoute = "method_route" // 使用方法+路由模板，如 GET /api/user/:id
+)
+
 var (
 	tracer trace.Tracer
 	// noopTracer 无操作追踪器，用于追踪未启用时
 	noopTracer = otel.Tracer("noop")
+	// spanNameStrategy span 命名策略（通过 InitTracing 设置）
+	spanNameStrategy = SpanNameRoute
 )
 
 // InitTracing 初始化追踪
@@ -32,6 +47,10 @@ func InitTracing(cfg *config.Config) {
 		return
 	}
 
+	if cfg.Tracing.SpanName != "" {
+		spanNameStrategy = cfg.Tracing.SpanName
+	}
+
 	// 设置全局传播器
 	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
 
@@ -41,6 +60,9 @@ func InitTracing(cfg *config.Config) {
 		endpoint = "localhost:4317"
 	}
 
+	// 启动时探测端点连通性，避免端点配置错误时静默丢失链路数据
+	checkTracingEndpoint(endpoint, cfg.Tracing.ConnectTimeout, cfg.Tracing.FailFast)
+
 	// 配置批量导出参数
 	batchSize := cfg.Tracing.BatchSize
 	if batchSize <= 0 {
@@ -85,16 +107,21 @@ func InitTracing(cfg *config.Config) {
 		sampleRate = 1.0 // 最大100%
 	}
 
-	var sampler sdktrace.Sampler
 	if sampleRate >= 1.0 {
 		// 100%采样（开发/测试环境）
-		sampler = sdktrace.AlwaysSample()
 		log.Printf("追踪已启用：100%% 采样率（开发模式）")
 	} else {
 		// 按比例采样（生产环境推荐）
-		sampler = sdktrace.TraceIDRatioBased(sampleRate)
 		log.Printf("追踪已启用：%.1f%% 采样率（生产模式）", sampleRate*100)
 	}
+	// 采样率可通过 POST /debug/tracing/sample-rate 在运行时调整（排查故障时临时提高到 100%）
+	ratioSampler := pkg.NewRatioSampler(sampleRate)
+	pkg.SetTraceSampler(ratioSampler)
+	// 携带 X-Force-Trace: 1 的请求始终采样（便于排查线上问题）
+	allowForceTrace = cfg.Tracing.AllowForceTrace
+	// 严格模式下 pkg.RequireSpan 找不到有效 span 时 panic（CI 中发现未传递 context 的调用）
+	pkg.SetStrictSpans(cfg.Tracing.StrictSpans)
+	sampler := newForceSampler(ratioSampler)
 
 	// 创建跟踪提供者，配置采样率和批量导出
 	// 批量导出配置优化性能：减少网络往返，降低性能开销
@@ -112,6 +139,10 @@ func InitTracing(cfg *config.Config) {
 	// 设置全局跟踪提供者
 	otel.SetTracerProvider(tp)
 
+	// 采集器不可用时限流输出导出错误并计数，持续失败后降级（tracing.degradeAfter）
+	otel.SetErrorHandler(newExportErrorHandler(cfg.Tracing.DegradeAfter))
+	metrics.Register(exportErrors)
+
 	// 创建全局tracer
 	tracer = otel.Tracer(cfg.App.Name)
 
@@ -126,21 +157,30 @@ func InitTracing(cfg *config.Config) {
 	cfg.Tracing.Cleanup = cleanup
 }
 
+// UseTracerProvider 使用指定的 TracerProvider 创建 HTTP 请求追踪器，不连接导出端点
+// 供测试配合内存 span 记录器（pkg/tracetest）验证链路，生产环境通过 InitTracing 初始化
+func UseTracerProvider(tp trace.TracerProvider, name string) {
+	tracer = tp.Tracer(name)
+}
+
 // TracingMiddleware 追踪中间件
 // 自动为所有 HTTP 请求创建追踪 span，提取和传播 TraceID
 func TracingMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// 如果追踪未启用，直接跳过
-		if tracer == noopTracer {
+		// 如果追踪未启用或已因导出持续失败而降级，直接跳过
+		if tracer == noopTracer || tracingDegraded.Load() {
 			c.Next()
 			return
 		}
 
 		// 从请求头中提取追踪上下文（支持 W3C Trace Context 标准）
 		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		if allowForceTrace && c.GetHeader(ForceTraceHeader) == "1" {
+			ctx = withForceSample(ctx)
+		}
 
-		// 开始新的 span（使用路由路径作为操作名）
-		ctx, span := tracer.Start(ctx, c.FullPath(),
+		// 开始新的 span（按命名策略使用路由模板作为操作名）
+		ctx, span := pkg.StartSpan(ctx, tracer, spanName(c),
 			trace.WithSpanKind(trace.SpanKindServer),
 		)
 		defer span.End()
@@ -156,6 +196,14 @@ func TracingMiddleware() gin.HandlerFunc {
 			semconv.NetPeerIPKey.String(c.ClientIP()),
 		)
 
+		// 记录请求ID：优先使用客户端/上游代理传入的 X-Request-ID，没有则自动生成并写回响应头
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" {
+			requestID = uuid.NewString()
+			c.Header(RequestIDHeader, requestID)
+		}
+		span.SetAttributes(attribute.String("request.id", requestID))
+
 		// 处理请求
 		c.Next()
 
@@ -165,3 +213,16 @@ func TracingMiddleware() gin.HandlerFunc {
 		)
 	}
 }
+
+// spanName 根据命名策略生成 span 名称
+// 未匹配路由（404/405）时 FullPath 为空，使用 "HTTP {method}"，避免空名称且不因原始路径导致名称数量膨胀
+func spanName(c *gin.Context) string {
+	route := c.FullPath()
+	if route == "" {
+		return "HTTP " + c.Request.Method
+	}
+	if spanNameStrategy == SpanNameMethodRoute {
+		return c.Request.Method + " " + route
+	}
+	return route
+}
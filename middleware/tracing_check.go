@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"time"
+)
+
+// defaultTracingConnectTimeout 启动时探测追踪端点的默认超时
+const defaultTracingConnectTimeout = 2 * time.Second
+
+// checkTracingEndpoint 启动时探测 OTLP 端点是否可连接（TCP 建连）
+// 不可连接时：failFast 开启则退出进程，否则输出醒目的警告（导出器会在后台持续重试，服务照常运行）
+func checkTracingEndpoint(endpoint string, timeout time.Duration, failFast bool) {
+	if timeout <= 0 {
+		timeout = defaultTracingConnectTimeout
+	}
+
+	err := dialEndpoint(endpoint, timeout)
+	if err == nil {
+		return
+	}
+	if failFast {
+		log.Fatalf("追踪端点 %s 不可连接（tracing.failFast 已开启）: %v", endpoint, err)
+	}
+	log.Printf("==================== 警告 ====================")
+	log.Printf("追踪端点 %s 不可连接: %v", endpoint, err)
+	log.Printf("链路数据将无法导出，请检查 tracing.endpoint；开启 tracing.failFast 可在启动时直接失败")
+	log.Printf("==============================================")
+}
+
+// dialEndpoint 在超时时间内尝试建立 TCP 连接
+func dialEndpoint(endpoint string, timeout time.Duration) error {
+	conn, err := net.DialTimeout("tcp", endpoint, timeout)
+	if err != nil {
+		return fmt.Errorf("连接失败: %w", err)
+	}
+	return conn.Close()
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+// unreachableEndpoint 返回一个已关闭的本地端口（连接会被拒绝）
+func unreachableEndpoint(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+	return addr
+}
+
+func TestCheckTracingEndpointReachable(t *testing.T) {
+	logs := captureStdLog(t)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	checkTracingEndpoint(ln.Addr().String(), time.Second, true)
+	if logs.Len() != 0 {
+		t.Errorf("端点可连接时不应输出日志，got %q", logs.String())
+	}
+}
+
+func TestCheckTracingEndpointWarnsByDefault(t *testing.T) {
+	logs := captureStdLog(t)
+	endpoint := unreachableEndpoint(t)
+
+	checkTracingEndpoint(endpoint, 100*time.Millisecond, false)
+	if !strings.Contains(logs.String(), "追踪端点 "+endpoint+" 不可连接") {
+		t.Errorf("端点不可连接时应输出警告，got %q", logs.String())
+	}
+}
+
+func TestCheckTracingEndpointFailFast(t *testing.T) {
+	// log.Fatalf 会退出进程，在子进程中执行
+	if endpoint := os.Getenv("TRACING_CHECK_ENDPOINT"); endpoint != "" {
+		checkTracingEndpoint(endpoint, 100*time.Millisecond, true)
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestCheckTracingEndpointFailFast$")
+	cmd.Env = append(os.Environ(), "TRACING_CHECK_ENDPOINT="+unreachableEndpoint(t))
+	out, err := cmd.CombinedOutput()
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.Success() {
+		t.Fatalf("failFast 模式下端点不可连接时应退出进程，err = %v, output = %s", err, out)
+	}
+	if !strings.Contains(string(out), "tracing.failFast 已开启") {
+		t.Errorf("退出前应输出原因，output = %s", out)
+	}
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gin-project/pkg/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// exportErrorLogInterval 追踪导出错误日志的最小输出间隔（期间的错误只计数）
+const exportErrorLogInterval = time.Minute
+
+// exportErrors 追踪导出失败次数
+var exportErrors = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: metrics.Namespace,
+	Subsystem: "tracing",
+	Name:      "export_errors_total",
+	Help:      "追踪导出失败次数（含 OpenTelemetry SDK 内部错误）",
+})
+
+// tracingDegraded 追踪导出持续失败后置为 true，TracingMiddleware 不再创建请求 span
+var tracingDegraded atomic.Bool
+
+// exportErrorHandler OpenTelemetry 全局错误处理器
+// 采集器不可用时批量导出器会持续报错，这里按间隔限流输出日志并计数，
+// 持续失败超过 degradeAfter 后降级为不创建请求 span，避免无意义的开销
+type exportErrorHandler struct {
+	interval     time.Duration // 日志输出间隔
+	degradeAfter time.Duration // 持续失败多久后降级，0 表示不降级
+
+	mu           sync.Mutex
+	lastLog      time.Time // 上一次输出日志的时间
+	suppressed   int       // 上一次输出日志后被省略的错误数
+	failingSince time.Time // 本轮持续失败的开始时间
+	lastError    time.Time // 上一次错误的时间
+}
+
+// newExportErrorHandler 创建追踪导出错误处理器
+func newExportErrorHandler(degradeAfter time.Duration) *exportErrorHandler {
+	return &exportErrorHandler{
+		interval:     exportErrorLogInterval,
+		degradeAfter: degradeAfter,
+	}
+}
+
+// Handle 处理 OpenTelemetry SDK 上报的错误（实现 otel.ErrorHandler）
+func (h *exportErrorHandler) Handle(err error) {
+	exportErrors.Inc()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+
+	// 距上一次错误超过日志间隔视为已恢复过，重新计算持续失败时间
+	if h.failingSince.IsZero() || now.Sub(h.lastError) > h.interval {
+		h.failingSince = now
+	}
+	h.lastError = now
+
+	if now.Sub(h.lastLog) >= h.interval {
+		if h.suppressed > 0 {
+			log.Printf("追踪导出失败: %v（期间另有 %d 条错误已省略）", err, h.suppressed)
+		} else {
+			log.Printf("追踪导出失败: %v", err)
+		}
+		h.lastLog = now
+		h.suppressed = 0
+	} else {
+		h.suppressed++
+	}
+
+	if h.degradeAfter > 0 && now.Sub(h.failingSince) >= h.degradeAfter && !tracingDegraded.Load() {
+		tracingDegraded.Store(true)
+		log.Printf("追踪导出已持续失败 %s，停止创建请求 span（降级为无操作，重启后恢复）", h.degradeAfter)
+	}
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// failingExporter 始终导出失败的 span 导出器（模拟采集器不可用）
+type failingExporter struct{}
+
+func (failingExporter) ExportSpans(context.Context, []sdktrace.ReadOnlySpan) error {
+	return errors.New("collector unavailable")
+}
+
+func (failingExporter) Shutdown(context.Context) error { return nil }
+
+// captureStdLog 将标准库 log 输出重定向到缓冲区，测试结束时恢复
+func captureStdLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous := log.Writer()
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(previous) })
+	return &buf
+}
+
+func TestExportErrorHandlerThrottlesAndDegrades(t *testing.T) {
+	logs := captureStdLog(t)
+	handler := newExportErrorHandler(time.Nanosecond)
+	previous := otel.GetErrorHandler()
+	otel.SetErrorHandler(handler)
+	t.Cleanup(func() {
+		otel.SetErrorHandler(previous)
+		tracingDegraded.Store(false)
+	})
+
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(failingExporter{}))
+	defer provider.Shutdown(context.Background())
+	before := testutil.ToFloat64(exportErrors)
+
+	for i := 0; i < 5; i++ {
+		_, span := provider.Tracer("export-test").Start(context.Background(), "op")
+		span.End()
+	}
+
+	if got := testutil.ToFloat64(exportErrors) - before; got != 5 {
+		t.Errorf("export_errors_total 增加 %v, want 5", got)
+	}
+	if got := strings.Count(logs.String(), "追踪导出失败: "); got != 1 {
+		t.Errorf("间隔内输出 %d 条导出错误日志, want 1: %s", got, logs.String())
+	}
+	if handler.suppressed != 4 {
+		t.Errorf("省略的错误数 = %d, want 4", handler.suppressed)
+	}
+	if !tracingDegraded.Load() {
+		t.Error("持续失败超过 degradeAfter 后应降级")
+	}
+}
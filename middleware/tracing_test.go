@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTracingRecordsProvidedRequestID(t *testing.T) {
+	recorder := recordSpans(t)
+	r := gin.New()
+	r.Use(TracingMiddleware())
+	r.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set(RequestIDHeader, "upstream-123")
+	w := serve(r, req)
+
+	if got := w.Header().Get(RequestIDHeader); got != "" {
+		t.Errorf("已传入请求ID时不应写回响应头，got %q", got)
+	}
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("span 数 = %d, want 1", len(spans))
+	}
+	if id, _ := spanAttribute(spans[0], "request.id"); id.AsString() != "upstream-123" {
+		t.Errorf("request.id = %q, want upstream-123", id.AsString())
+	}
+}
+
+func TestTracingGeneratesRequestID(t *testing.T) {
+	recorder := recordSpans(t)
+	r := gin.New()
+	r.Use(TracingMiddleware())
+	r.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := serve(r, httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+	generated := w.Header().Get(RequestIDHeader)
+	if generated == "" {
+		t.Fatal("未传入请求ID时应生成并写回响应头")
+	}
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("span 数 = %d, want 1", len(spans))
+	}
+	if id, _ := spanAttribute(spans[0], "request.id"); id.AsString() != generated {
+		t.Errorf("request.id = %q, want %q", id.AsString(), generated)
+	}
+}
+
+func TestTracingSpanNames(t *testing.T) {
+	tests := []struct {
+		strategy string
+		target   string
+		want     string
+	}{
+		{SpanNameRoute, "/api/user/1", "/api/user/:id"},
+		{SpanNameMethodRoute, "/api/user/1", "GET /api/user/:id"},
+		{SpanNameRoute, "/unknown", "HTTP GET"},
+		{SpanNameMethodRoute, "/unknown", "HTTP GET"},
+	}
+	for _, tt := range tests {
+		recorder := recordSpans(t)
+		previous := spanNameStrategy
+		spanNameStrategy = tt.strategy
+
+		r := gin.New()
+		r.Use(TracingMiddleware())
+		r.GET("/api/user/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+		serve(r, httptest.NewRequest(http.MethodGet, tt.target, nil))
+		spanNameStrategy = previous
+
+		spans := recorder.Ended()
+		if len(spans) != 1 {
+			t.Fatalf("%s %s: span 数 = %d, want 1", tt.strategy, tt.target, len(spans))
+		}
+		if got := spans[0].Name(); got != tt.want {
+			t.Errorf("%s %s: span 名称 = %q, want %q", tt.strategy, tt.target, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+)
+
+// TrimTrailingSlash 去除请求路径末尾斜杠（根路径除外）的 HTTP 处理器包装
+// 必须在路由匹配之前执行（gin 中间件在路由匹配之后才运行），因此包装整个引擎而不是注册为 gin 中间件；
+// 避免 /api/user/query/ 与 /api/user/query 被视为不同路由，产生 404 或丢失 POST 请求体的重定向
+func TrimTrailingSlash(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path := r.URL.Path; len(path) > 1 && strings.HasSuffix(path, "/") {
+			r.URL.Path = strings.TrimRight(path, "/")
+			if r.URL.Path == "" {
+				r.URL.Path = "/"
+			}
+			if r.URL.RawPath != "" {
+				r.URL.RawPath = strings.TrimRight(r.URL.RawPath, "/")
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
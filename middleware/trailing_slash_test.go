@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTrimTrailingSlash(t *testing.T) {
+	r := gin.New()
+	echo := func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, "%s %s", c.Request.Method, body)
+	}
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete} {
+		r.Handle(method, "/api/user/query", echo)
+	}
+	r.GET("/", func(c *gin.Context) { c.String(http.StatusOK, "root") })
+	handler := TrimTrailingSlash(r)
+
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete} {
+		for _, path := range []string{"/api/user/query", "/api/user/query/", "/api/user/query//"} {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(`{"id":1}`)))
+
+			if w.Code != http.StatusOK {
+				t.Errorf("%s %s: status = %d, want 200", method, path, w.Code)
+				continue
+			}
+			// 不重定向，请求体原样到达处理器
+			if want := method + ` {"id":1}`; w.Body.String() != want {
+				t.Errorf("%s %s: body = %q, want %q", method, path, w.Body.String(), want)
+			}
+		}
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusOK || w.Body.String() != "root" {
+		t.Errorf("根路径 status = %d, body = %q", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"gin-project/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+// URL 长度默认上限（app.server.maxURLBytes / maxQueryBytes 未配置时使用）
+const (
+	DefaultMaxURLBytes   = 8 << 10
+	DefaultMaxQueryBytes = 4 << 10
+)
+
+// URLLengthLimit 请求 URL 长度限制中间件：完整 URL（路径 + 查询参数）或查询参数超过上限时返回 414（统一响应格式）
+// 紧跟恢复中间件注册为全局中间件，未匹配路由（404/405）的请求同样受保护，超长请求不会进入后续中间件和处理器；
+// maxURLBytes、maxQueryBytes 小于 0 时不限制对应长度
+func URLLengthLimit(maxURLBytes, maxQueryBytes int) gin.HandlerFunc {
+	baseCtrl := &controller.BaseController{}
+	return func(c *gin.Context) {
+		if maxURLBytes >= 0 && len(c.Request.RequestURI) > maxURLBytes {
+			baseCtrl.Error(c, http.StatusRequestURITooLong, fmt.Sprintf("请求 URL 过长: 最多 %d 字节", maxURLBytes))
+			c.Abort()
+			return
+		}
+		if maxQueryBytes >= 0 && len(c.Request.URL.RawQuery) > maxQueryBytes {
+			baseCtrl.Error(c, http.StatusRequestURITooLong, fmt.Sprintf("查询参数过长: 最多 %d 字节", maxQueryBytes))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
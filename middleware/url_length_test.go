@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// urlLengthEngine 注册 URL 长度限制中间件的测试引擎
+func urlLengthEngine(maxURLBytes, maxQueryBytes int) *gin.Engine {
+	r := gin.New()
+	r.Use(URLLengthLimit(maxURLBytes, maxQueryBytes))
+	r.GET("/search", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func TestURLLengthLimitRejectsOversizedQuery(t *testing.T) {
+	r := urlLengthEngine(1024, 64)
+
+	w := serve(r, httptest.NewRequest(http.MethodGet, "/search?q="+strings.Repeat("a", 100), nil))
+	if w.Code != http.StatusRequestURITooLong {
+		t.Fatalf("status = %d, want 414", w.Code)
+	}
+	var resp struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("414 应使用统一响应格式: %v, body = %s", err, w.Body.String())
+	}
+	if resp.Code != http.StatusRequestURITooLong || !strings.Contains(resp.Message, "查询参数过长") {
+		t.Errorf("响应 = %+v", resp)
+	}
+
+	if w := serve(r, httptest.NewRequest(http.MethodGet, "/search?q=short", nil)); w.Code != http.StatusOK {
+		t.Errorf("未超过上限: status = %d, want 200", w.Code)
+	}
+}
+
+func TestURLLengthLimitRejectsOversizedURL(t *testing.T) {
+	r := urlLengthEngine(64, -1)
+
+	// 查询参数不限制，但完整 URL 超过上限；未匹配的路由同样受保护
+	for _, target := range []string{"/search?q=" + strings.Repeat("a", 100), "/" + strings.Repeat("x", 100)} {
+		if w := serve(r, httptest.NewRequest(http.MethodGet, target, nil)); w.Code != http.StatusRequestURITooLong {
+			t.Errorf("%.20s...: status = %d, want 414", target, w.Code)
+		}
+	}
+}
+
+func TestURLLengthLimitDisabled(t *testing.T) {
+	r := urlLengthEngine(-1, -1)
+	if w := serve(r, httptest.NewRequest(http.MethodGet, "/search?q="+strings.Repeat("a", 20000), nil)); w.Code != http.StatusOK {
+		t.Errorf("不限制长度时 status = %d, want 200", w.Code)
+	}
+}
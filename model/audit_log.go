@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// AuditLog 审计日志数据模型，记录谁在何时对哪个实体执行了写操作
+type AuditLog struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+	Action    string    `json:"action" gorm:"not null;size:20"`  // 操作类型：create、update
+	Entity    string    `json:"entity" gorm:"not null;size:50"`  // 实体类型，如 user
+	EntityID  uint      `json:"entity_id" gorm:"not null;index"` // 实体ID
+	Actor     string    `json:"actor" gorm:"not null;size:100"`  // 操作人（来自认证信息，未认证时为 anonymous）
+	TraceID   string    `json:"trace_id" gorm:"size:32"`         // 追踪ID，便于关联请求日志
+}
+
+// TableName 指定表名
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
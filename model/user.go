@@ -9,13 +9,17 @@ import (
 // User 用户数据模型
 type User struct {
 	ID        uint           `json:"id" gorm:"primaryKey"`
+	TenantID  string         `json:"tenant_id" gorm:"size:64;index"`      // 租户ID（多租户部署时由 context 中的租户填充，单租户时为空）
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index" swaggertype:"string" format:"date-time"`
 	Name      string         `json:"name" gorm:"not null;size:100"`      // 用户姓名
 	Email     string         `json:"email" gorm:"not null;unique;size:100"` // 用户邮箱
 	Age       int            `json:"age"`                                  // 用户年龄
 	Status    int            `json:"status" gorm:"default:1"`              // 用户状态 1-正常 0-禁用
+	Version   int            `json:"version" gorm:"not null;default:0"`    // 版本号（乐观锁，每次更新递增）
+	CreatedBy string         `json:"created_by" gorm:"size:100"`           // 创建人（由 GORM 回调根据 context 中的操作人填充）
+	UpdatedBy string         `json:"updated_by" gorm:"size:100"`           // 最后修改人（由 GORM 回调根据 context 中的操作人填充）
 }
 
 // TableName 指定表名
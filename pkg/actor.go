@@ -0,0 +1,28 @@
+package pkg
+
+import "context"
+
+// AnonymousActor 未认证请求的操作人
+const AnonymousActor = "anonymous"
+
+// actorKey 操作人在 context 中的键
+type actorKey struct{}
+
+// WithActor 将操作人（如 JWT claims 中的用户标识）存入 context，由认证中间件调用
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext 获取当前请求的操作人，context 中没有时返回 AnonymousActor
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := LookupActor(ctx); ok {
+		return actor
+	}
+	return AnonymousActor
+}
+
+// LookupActor 获取 context 中的操作人，未设置时 ok 为 false
+func LookupActor(ctx context.Context) (actor string, ok bool) {
+	actor, _ = ctx.Value(actorKey{}).(string)
+	return actor, actor != ""
+}
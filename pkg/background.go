@@ -0,0 +1,197 @@
+package pkg
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gin-project/pkg/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// DefaultBackgroundWorkers 后台任务默认并发数
+	DefaultBackgroundWorkers = 4
+	// DefaultBackgroundQueueSize 后台任务默认队列长度
+	DefaultBackgroundQueueSize = 1000
+	// backgroundSpanName 后台任务 span 名称
+	backgroundSpanName = "background.task"
+)
+
+var (
+	// backgroundRunner 全局后台任务执行器
+	backgroundRunner     *BackgroundRunner
+	backgroundRunnerOnce sync.Once
+
+	// backgroundDropped 被丢弃的后台任务数（按丢弃原因）
+	backgroundDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "background",
+		Name:      "tasks_dropped_total",
+		Help:      "被丢弃的后台任务数（queue_full 为队列已满，closed 为执行器已关闭）",
+	}, []string{"reason"})
+)
+
+// 后台任务丢弃原因
+const (
+	dropQueueFull = "queue_full" // 队列已满
+	dropClosed    = "closed"     // 执行器已关闭
+)
+
+// backgroundTask 后台任务
+type backgroundTask struct {
+	ctx context.Context // 提交任务时的上下文（通常为请求上下文），执行时由 Go 脱离其生命周期
+	fn  func(ctx context.Context)
+}
+
+// BackgroundRunner 有界后台任务执行器
+// 固定数量的 worker 消费有界队列，队列满时丢弃任务（记录日志和计数），避免无限制创建 goroutine
+type BackgroundRunner struct {
+	tasks       chan backgroundTask
+	wg          sync.WaitGroup
+	mu          sync.RWMutex
+	closed      bool
+	dropped     atomic.Uint64
+	taskTimeout time.Duration
+
+	// ctx 所有任务上下文的父上下文，Shutdown 超时时取消，通知仍在执行的任务退出
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewBackgroundRunner 创建后台任务执行器并启动 worker
+// taskTimeout 为单个任务的执行超时（任务上下文的截止时间），0 表示不限制
+func NewBackgroundRunner(workers int, queue int, taskTimeout time.Duration) *BackgroundRunner {
+	if workers <= 0 {
+		workers = DefaultBackgroundWorkers
+	}
+	if queue < 0 {
+		queue = 0
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &BackgroundRunner{
+		tasks:       make(chan backgroundTask, queue),
+		taskTimeout: taskTimeout,
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+	for i := 0; i < workers; i++ {
+		r.wg.Add(1)
+		go r.work()
+	}
+	return r
+}
+
+// work 消费队列中的任务，直到队列关闭且排空
+func (r *BackgroundRunner) work() {
+	defer r.wg.Done()
+	for task := range r.tasks {
+		r.run(task)
+	}
+}
+
+// run 通过 Go 执行单个任务（在关联到提交方 span 的新 span 中执行，panic 被恢复，worker 不会退出），
+// 等待任务结束后再处理下一个，保持并发数不超过 worker 数
+func (r *BackgroundRunner) run(task backgroundTask) {
+	<-Go(task.ctx, backgroundSpanName, func(ctx context.Context) {
+		ctx, cancel := r.taskContext(ctx)
+		defer cancel()
+		task.fn(ctx)
+	})
+}
+
+// taskContext 为任务上下文加上执行超时，并在执行器被强制停止（Shutdown 超时）时取消
+func (r *BackgroundRunner) taskContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	var cancel context.CancelFunc
+	if r.taskTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, r.taskTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	stop := context.AfterFunc(r.ctx, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// Submit 提交后台任务，返回任务是否被接受
+// 任务使用脱离请求生命周期的新上下文执行，不会因请求结束而被取消（仅受执行超时和 Shutdown 超时约束）；
+// ctx 中带有 span 时，任务在新的根 span 中执行，并通过 span link 关联到提交时的 span
+// （任务可能在请求结束后才执行，不作为请求 span 的子 span）；队列已满或执行器已关闭时丢弃任务
+func (r *BackgroundRunner) Submit(ctx context.Context, fn func(ctx context.Context)) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.closed {
+		r.drop(dropClosed)
+		return false
+	}
+
+	select {
+	case r.tasks <- backgroundTask{ctx: ctx, fn: fn}:
+		return true
+	default:
+		r.drop(dropQueueFull)
+		return false
+	}
+}
+
+// drop 记录被丢弃的任务（日志、计数和指标）
+func (r *BackgroundRunner) drop(reason string) {
+	dropped := r.dropped.Add(1)
+	backgroundDropped.WithLabelValues(reason).Inc()
+	log.Printf("后台任务被丢弃（%s），累计丢弃: %d", reason, dropped)
+}
+
+// Dropped 累计丢弃的任务数
+func (r *BackgroundRunner) Dropped() uint64 {
+	return r.dropped.Load()
+}
+
+// Shutdown 停止接收新任务，并等待队列中已有任务执行完毕
+// ctx 超时或取消时取消所有任务的上下文（正在执行的任务应响应 ctx.Done() 退出，
+// 队列中尚未执行的任务收到已取消的上下文）并立即返回 ctx.Err()
+func (r *BackgroundRunner) Shutdown(ctx context.Context) error {
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.tasks)
+	}
+	r.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		r.cancel()
+		return nil
+	case <-ctx.Done():
+		r.cancel()
+		return ctx.Err()
+	}
+}
+
+// InitBackgroundRunner 初始化全局后台任务执行器
+// 必须在 metrics.Init 之后调用
+func InitBackgroundRunner(workers int, queue int, taskTimeout time.Duration) {
+	backgroundRunnerOnce.Do(func() {
+		backgroundRunner = NewBackgroundRunner(workers, queue, taskTimeout)
+		metrics.Register(backgroundDropped)
+	})
+}
+
+// Background 获取全局后台任务执行器
+// 如果未初始化，使用默认配置
+func Background() *BackgroundRunner {
+	InitBackgroundRunner(DefaultBackgroundWorkers, DefaultBackgroundQueueSize, 0)
+	return backgroundRunner
+}
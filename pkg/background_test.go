@@ -0,0 +1,134 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestBackgroundRunnerDropsWhenQueueFull(t *testing.T) {
+	runner := NewBackgroundRunner(1, 1, 0)
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	// 第一个任务占用唯一的 worker，第二个任务填满队列
+	runner.Submit(context.Background(), func(context.Context) {
+		close(started)
+		<-release
+	})
+	<-started
+	if !runner.Submit(context.Background(), func(context.Context) {}) {
+		t.Fatal("队列未满时任务应被接受")
+	}
+	if runner.Submit(context.Background(), func(context.Context) {}) {
+		t.Error("队列已满时任务应被丢弃")
+	}
+	if got := runner.Dropped(); got != 1 {
+		t.Errorf("Dropped = %d, want 1", got)
+	}
+
+	close(release)
+	shutdown(t, runner)
+}
+
+func TestBackgroundRunnerShutdownDrainsQueue(t *testing.T) {
+	runner := NewBackgroundRunner(2, 10, 0)
+	var done atomic.Int32
+	for i := 0; i < 10; i++ {
+		runner.Submit(context.Background(), func(context.Context) {
+			time.Sleep(time.Millisecond)
+			done.Add(1)
+		})
+	}
+
+	shutdown(t, runner)
+	if got := done.Load(); got != 10 {
+		t.Errorf("Shutdown 返回时已执行 %d 个任务, want 10", got)
+	}
+	if runner.Submit(context.Background(), func(context.Context) {}) {
+		t.Error("关闭后提交的任务应被丢弃")
+	}
+}
+
+func TestBackgroundRunnerShutdownTimeout(t *testing.T) {
+	runner := NewBackgroundRunner(1, 1, 0)
+	release := make(chan struct{})
+	defer close(release)
+	runner.Submit(context.Background(), func(context.Context) { <-release })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := runner.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("任务未执行完时 Shutdown 应返回超时错误，got %v", err)
+	}
+}
+
+func TestBackgroundRunnerShutdownTimeoutCancelsTasks(t *testing.T) {
+	runner := NewBackgroundRunner(1, 1, 0)
+	started := make(chan struct{})
+	stopped := make(chan error, 1)
+	runner.Submit(context.Background(), func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+		stopped <- ctx.Err()
+	})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := runner.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown = %v, want DeadlineExceeded", err)
+	}
+	select {
+	case err := <-stopped:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("任务上下文 Err = %v, want Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Shutdown 超时后正在执行的任务应收到 ctx.Done()")
+	}
+}
+
+func TestBackgroundRunnerTaskTimeout(t *testing.T) {
+	runner := NewBackgroundRunner(1, 1, 10*time.Millisecond)
+	var taskErr error
+	runner.Submit(context.Background(), func(ctx context.Context) {
+		<-ctx.Done()
+		taskErr = ctx.Err()
+	})
+
+	shutdown(t, runner)
+	if !errors.Is(taskErr, context.DeadlineExceeded) {
+		t.Errorf("任务上下文 Err = %v, want DeadlineExceeded", taskErr)
+	}
+}
+
+func TestBackgroundRunnerDropMetric(t *testing.T) {
+	queueFull := testutil.ToFloat64(backgroundDropped.WithLabelValues(dropQueueFull))
+	closed := testutil.ToFloat64(backgroundDropped.WithLabelValues(dropClosed))
+
+	runner := NewBackgroundRunner(1, 1, 0)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	runner.Submit(context.Background(), func(context.Context) {
+		close(started)
+		<-release
+	})
+	<-started
+	runner.Submit(context.Background(), func(context.Context) {})
+	runner.Submit(context.Background(), func(context.Context) {})
+	close(release)
+	shutdown(t, runner)
+	runner.Submit(context.Background(), func(context.Context) {})
+
+	if got := testutil.ToFloat64(backgroundDropped.WithLabelValues(dropQueueFull)) - queueFull; got != 1 {
+		t.Errorf("queue_full 丢弃计数增加 %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(backgroundDropped.WithLabelValues(dropClosed)) - closed; got != 1 {
+		t.Errorf("closed 丢弃计数增加 %v, want 1", got)
+	}
+}
@@ -0,0 +1,181 @@
+// Package breaker 熔断器及其注册表
+// 下游连续失败达到阈值后熔断（open），在冷却时间内直接拒绝调用；冷却结束后放行一次试探调用（half-open），
+// 成功则恢复（closed），失败则重新熔断。熔断器按名称注册，健康检查通过 States 汇总各下游状态
+package breaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrOpen 熔断器已打开，调用被拒绝
+var ErrOpen = errors.New("熔断器已打开，暂停调用下游")
+
+const (
+	DefaultFailureThreshold = 5                // 默认连续失败阈值
+	DefaultOpenTimeout      = 30 * time.Second // 默认熔断冷却时间
+)
+
+// State 熔断器状态
+type State int
+
+const (
+	StateClosed   State = iota // 关闭：正常调用
+	StateOpen                  // 打开：拒绝调用
+	StateHalfOpen              // 半开：放行一次试探调用
+)
+
+// String 状态名称
+func (s State) String() string {
+	switch s {
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half_open"
+	default:
+		return "closed"
+	}
+}
+
+// Breaker 熔断器（并发安全）
+type Breaker struct {
+	name             string
+	failureThreshold int
+	openTimeout      time.Duration
+
+	mu       sync.Mutex
+	state    State
+	failures int       // 连续失败次数
+	openedAt time.Time // 最近一次熔断的时间
+	probing  bool      // 半开状态下是否已有试探调用在进行
+}
+
+// New 创建熔断器，参数非法时使用默认值
+func New(name string, failureThreshold int, openTimeout time.Duration) *Breaker {
+	if failureThreshold <= 0 {
+		failureThreshold = DefaultFailureThreshold
+	}
+	if openTimeout <= 0 {
+		openTimeout = DefaultOpenTimeout
+	}
+	return &Breaker{
+		name:             name,
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+	}
+}
+
+// Name 熔断器名称
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// Allow 判断是否允许调用，熔断期间返回 ErrOpen
+// 允许调用后必须调用 Record 上报结果（或调用 Release 放弃上报）
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if time.Since(b.openedAt) < b.openTimeout {
+			return ErrOpen
+		}
+		// 冷却结束，进入半开状态放行一次试探调用
+		b.state = StateHalfOpen
+		b.probing = true
+		return nil
+	case StateHalfOpen:
+		if b.probing {
+			return ErrOpen
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Record 上报调用结果，err 为 nil 表示成功
+// 只应上报下游故障（网络错误、5xx 等），业务错误不应计入失败
+func (b *Breaker) Record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if err == nil {
+		b.state = StateClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == StateHalfOpen || b.failures >= b.failureThreshold {
+		b.state = StateOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// Release 放弃上报本次调用结果（如调用方取消），不改变状态和失败计数
+// 半开状态下释放试探名额，允许下一次调用继续试探
+func (b *Breaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// State 当前状态（冷却结束但尚未有调用时仍报告为 open）
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// Registry 熔断器注册表，按名称管理熔断器
+type Registry struct {
+	mu       sync.Mutex
+	breakers map[string]*Breaker
+}
+
+// NewRegistry 创建熔断器注册表
+func NewRegistry() *Registry {
+	return &Registry{breakers: make(map[string]*Breaker)}
+}
+
+// Get 按名称获取熔断器，不存在时使用给定参数创建并注册
+func (r *Registry) Get(name string, failureThreshold int, openTimeout time.Duration) *Breaker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if b, ok := r.breakers[name]; ok {
+		return b
+	}
+	b := New(name, failureThreshold, openTimeout)
+	r.breakers[name] = b
+	return b
+}
+
+// States 汇总所有熔断器的状态（名称 -> 状态）
+func (r *Registry) States() map[string]State {
+	r.mu.Lock()
+	breakers := make([]*Breaker, 0, len(r.breakers))
+	for _, b := range r.breakers {
+		breakers = append(breakers, b)
+	}
+	r.mu.Unlock()
+
+	states := make(map[string]State, len(breakers))
+	for _, b := range breakers {
+		states[b.Name()] = b.State()
+	}
+	return states
+}
+
+// defaultRegistry 全局熔断器注册表
+var defaultRegistry = NewRegistry()
+
+// Default 获取全局熔断器注册表
+func Default() *Registry {
+	return defaultRegistry
+}
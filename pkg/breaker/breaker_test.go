@@ -0,0 +1,154 @@
+package breaker
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testOpenTimeout = 20 * time.Millisecond
+
+// 熔断器操作
+const (
+	opAllow    = "allow"    // 调用 Allow
+	opSuccess  = "success"  // 上报成功
+	opFailure  = "failure"  // 上报失败
+	opRelease  = "release"  // 放弃上报
+	opCooldown = "cooldown" // 等待冷却结束
+)
+
+var errDownstream = errors.New("下游故障")
+
+func TestBreakerTransitions(t *testing.T) {
+	type step struct {
+		op      string
+		wantErr error // 仅 opAllow 检查
+		want    State // 操作后的状态
+	}
+	// 进入熔断状态的公共步骤（阈值为 2）
+	trip := []step{
+		{opAllow, nil, StateClosed},
+		{opFailure, nil, StateClosed},
+		{opAllow, nil, StateClosed},
+		{opFailure, nil, StateOpen},
+	}
+	// 冷却结束后放行一次试探调用，试探进行中拒绝其他调用
+	probe := append(trip,
+		step{opCooldown, nil, StateOpen},
+		step{opAllow, nil, StateHalfOpen},
+		step{opAllow, ErrOpen, StateHalfOpen},
+	)
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{"连续失败达到阈值后熔断", append(trip,
+			step{opAllow, ErrOpen, StateOpen},
+		)},
+		{"成功重置连续失败次数", []step{
+			{opAllow, nil, StateClosed},
+			{opFailure, nil, StateClosed},
+			{opAllow, nil, StateClosed},
+			{opSuccess, nil, StateClosed},
+			{opAllow, nil, StateClosed},
+			{opFailure, nil, StateClosed},
+		}},
+		{"试探成功后恢复", append(probe,
+			step{opSuccess, nil, StateClosed},
+			step{opAllow, nil, StateClosed},
+		)},
+		{"试探失败后重新熔断", append(probe,
+			step{opFailure, nil, StateOpen},
+			step{opAllow, ErrOpen, StateOpen},
+		)},
+		{"放弃试探后允许再次试探", append(probe,
+			step{opRelease, nil, StateHalfOpen},
+			step{opAllow, nil, StateHalfOpen},
+			step{opSuccess, nil, StateClosed},
+		)},
+		{"放弃上报不影响失败计数", []step{
+			{opAllow, nil, StateClosed},
+			{opFailure, nil, StateClosed},
+			{opAllow, nil, StateClosed},
+			{opRelease, nil, StateClosed},
+			{opAllow, nil, StateClosed},
+			{opFailure, nil, StateOpen},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New("test", 2, testOpenTimeout)
+			for i, s := range tt.steps {
+				switch s.op {
+				case opAllow:
+					if err := b.Allow(); !errors.Is(err, s.wantErr) {
+						t.Fatalf("步骤 %d: Allow = %v, want %v", i, err, s.wantErr)
+					}
+				case opSuccess:
+					b.Record(nil)
+				case opFailure:
+					b.Record(errDownstream)
+				case opRelease:
+					b.Release()
+				case opCooldown:
+					time.Sleep(testOpenTimeout + 10*time.Millisecond)
+				}
+				if got := b.State(); got != s.want {
+					t.Fatalf("步骤 %d（%s）后状态 = %s, want %s", i, s.op, got, s.want)
+				}
+			}
+		})
+	}
+}
+
+func TestBreakerHalfOpenAllowsSingleProbe(t *testing.T) {
+	b := New("test", 1, testOpenTimeout)
+	b.Record(errDownstream)
+	time.Sleep(testOpenTimeout + 10*time.Millisecond)
+
+	// 冷却结束后并发调用，只有一个试探调用被放行
+	var allowed atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if b.Allow() == nil {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := allowed.Load(); got != 1 {
+		t.Errorf("半开状态放行 %d 次调用, want 1", got)
+	}
+	if got := b.State(); got != StateHalfOpen {
+		t.Errorf("State = %s, want half_open", got)
+	}
+}
+
+func TestNewUsesDefaults(t *testing.T) {
+	b := New("test", 0, 0)
+	if b.failureThreshold != DefaultFailureThreshold || b.openTimeout != DefaultOpenTimeout {
+		t.Errorf("参数非法时应使用默认值，got threshold=%d timeout=%s", b.failureThreshold, b.openTimeout)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	a := r.Get("a", 1, time.Minute)
+	if r.Get("a", 5, time.Second) != a {
+		t.Error("同名熔断器应复用")
+	}
+	a.Record(errDownstream)
+	r.Get("b", 1, time.Minute)
+
+	states := r.States()
+	if states["a"] != StateOpen || states["b"] != StateClosed || len(states) != 2 {
+		t.Errorf("States = %v", states)
+	}
+}
@@ -0,0 +1,105 @@
+// Package errcode 业务错误码
+// 每个错误码在注册表中对应一个 HTTP 状态码和默认消息，逻辑层返回 *Error，
+// 控制器通过 BaseController.HandleError 统一渲染（响应体 code 为业务错误码）
+package errcode
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+)
+
+// Code 业务错误码（5 位：前 2 位为模块，后 3 位为序号）
+type Code int
+
+// 用户模块（10xxx）
+const (
+	UserNotFound    Code = 10001 // 用户不存在
+	DuplicateEmail  Code = 10002 // 邮箱已存在
+	VersionConflict Code = 10003 // 乐观锁版本冲突
+	InvalidUser     Code = 10004 // 用户数据不合法
+)
+
+// 租户模块（11xxx）
+const (
+	TenantRequired Code = 11001 // 缺少租户
+)
+
+// definition 错误码定义
+type definition struct {
+	status  int    // HTTP 状态码
+	message string // 默认消息
+}
+
+// registry 错误码注册表，新增错误码时必须在此登记
+var registry = map[Code]definition{
+	UserNotFound:    {http.StatusNotFound, "用户不存在"},
+	DuplicateEmail:  {http.StatusConflict, "邮箱已存在"},
+	VersionConflict: {http.StatusConflict, "用户已被修改，请刷新后重试"},
+	InvalidUser:     {http.StatusBadRequest, "用户数据不合法"},
+	TenantRequired:  {http.StatusBadRequest, "缺少租户信息"},
+}
+
+// HTTPStatus 错误码对应的 HTTP 状态码，未注册时返回 500
+func (c Code) HTTPStatus() int {
+	if def, ok := registry[c]; ok {
+		return def.status
+	}
+	return http.StatusInternalServerError
+}
+
+// Message 错误码的默认消息，未注册时返回"未知错误"
+func (c Code) Message() string {
+	if def, ok := registry[c]; ok {
+		return def.message
+	}
+	return "未知错误"
+}
+
+// Codes 所有已注册的错误码（升序），用于生成文档和校验
+func Codes() []Code {
+	codes := make([]Code, 0, len(registry))
+	for code := range registry {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+	return codes
+}
+
+// Error 业务错误
+type Error struct {
+	Code    Code   // 业务错误码
+	Message string // 错误消息（为空时使用错误码的默认消息）
+	Err     error  // 原始错误（可选），支持 errors.Is/As 判断
+}
+
+// New 创建业务错误，message 为空时使用错误码的默认消息
+func New(code Code, message string) *Error {
+	if message == "" {
+		message = code.Message()
+	}
+	return &Error{Code: code, Message: message}
+}
+
+// Wrap 使用错误码包装原始错误（消息为错误码的默认消息）
+func Wrap(code Code, err error) *Error {
+	return &Error{Code: code, Message: code.Message(), Err: err}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap 返回原始错误
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// FromError 从错误链中提取业务错误
+func FromError(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+	return nil, false
+}
@@ -0,0 +1,60 @@
+package errcode
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestEveryCodeIsRegistered(t *testing.T) {
+	codes := Codes()
+	if len(codes) == 0 {
+		t.Fatal("注册表为空")
+	}
+	for i, code := range codes {
+		if code < 10000 || code > 99999 {
+			t.Errorf("%d: 错误码应为 5 位", code)
+		}
+		if i > 0 && codes[i-1] >= code {
+			t.Errorf("Codes() 未按升序排列: %v", codes)
+		}
+		if status := code.HTTPStatus(); status < http.StatusBadRequest || status > 599 || http.StatusText(status) == "" {
+			t.Errorf("%d: HTTP 状态码 %d 无效", code, status)
+		}
+		if code.Message() == "" || code.Message() == "未知错误" {
+			t.Errorf("%d: 缺少默认消息", code)
+		}
+	}
+}
+
+func TestUnregisteredCode(t *testing.T) {
+	if got := Code(99999).HTTPStatus(); got != http.StatusInternalServerError {
+		t.Errorf("未注册错误码 HTTPStatus = %d, want 500", got)
+	}
+	if got := Code(99999).Message(); got != "未知错误" {
+		t.Errorf("未注册错误码 Message = %q", got)
+	}
+}
+
+func TestErrorWrapping(t *testing.T) {
+	if got := New(UserNotFound, "").Error(); got != UserNotFound.Message() {
+		t.Errorf("New 默认消息 = %q", got)
+	}
+	if got := New(UserNotFound, "用户 7 不存在").Error(); got != "用户 7 不存在" {
+		t.Errorf("New 自定义消息 = %q", got)
+	}
+
+	cause := errors.New("record not found")
+	err := fmt.Errorf("查询失败: %w", Wrap(UserNotFound, cause))
+	if !errors.Is(err, cause) {
+		t.Error("Wrap 应保留原始错误")
+	}
+	e, ok := FromError(err)
+	if !ok || e.Code != UserNotFound {
+		t.Errorf("FromError = (%v, %v)", e, ok)
+	}
+	if _, ok := FromError(cause); ok {
+		t.Error("普通错误不应解析为业务错误")
+	}
+}
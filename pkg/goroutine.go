@@ -0,0 +1,51 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Go 启动携带追踪信息的 goroutine，替代直接使用 go func(){...}
+// fn 使用脱离 ctx 生命周期的上下文执行（不随请求结束而取消，保留 ctx 中的值）；ctx 中带有 span 时，
+// fn 在名为 name 的新根 span 中执行，并通过 span link 关联到原 span；panic 会被恢复并记录到 span。
+// 返回的 channel 在 fn 执行完毕（span 已结束）后关闭，不需要等待时忽略即可
+//
+// 不限制并发数，高频调用（如每个请求都触发）应使用 Background().Submit（其 worker 通过 Go 执行任务）
+func Go(ctx context.Context, name string, fn func(ctx context.Context)) <-chan struct{} {
+	origin := trace.SpanContextFromContext(ctx)
+	detached := context.WithoutCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runLinked(detached, origin, name, fn)
+	}()
+	return done
+}
+
+// runLinked 在关联到 origin 的新根 span 中执行 fn（origin 无效时不创建 span），并恢复 panic（记录到 span）
+func runLinked(ctx context.Context, origin trace.SpanContext, name string, fn func(ctx context.Context)) {
+	span := trace.SpanFromContext(ctx)
+	if origin.IsValid() {
+		ctx, span = StartSpan(ctx, Tracer, name,
+			trace.WithNewRoot(),
+			trace.WithLinks(trace.Link{SpanContext: origin}),
+		)
+		defer span.End()
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			log.Printf("%s panic: %v", name, recovered)
+			if origin.IsValid() {
+				err := fmt.Errorf("panic: %v", recovered)
+				span.RecordError(err, trace.WithStackTrace(true))
+				span.SetStatus(codes.Error, err.Error())
+			}
+		}
+	}()
+	fn(ctx)
+}
@@ -0,0 +1,121 @@
+package pkg
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// wait 等待 Go 启动的 goroutine 结束
+func wait(t *testing.T, done <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("等待 goroutine 结束超时")
+	}
+}
+
+func TestGoLinksToCallerSpan(t *testing.T) {
+	spans := recordedSpans(t)
+
+	ctx, cancel := context.WithCancel(WithTenant(context.Background(), "acme"))
+	ctx, parent := StartSpan(ctx, Tracer, "request")
+	started, release := make(chan struct{}), make(chan struct{})
+	var taskCtx context.Context
+	done := Go(ctx, "cache.set", func(ctx context.Context) {
+		taskCtx = ctx
+		close(started)
+		<-release
+	})
+
+	// 调用方结束（请求返回）后 goroutine 继续执行，上下文不随之取消
+	<-started
+	parent.End()
+	cancel()
+	close(release)
+	wait(t, done)
+
+	if taskCtx.Err() != nil {
+		t.Errorf("goroutine 上下文不应随调用方取消: %v", taskCtx.Err())
+	}
+	if got, _ := TenantFromContext(taskCtx); got != "acme" {
+		t.Errorf("goroutine 上下文应保留调用方的值，tenant = %q", got)
+	}
+
+	task := findSpan(t, spans(), "cache.set")
+	if task.Parent().IsValid() {
+		t.Errorf("goroutine 应在新的根 span 中执行，parent = %s", task.Parent().SpanID())
+	}
+	links := task.Links()
+	if len(links) != 1 || links[0].SpanContext.SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("goroutine span 应通过 link 关联到调用方 span，links = %v", links)
+	}
+	if spanCtx := trace.SpanContextFromContext(taskCtx); spanCtx.SpanID() != task.SpanContext().SpanID() {
+		t.Error("fn 收到的上下文应携带 goroutine 的 span")
+	}
+}
+
+func TestGoRecoversPanic(t *testing.T) {
+	spans := recordedSpans(t)
+
+	ctx, parent := StartSpan(context.Background(), Tracer, "request")
+	wait(t, Go(ctx, "boom.task", func(context.Context) { panic("boom") }))
+	parent.End()
+
+	span := findSpan(t, spans(), "boom.task")
+	if span.Status().Code != codes.Error || span.Status().Description != "panic: boom" {
+		t.Errorf("status = %v %q, want Error %q", span.Status().Code, span.Status().Description, "panic: boom")
+	}
+	if len(span.Events()) == 0 || span.Events()[0].Name != "exception" {
+		t.Errorf("panic 应作为异常事件记录到 span，events = %v", span.Events())
+	}
+}
+
+func TestGoWithoutSpanCreatesNone(t *testing.T) {
+	spans := recordedSpans(t)
+
+	ran := false
+	wait(t, Go(context.Background(), "untraced", func(context.Context) { ran = true }))
+	wait(t, Go(context.Background(), "untraced", func(context.Context) { panic("boom") }))
+
+	if !ran {
+		t.Error("fn 应被执行")
+	}
+	if got := len(spans()); got != 0 {
+		t.Errorf("调用方没有 span 时不应创建 span，got %d", got)
+	}
+}
+
+func TestBackgroundTaskRunsThroughGo(t *testing.T) {
+	spans := recordedSpans(t)
+	runner := NewBackgroundRunner(1, 2, 0)
+
+	ctx, parent := StartSpan(context.Background(), Tracer, "request")
+	runner.Submit(ctx, func(context.Context) { panic("boom") })
+	ran := false
+	runner.Submit(ctx, func(context.Context) { ran = true })
+	parent.End()
+	shutdown(t, runner)
+
+	if !ran {
+		t.Error("panic 后 worker 应继续执行后续任务")
+	}
+	task := findSpan(t, spans(), backgroundSpanName)
+	if links := task.Links(); len(links) != 1 || links[0].SpanContext.SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("后台任务应通过 link 关联到提交方 span，links = %v", links)
+	}
+}
+
+// shutdown 等待执行器中的任务执行完毕
+func shutdown(t *testing.T, runner *BackgroundRunner) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := runner.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package grpcclient
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+var (
+	// tracingEnabled 追踪是否启用（通过 Init 设置）
+	tracingEnabled bool
+	// defaultTarget 默认的内部 gRPC 服务地址（通过 Init 设置）
+	defaultTarget string
+)
+
+// Init 初始化 gRPC 客户端配置
+// 根据追踪开关决定是否注册 otelgrpc stats handler，优化性能
+func Init(enabled bool, target string) {
+	tracingEnabled = enabled
+	defaultTarget = target
+}
+
+// Dial 连接默认的内部 gRPC 服务（地址来自配置 app.grpcPort）
+func Dial(opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	if defaultTarget == "" {
+		return nil, fmt.Errorf("gRPC 目标地址未配置")
+	}
+	return DialTarget(defaultTarget, opts...)
+}
+
+// DialTarget 连接指定的 gRPC 服务
+// 仅在追踪启用时注册 otelgrpc stats handler，所有 RPC 调用自动追踪并传播 TraceID
+// 默认使用非加密连接（内部服务），可通过 opts 覆盖
+func DialTarget(target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	dialOpts := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()), // 内部服务，生产环境应使用安全连接
+	}
+
+	// 仅在追踪启用时注册 stats handler，避免不必要的性能开销
+	if tracingEnabled {
+		dialOpts = append(dialOpts, grpc.WithStatsHandler(otelgrpc.NewClientHandler()))
+	}
+
+	dialOpts = append(dialOpts, opts...)
+
+	conn, err := grpc.NewClient(target, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("创建 gRPC 连接失败: %v", err)
+	}
+	return conn, nil
+}
@@ -0,0 +1,89 @@
+package grpcclient
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	sdktracetest "go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// startBufconnServer 启动进程内 gRPC 服务（注册健康检查服务），返回连接该服务的拨号选项
+func startBufconnServer(t *testing.T) grpc.DialOption {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	return grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.DialContext(ctx)
+	})
+}
+
+// recordSpans 将全局 TracerProvider 替换为内存 span 记录器，测试结束时恢复
+func recordSpans(t *testing.T) *sdktracetest.SpanRecorder {
+	t.Helper()
+	recorder := sdktracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+// checkHealth 通过 DialTarget 建立连接并调用一次健康检查
+func checkHealth(t *testing.T, dialer grpc.DialOption) {
+	t.Helper()
+	conn, err := DialTarget("passthrough:///bufnet", dialer)
+	if err != nil {
+		t.Fatalf("DialTarget: %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+}
+
+func TestDialTargetTracesCalls(t *testing.T) {
+	dialer := startBufconnServer(t)
+	recorder := recordSpans(t)
+	Init(true, "")
+
+	checkHealth(t, dialer)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("span 数 = %d, want 1", len(spans))
+	}
+	if spans[0].Name() != "grpc.health.v1.Health/Check" || spans[0].SpanKind() != trace.SpanKindClient {
+		t.Errorf("span = %s (%s), want grpc.health.v1.Health/Check (client)", spans[0].Name(), spans[0].SpanKind())
+	}
+}
+
+func TestDialTargetWithoutTracing(t *testing.T) {
+	dialer := startBufconnServer(t)
+	recorder := recordSpans(t)
+	Init(false, "")
+
+	checkHealth(t, dialer)
+
+	if got := len(recorder.Ended()); got != 0 {
+		t.Errorf("追踪未启用时不应创建 span，got %d", got)
+	}
+}
+
+func TestDialRequiresTarget(t *testing.T) {
+	Init(false, "")
+	if _, err := Dial(); err == nil {
+		t.Error("未配置目标地址时应返回错误")
+	}
+}
@@ -8,21 +8,89 @@ import (
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
+// DefaultHTTPTimeout 全局 HTTP 客户端默认超时
+const DefaultHTTPTimeout = 10 * time.Second
+
 var (
 	// tracingEnabled 追踪是否启用（通过 InitHTTPClient 设置）
 	tracingEnabled bool
+	// clientOptions 全局客户端选项（通过 InitHTTPClient 设置），NewHTTPClient 创建的专用客户端沿用
+	clientOptions []HTTPClientOption
 	// httpClient 全局 HTTP 客户端
 	httpClient *req.Client
 )
 
+// HTTPClientOption HTTP 客户端配置选项
+type HTTPClientOption func(*req.Client)
+
+// WithRetry 启用失败重试
+// 重试按请求方法区分：GET/HEAD/PUT/DELETE/OPTIONS 默认重试，
+// POST 等非幂等方法只有在请求 context 通过 WithRetrySafe 标记后才会重试；
+// count <= 0 时客户端不重试，但退避区间仍设置到客户端，作为请求级重试（SetRetryCount）的退避区间；
+// 退避区间 <= 0 时使用默认值
+func WithRetry(count int, minBackoff, maxBackoff time.Duration) HTTPClientOption {
+	if minBackoff <= 0 {
+		minBackoff = DefaultRetryMinBackoff
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultRetryMaxBackoff
+	}
+	return func(c *req.Client) {
+		c.SetCommonRetryBackoffInterval(minBackoff, maxBackoff)
+		if count <= 0 {
+			return
+		}
+		c.SetCommonRetryCount(count).
+			SetCommonRetryCondition(RetryCondition)
+	}
+}
+
+// WithUserAgent 设置 User-Agent，便于下游在日志中识别调用方
+func WithUserAgent(userAgent string) HTTPClientOption {
+	return func(c *req.Client) {
+		if userAgent != "" {
+			c.SetUserAgent(userAgent)
+		}
+	}
+}
+
+// WithHeaders 设置所有请求默认携带的公共请求头
+func WithHeaders(headers map[string]string) HTTPClientOption {
+	return func(c *req.Client) {
+		if len(headers) > 0 {
+			c.SetCommonHeaders(headers)
+		}
+	}
+}
+
 // InitHTTPClient 初始化 HTTP 客户端
 // 根据追踪开关决定是否启用追踪，优化性能
-func InitHTTPClient(enabled bool) {
+func InitHTTPClient(enabled bool, opts ...HTTPClientOption) {
 	tracingEnabled = enabled
+	clientOptions = opts
+	httpClient = newHTTPClient(enabled, DefaultHTTPTimeout, opts)
+}
 
+// NewHTTPClient 创建与全局客户端配置相同（追踪、重试、请求头等）但超时独立的专用客户端
+// 用于为单个下游服务设置超时，避免不同服务之间互相影响；timeout <= 0 时使用默认超时
+func NewHTTPClient(timeout time.Duration) *req.Client {
+	if timeout <= 0 {
+		timeout = DefaultHTTPTimeout
+	}
+	return newHTTPClient(tracingEnabled, timeout, clientOptions)
+}
+
+// newHTTPClient 创建 HTTP 客户端，追踪启用时包装带追踪的 Transport
+func newHTTPClient(enabled bool, timeout time.Duration, opts []HTTPClientOption) *req.Client {
+	// 请求创建时复制客户端的重试配置，请求级重试沿用客户端的退避区间（默认值可由 WithRetry 覆盖）
 	client := req.C().
-		SetTimeout(10*time.Second).
-		SetCommonHeader("Content-Type", "application/json")
+		SetTimeout(timeout).
+		SetCommonHeader("Content-Type", "application/json").
+		SetCommonRetryBackoffInterval(DefaultRetryMinBackoff, DefaultRetryMaxBackoff)
+
+	for _, opt := range opts {
+		opt(client)
+	}
 
 	// 仅在追踪启用时包装 Transport，避免不必要的性能开销
 	if enabled {
@@ -36,7 +104,7 @@ func InitHTTPClient(enabled bool) {
 		httpClientInstance.Transport = otelhttp.NewTransport(baseTransport)
 	}
 
-	httpClient = client
+	return client
 }
 
 // HTTPClient 获取全局带追踪的 HTTP 客户端
@@ -0,0 +1,41 @@
+package pkg
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/imroc/req/v3"
+)
+
+// sensitiveHeaderPattern 报文中需要脱敏的请求/响应头
+var sensitiveHeaderPattern = regexp.MustCompile(`(?im)^(authorization|proxy-authorization|cookie|set-cookie|x-api-key):.*$`)
+
+// WithDump 记录每次出站请求和响应的完整报文（含请求体、响应体，敏感头和敏感字段已脱敏）
+// 仅用于调试下游集成问题，报文可能包含业务数据且有额外开销，不应在 release 模式开启
+func WithDump() HTTPClientOption {
+	return func(c *req.Client) {
+		c.EnableDumpEachRequest().
+			OnAfterResponse(func(_ *req.Client, resp *req.Response) error {
+				if resp.Request == nil {
+					return nil
+				}
+				LoggerFromContext(resp.Request.Context()).Info("出站 HTTP 报文",
+					"method", resp.Request.Method,
+					"url", resp.Request.RawURL,
+					"dump", redactDump(resp.Dump()),
+				)
+				return nil
+			})
+	}
+}
+
+// redactDump 将报文中的敏感头和报文体中的敏感字段（含 log.redactFields 配置的路径）替换为脱敏值
+// 报文按空行拆分，能整体解析为 JSON 的部分按字段路径脱敏，其余部分按字段名模式替换
+func redactDump(dump string) string {
+	dump = sensitiveHeaderPattern.ReplaceAllString(dump, "$1: "+redactedValue)
+	parts := strings.Split(dump, "\r\n\r\n")
+	for i, part := range parts {
+		parts[i] = RedactBody([]byte(part))
+	}
+	return strings.Join(parts, "\r\n\r\n")
+}
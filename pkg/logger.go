@@ -0,0 +1,23 @@
+package pkg
+
+import (
+	"context"
+	"log/slog"
+)
+
+// loggerKey 请求级日志记录器在 context 中的键
+type loggerKey struct{}
+
+// WithLogger 将日志记录器存入 context
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// LoggerFromContext 获取请求级日志记录器（已绑定 trace_id、request_id 等关联字段）
+// context 中没有时返回 slog.Default()，调用方无需判空
+func LoggerFromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok && logger != nil {
+		return logger
+	}
+	return slog.Default()
+}
@@ -0,0 +1,103 @@
+package pkg
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// LRU 并发安全的进程内 LRU 缓存，支持条目过期
+// 容量满时淘汰最久未使用的条目，过期条目在读取时删除
+type LRU[K comparable, V any] struct {
+	mu    sync.Mutex
+	size  int
+	ttl   time.Duration
+	order *list.List // 最近使用的条目在前
+	items map[K]*list.Element
+}
+
+// lruEntry LRU 缓存条目
+type lruEntry[K comparable, V any] struct {
+	key      K
+	value    V
+	expireAt time.Time
+}
+
+// NewLRU 创建 LRU 缓存
+// size 为最大条目数，ttl 为条目过期时间（<=0 表示不过期）
+func NewLRU[K comparable, V any](size int, ttl time.Duration) *LRU[K, V] {
+	if size <= 0 {
+		size = 1
+	}
+	return &LRU[K, V]{
+		size:  size,
+		ttl:   ttl,
+		order: list.New(),
+		items: make(map[K]*list.Element, size),
+	}
+}
+
+// Get 获取缓存值，不存在或已过期时返回 false
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+	elem, ok := c.items[key]
+	if !ok {
+		return zero, false
+	}
+
+	entry := elem.Value.(*lruEntry[K, V])
+	if c.ttl > 0 && time.Now().After(entry.expireAt) {
+		c.removeElement(elem)
+		return zero, false
+	}
+
+	c.order.MoveToFront(elem)
+	return entry.value, true
+}
+
+// Set 设置缓存值，容量满时淘汰最久未使用的条目
+func (c *LRU[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expireAt := time.Now().Add(c.ttl)
+	if elem, ok := c.items[key]; ok {
+		entry := elem.Value.(*lruEntry[K, V])
+		entry.value = value
+		entry.expireAt = expireAt
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&lruEntry[K, V]{key: key, value: value, expireAt: expireAt})
+	if c.order.Len() > c.size {
+		c.removeElement(c.order.Back())
+	}
+}
+
+// Delete 删除缓存值
+func (c *LRU[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		c.removeElement(elem)
+	}
+}
+
+// Len 当前条目数（包含尚未清理的过期条目）
+func (c *LRU[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// removeElement 删除条目（调用方需持有锁）
+func (c *LRU[K, V]) removeElement(elem *list.Element) {
+	entry := elem.Value.(*lruEntry[K, V])
+	delete(c.items, entry.key)
+	c.order.Remove(elem)
+}
@@ -0,0 +1,54 @@
+package pkg
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewLRU[string, int](2, 0)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a") // a 变为最近使用
+	c.Set("c", 3)
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("容量满时应淘汰最久未使用的 b")
+	}
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Errorf("a = %d, %v", v, ok)
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len = %d, want 2", c.Len())
+	}
+}
+
+func TestLRUExpiresAndDeletes(t *testing.T) {
+	c := NewLRU[string, int](10, 10*time.Millisecond)
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	c.Delete("a")
+	if _, ok := c.Get("a"); ok {
+		t.Error("删除后不应命中")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := c.Get("b"); ok {
+		t.Error("过期后不应命中")
+	}
+	if c.Len() != 0 {
+		t.Errorf("过期条目读取时应删除，Len = %d", c.Len())
+	}
+}
+
+func BenchmarkLRUGet(b *testing.B) {
+	c := NewLRU[int, int](1000, time.Minute)
+	for i := 0; i < 1000; i++ {
+		c.Set(i, i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Get(i % 1000)
+	}
+}
@@ -0,0 +1,42 @@
+package pkg
+
+import (
+	"os"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	sdktracetest "go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// spanRecorder 包内测试共用的 span 记录器
+// 包级追踪器（Tracer）只会绑定到第一次设置的全局 TracerProvider，因此整个测试进程只安装一次，用例之间通过 Reset 清空
+var spanRecorder = sdktracetest.NewSpanRecorder()
+
+func TestMain(m *testing.M) {
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithSpanProcessor(spanRecorder),
+	))
+	os.Exit(m.Run())
+}
+
+// recordedSpans 返回已结束的 span，并注册用例结束时清空记录
+func recordedSpans(t *testing.T) func() []sdktrace.ReadOnlySpan {
+	t.Helper()
+	spanRecorder.Reset()
+	t.Cleanup(spanRecorder.Reset)
+	return spanRecorder.Ended
+}
+
+// findSpan 按名称查找 span，未找到时终止用例
+func findSpan(t *testing.T, spans []sdktrace.ReadOnlySpan, name string) sdktrace.ReadOnlySpan {
+	t.Helper()
+	for _, span := range spans {
+		if span.Name() == name {
+			return span
+		}
+	}
+	t.Fatalf("未找到 span %q", name)
+	return nil
+}
@@ -0,0 +1,16 @@
+package pkg
+
+import "sync/atomic"
+
+// maintenance 维护模式开关（由配置初始化，可通过管理接口在运行时切换）
+var maintenance atomic.Bool
+
+// SetMaintenance 开启或关闭维护模式
+func SetMaintenance(enabled bool) {
+	maintenance.Store(enabled)
+}
+
+// InMaintenance 是否处于维护模式
+func InMaintenance() bool {
+	return maintenance.Load()
+}
@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Namespace 指标名前缀
+const Namespace = "gin_project"
+
+// enabled 指标是否启用（通过 Init 设置）
+var enabled bool
+
+// Init 初始化指标开关
+// 未启用时所有注册操作均为空操作，零性能开销
+func Init(on bool) {
+	enabled = on
+	if on {
+		log.Println("Prometheus 指标已启用")
+	}
+}
+
+// Enabled 指标是否启用
+func Enabled() bool {
+	return enabled
+}
+
+// Register 注册指标采集器（仅在指标启用时注册）
+// 重复注册同名指标时忽略，便于在初始化函数中重复调用
+func Register(collectors ...prometheus.Collector) {
+	if !enabled {
+		return
+	}
+	for _, c := range collectors {
+		if err := prometheus.Register(c); err != nil {
+			if _, ok := err.(prometheus.AlreadyRegisteredError); ok {
+				continue
+			}
+			log.Printf("注册指标失败: %v", err)
+		}
+	}
+}
+
+// Handler 指标暴露接口（Prometheus 抓取格式）
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,149 @@
+package pkg
+
+import (
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// sensitiveFieldPattern 请求体和查询参数中需要脱敏的字段名
+var sensitiveFieldPattern = regexp.MustCompile(`(?i)(password|passwd|secret|token|authorization|api[_-]?key)`)
+
+// redactedValue 脱敏后的字段值
+const redactedValue = "***"
+
+// sensitiveJSONFieldPattern 无法解析的 JSON 片段（如被截断的请求体）中的敏感字段
+var sensitiveJSONFieldPattern = regexp.MustCompile(`(?i)("[^"]*(?:password|passwd|secret|token|authorization|api[_-]?key)[^"]*"\s*:\s*)("(?:[^"\\]|\\.)*"?|[^,}\]\s]*)`)
+
+// redactRules 配置的额外脱敏字段路径（log.redactFields），由 InitRedaction 设置
+var redactRules struct {
+	sync.RWMutex
+	paths   [][]string     // 按 . 拆分的字段路径
+	pattern *regexp.Regexp // 无法解析的片段中按字段名（路径最后一段）匹配，无规则时为 nil
+}
+
+// InitRedaction 设置额外的脱敏字段路径，作用于失败请求记录和出站报文（httpClient.dump）
+// 路径按 . 分隔，数组元素沿用数组字段的路径（items.card 匹配 items 数组每个元素的 card）：
+// 只有一段时匹配任意层级的同名字段（如 email），多段时从根开始匹配（如 user.email），* 匹配任意字段名；字段名不区分大小写
+func InitRedaction(fields []string) {
+	paths := make([][]string, 0, len(fields))
+	names := make([]string, 0, len(fields))
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		path := strings.Split(field, ".")
+		paths = append(paths, path)
+		if name := path[len(path)-1]; name != "*" {
+			names = append(names, regexp.QuoteMeta(name))
+		}
+	}
+
+	var pattern *regexp.Regexp
+	if len(names) > 0 {
+		pattern = regexp.MustCompile(`(?i)("(?:` + strings.Join(names, "|") + `)"\s*:\s*)("(?:[^"\\]|\\.)*"?|[^,}\]\s]*)`)
+	}
+
+	redactRules.Lock()
+	defer redactRules.Unlock()
+	redactRules.paths = paths
+	redactRules.pattern = pattern
+}
+
+// RedactBody 脱敏 JSON 请求体中的敏感字段和配置的字段路径（递归处理嵌套对象和数组）
+// 无法解析的请求体（如被截断）按字段名模式替换，非 JSON 请求体原样返回
+func RedactBody(body []byte) string {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return redactText(string(body))
+	}
+	redacted, err := json.Marshal(redactValue(value, nil))
+	if err != nil {
+		return string(body)
+	}
+	return string(redacted)
+}
+
+// redactText 按字段名模式替换文本中 JSON 字段的值（用于无法整体解析的片段）
+func redactText(text string) string {
+	text = sensitiveJSONFieldPattern.ReplaceAllString(text, `$1"`+redactedValue+`"`)
+
+	redactRules.RLock()
+	pattern := redactRules.pattern
+	redactRules.RUnlock()
+	if pattern != nil {
+		text = pattern.ReplaceAllString(text, `$1"`+redactedValue+`"`)
+	}
+	return text
+}
+
+// redactValue 递归替换敏感字段的值，path 为当前对象所在的字段路径
+func redactValue(value interface{}, path []string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			fieldPath := append(path[:len(path):len(path)], key)
+			if sensitiveFieldPattern.MatchString(key) || redactPathMatches(fieldPath) {
+				v[key] = redactedValue
+			} else {
+				v[key] = redactValue(field, fieldPath)
+			}
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactValue(v[i], path)
+		}
+	}
+	return value
+}
+
+// redactPathMatches 判断字段路径是否命中配置的脱敏规则
+func redactPathMatches(path []string) bool {
+	redactRules.RLock()
+	defer redactRules.RUnlock()
+
+	for _, rule := range redactRules.paths {
+		if len(rule) == 1 {
+			if strings.EqualFold(rule[0], path[len(path)-1]) {
+				return true
+			}
+			continue
+		}
+		if len(rule) != len(path) {
+			continue
+		}
+		matched := true
+		for i, segment := range rule {
+			if segment != "*" && !strings.EqualFold(segment, path[i]) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactQuery 脱敏查询参数中的敏感参数
+func RedactQuery(rawQuery string) string {
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return rawQuery
+	}
+	redacted := false
+	for key := range values {
+		if sensitiveFieldPattern.MatchString(key) || redactPathMatches([]string{key}) {
+			values[key] = []string{redactedValue}
+			redacted = true
+		}
+	}
+	if !redacted {
+		return rawQuery
+	}
+	return values.Encode()
+}
@@ -0,0 +1,92 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// useRedaction 设置额外的脱敏字段路径，测试结束时清空
+func useRedaction(t *testing.T, fields ...string) {
+	t.Helper()
+	InitRedaction(fields)
+	t.Cleanup(func() { InitRedaction(nil) })
+}
+
+func TestRedactBodyMasksConfiguredFields(t *testing.T) {
+	useRedaction(t, "email", "user.phone", "items.card")
+
+	body := `{"name":"alice","email":"alice@example.com","password":"hunter2",` +
+		`"user":{"phone":"13800000000","profile":{"phone":"keep"}},"items":[{"card":"4111","sku":"a"}]}`
+	var got map[string]interface{}
+	if err := json.Unmarshal([]byte(RedactBody([]byte(body))), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	user := got["user"].(map[string]interface{})
+	item := got["items"].([]interface{})[0].(map[string]interface{})
+	for name, value := range map[string]interface{}{
+		"email":      got["email"],
+		"password":   got["password"],
+		"user.phone": user["phone"],
+		"items.card": item["card"],
+	} {
+		if value != "***" {
+			t.Errorf("%s = %v, want ***", name, value)
+		}
+	}
+	if got["name"] != "alice" || item["sku"] != "a" {
+		t.Errorf("未配置的字段不应脱敏: %v", got)
+	}
+	if phone := user["profile"].(map[string]interface{})["phone"]; phone != "keep" {
+		t.Errorf("user.profile.phone = %v, 多段路径应从根开始匹配", phone)
+	}
+
+	if text := RedactBody([]byte(`{"email":"alice@example.com","na`)); strings.Contains(text, "alice@example.com") {
+		t.Errorf("截断的请求体中配置的字段未脱敏: %s", text)
+	}
+}
+
+func TestDumpLogMasksConfiguredFields(t *testing.T) {
+	useRedaction(t, "email")
+	var logs bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"email":"reply@example.com","ok":true}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := newHTTPClient(false, time.Second, []HTTPClientOption{WithDump()})
+	_, err := client.R().
+		SetContext(context.Background()).
+		SetHeader("Authorization", "Bearer abc").
+		SetBody(map[string]string{"email": "alice@example.com", "name": "alice"}).
+		Post(server.URL)
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(bytes.TrimSpace(logs.Bytes()), &record); err != nil {
+		t.Fatalf("解析日志失败: %v, logs = %s", err, logs.String())
+	}
+	dump, _ := record["dump"].(string)
+	for _, secret := range []string{"alice@example.com", "reply@example.com", "Bearer abc"} {
+		if strings.Contains(dump, secret) {
+			t.Errorf("出站报文日志包含未脱敏的 %q: %s", secret, dump)
+		}
+	}
+	if !strings.Contains(dump, `"email":"***"`) || !strings.Contains(dump, `"name":"alice"`) {
+		t.Errorf("出站报文日志 = %s, want email 脱敏为 *** 且其他字段保留", dump)
+	}
+}
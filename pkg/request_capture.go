@@ -0,0 +1,78 @@
+package pkg
+
+import (
+	"sync"
+	"time"
+)
+
+// FailedRequest 失败请求记录（调试模式下用于复现问题）
+type FailedRequest struct {
+	Time          time.Time `json:"time"`           // 请求开始时间
+	Method        string    `json:"method"`         // 请求方法
+	Path          string    `json:"path"`           // 请求路径
+	Query         string    `json:"query"`          // 查询参数（敏感参数已脱敏）
+	ContentType   string    `json:"content_type"`   // 请求体类型
+	Body          string    `json:"body"`           // 请求体（截断，敏感字段已脱敏）
+	BodyTruncated bool      `json:"body_truncated"` // 请求体是否被截断
+	Status        int       `json:"status"`         // HTTP 状态码
+	Latency       string    `json:"latency"`        // 请求耗时
+	TraceID       string    `json:"trace_id"`       // 追踪ID
+}
+
+// RequestRecorder 环形缓冲区，保留最近 size 条失败请求
+type RequestRecorder struct {
+	mu      sync.Mutex
+	entries []FailedRequest
+	next    int
+	full    bool
+}
+
+// NewRequestRecorder 创建失败请求记录器，size 为最多保留的条数
+func NewRequestRecorder(size int) *RequestRecorder {
+	if size <= 0 {
+		size = 1
+	}
+	return &RequestRecorder{entries: make([]FailedRequest, size)}
+}
+
+// Record 记录一条失败请求，缓冲区满时覆盖最早的记录
+func (r *RequestRecorder) Record(req FailedRequest) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries[r.next] = req
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Recent 返回已记录的失败请求（最新的在前）
+func (r *RequestRecorder) Recent() []FailedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := r.next
+	if r.full {
+		n = len(r.entries)
+	}
+	result := make([]FailedRequest, 0, n)
+	for i := 1; i <= n; i++ {
+		result = append(result, r.entries[(r.next-i+len(r.entries))%len(r.entries)])
+	}
+	return result
+}
+
+// failedRequests 全局失败请求记录器（调试模式下由 InitRequestCapture 创建）
+var failedRequests *RequestRecorder
+
+// InitRequestCapture 创建全局失败请求记录器，应在注册路由前调用
+func InitRequestCapture(size int) *RequestRecorder {
+	failedRequests = NewRequestRecorder(size)
+	return failedRequests
+}
+
+// FailedRequests 获取全局失败请求记录器，未初始化时返回 nil
+func FailedRequests() *RequestRecorder {
+	return failedRequests
+}
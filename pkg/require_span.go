@@ -0,0 +1,53 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrNoSpan context 中没有有效的 span（通常是没有把请求 context 传递下来，或追踪中间件未注册）
+var ErrNoSpan = errors.New("context 中没有有效的 span，请检查是否传递了请求 context")
+
+// strictSpans 严格模式：RequireSpan 找不到有效 span 时 panic（tracing.strictSpans，用于 CI/测试环境）
+var strictSpans atomic.Bool
+
+// SetStrictSpans 开启或关闭 RequireSpan 的严格模式
+func SetStrictSpans(strict bool) {
+	strictSpans.Store(strict)
+}
+
+// CheckSpan 获取 context 中的 span，span 无效时返回 ErrNoSpan
+// 未被采样的 span 同样有效（SpanContext 有效，只是不导出）
+func CheckSpan(ctx context.Context) (trace.Span, error) {
+	span := trace.SpanFromContext(ctx)
+	if !span.SpanContext().IsValid() {
+		return span, ErrNoSpan
+	}
+	return span, nil
+}
+
+// RequireSpan 获取 context 中的 span，用于断言关键接口的追踪链路已正确接入
+// 追踪未启用时不做检查；span 无效时严格模式（tracing.strictSpans）下 panic，便于在 CI 中尽早发现，
+// 否则输出 WARN 日志并返回不记录的 span，调用方可照常使用
+//
+// 使用示例:
+//
+//	span := pkg.RequireSpan(c.Request.Context())
+//	span.SetAttributes(attribute.Int("user.id", int(id)))
+func RequireSpan(ctx context.Context) trace.Span {
+	span, err := CheckSpan(ctx)
+	if err == nil {
+		return span
+	}
+	if _, disabled := TraceSampleRate(); disabled != nil {
+		return span
+	}
+	if strictSpans.Load() {
+		panic(err)
+	}
+	LoggerFromContext(ctx).Warn("追踪链路缺失", "error", err)
+	return span
+}
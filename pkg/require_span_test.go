@@ -0,0 +1,91 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// useRequireSpan 设置追踪采样器（nil 表示追踪未启用）和严格模式，测试结束时恢复
+func useRequireSpan(t *testing.T, sampler *RatioSampler, strict bool) {
+	t.Helper()
+	SetTraceSampler(sampler)
+	SetStrictSpans(strict)
+	t.Cleanup(func() {
+		SetTraceSampler(nil)
+		SetStrictSpans(false)
+	})
+}
+
+// requireSpanPanic 调用 RequireSpan，返回 panic 值（未 panic 时为 nil）
+func requireSpanPanic(ctx context.Context) (recovered interface{}) {
+	defer func() { recovered = recover() }()
+	RequireSpan(ctx)
+	return nil
+}
+
+func TestCheckSpan(t *testing.T) {
+	ctx, span := otel.Tracer("require-span-test").Start(context.Background(), "request")
+	defer span.End()
+	if got, err := CheckSpan(ctx); err != nil || !got.SpanContext().Equal(span.SpanContext()) {
+		t.Errorf("有效 span: CheckSpan = (%v, %v)", got.SpanContext(), err)
+	}
+
+	// 未被采样的 span 同样有效
+	unsampled := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.NeverSample()))
+	ctx, span = unsampled.Tracer("require-span-test").Start(context.Background(), "request")
+	defer span.End()
+	if span.SpanContext().IsSampled() {
+		t.Fatal("NeverSample 的 span 不应被采样")
+	}
+	if _, err := CheckSpan(ctx); err != nil {
+		t.Errorf("未采样 span: CheckSpan = %v, want nil", err)
+	}
+
+	if _, err := CheckSpan(context.Background()); !errors.Is(err, ErrNoSpan) {
+		t.Errorf("没有 span: CheckSpan = %v, want ErrNoSpan", err)
+	}
+}
+
+func TestRequireSpanStrictModePanics(t *testing.T) {
+	useRequireSpan(t, NewRatioSampler(1), true)
+
+	if got := requireSpanPanic(context.Background()); got != ErrNoSpan {
+		t.Errorf("严格模式下缺少 span: panic = %v, want ErrNoSpan", got)
+	}
+
+	ctx, span := otel.Tracer("require-span-test").Start(context.Background(), "request")
+	defer span.End()
+	if got := requireSpanPanic(ctx); got != nil {
+		t.Errorf("有效 span 不应 panic，got %v", got)
+	}
+}
+
+func TestRequireSpanWarnsOutsideStrictMode(t *testing.T) {
+	useRequireSpan(t, NewRatioSampler(1), false)
+	var logs bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	if got := requireSpanPanic(context.Background()); got != nil {
+		t.Fatalf("非严格模式不应 panic，got %v", got)
+	}
+	if !strings.Contains(logs.String(), "追踪链路缺失") {
+		t.Errorf("缺少 span 时应输出 WARN 日志，got %q", logs.String())
+	}
+}
+
+func TestRequireSpanSkipsWhenTracingDisabled(t *testing.T) {
+	useRequireSpan(t, nil, true)
+
+	if got := requireSpanPanic(context.Background()); got != nil {
+		t.Errorf("追踪未启用时不应检查 span，got panic %v", got)
+	}
+}
@@ -0,0 +1,73 @@
+package pkg
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/imroc/req/v3"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 重试退避默认值（httpClient.retryMinBackoff / retryMaxBackoff 未配置时使用）
+const (
+	DefaultRetryMinBackoff = 100 * time.Millisecond
+	DefaultRetryMaxBackoff = 2 * time.Second
+)
+
+// retrySafeKey 请求可安全重试标记在 context 中的键
+type retrySafeKey struct{}
+
+// idempotentMethods 默认允许重试的幂等方法
+var idempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+}
+
+// WithRetrySafe 标记请求可安全重试
+// POST 等非幂等方法默认不重试，只有确认下游接口无副作用（如纯计算）时才应使用此标记：
+//
+//	resp, err := pkg.HTTPClient().R().
+//		SetContext(pkg.WithRetrySafe(ctx)).
+//		Post(url)
+func WithRetrySafe(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retrySafeKey{}, true)
+}
+
+// isRetrySafe 请求是否已标记为可安全重试
+func isRetrySafe(ctx context.Context) bool {
+	safe, _ := ctx.Value(retrySafeKey{}).(bool)
+	return safe
+}
+
+// RetryCondition 按请求方法区分的共享重试条件（全局客户端和请求级重试共用）
+// 仅对临时性失败（网络错误、5xx、429）重试；幂等方法默认重试，其他方法需通过 WithRetrySafe 显式开启；
+// 全局重试预算（InitRetryBudget）耗尽时放弃重试，并在请求 span 上记录 http.retry_shed 事件。
+// 请求级 SetRetryCount 不会继承未配置重试的客户端的条件（req 默认对任意错误重试），必须同时设置该条件
+// （退避区间沿用客户端配置）：
+//
+//	request.SetRetryCount(n).
+//		SetRetryCondition(pkg.RetryCondition)
+func RetryCondition(resp *req.Response, err error) bool {
+	if resp == nil || resp.Request == nil {
+		return false
+	}
+
+	transient := err != nil || resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+	if !transient {
+		return false
+	}
+
+	if !idempotentMethods[resp.Request.Method] && !isRetrySafe(resp.Request.Context()) {
+		return false
+	}
+
+	if !allowRetry() {
+		trace.SpanFromContext(resp.Request.Context()).AddEvent("http.retry_shed")
+		return false
+	}
+	return true
+}
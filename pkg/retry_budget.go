@@ -0,0 +1,119 @@
+package pkg
+
+import (
+	"sync"
+	"time"
+
+	"gin-project/pkg/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RetryBudget 全局重试预算（令牌桶）
+// 每次重试消耗一个令牌，令牌按 rate 每秒补充，最多累积 burst 个；预算耗尽时放弃重试，
+// 避免故障期间各请求的重试叠加放大下游压力（重试风暴）
+type RetryBudget struct {
+	mu     sync.Mutex
+	rate   float64 // 每秒补充的令牌数
+	burst  float64 // 令牌上限
+	tokens float64 // 当前令牌数
+	last   time.Time
+	now    func() time.Time
+}
+
+// NewRetryBudget 创建重试预算，初始令牌数为 burst
+func NewRetryBudget(rate float64, burst int) *RetryBudget {
+	if burst <= 0 {
+		burst = 1
+	}
+	return &RetryBudget{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+		now:    time.Now,
+	}
+}
+
+// Allow 尝试消耗一个重试令牌，预算耗尽时返回 false
+func (b *RetryBudget) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Tokens 当前剩余的重试令牌数
+func (b *RetryBudget) Tokens() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	return b.tokens
+}
+
+// refill 按距上次补充的时间补充令牌（调用方需持有锁）
+func (b *RetryBudget) refill() {
+	now := b.now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+}
+
+// 重试预算判定结果
+const (
+	retryAllowed = "allowed" // 消耗令牌后重试
+	retryShed    = "shed"    // 预算耗尽，放弃重试
+)
+
+var (
+	// retryBudget 全局重试预算（通过 InitRetryBudget 设置），为 nil 时不限制
+	retryBudget *RetryBudget
+
+	// retryAttempts 出站 HTTP 重试次数（按预算判定结果）
+	retryAttempts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "http_client",
+		Name:      "retries_total",
+		Help:      "出站 HTTP 重试次数（allowed 为已重试，shed 为重试预算耗尽而放弃）",
+	}, []string{"result"})
+)
+
+// InitRetryBudget 初始化全局重试预算，所有 HTTP 客户端（含各服务的专用客户端）共享同一预算
+// rate 为每秒补充的重试次数，burst 为最多累积的重试次数；rate <= 0 时不限制重试总量
+// 必须在 metrics.Init 之后调用
+func InitRetryBudget(rate float64, burst int) {
+	if rate <= 0 {
+		retryBudget = nil
+		return
+	}
+	budget := NewRetryBudget(rate, burst)
+	retryBudget = budget
+
+	metrics.Register(retryAttempts, prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "http_client",
+		Name:      "retry_budget_tokens",
+		Help:      "出站 HTTP 重试预算剩余令牌数",
+	}, budget.Tokens))
+}
+
+// allowRetry 按全局重试预算判断是否允许本次重试，并记录指标
+func allowRetry() bool {
+	if retryBudget == nil {
+		return true
+	}
+	if !retryBudget.Allow() {
+		retryAttempts.WithLabelValues(retryShed).Inc()
+		return false
+	}
+	retryAttempts.WithLabelValues(retryAllowed).Inc()
+	return true
+}
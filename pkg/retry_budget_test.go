@@ -0,0 +1,86 @@
+package pkg
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// useRetryBudget 将全局重试预算替换为 budget，测试结束时恢复
+func useRetryBudget(t *testing.T, budget *RetryBudget) {
+	t.Helper()
+	previous := retryBudget
+	retryBudget = budget
+	t.Cleanup(func() { retryBudget = previous })
+}
+
+func TestRetryBudgetExhaustionSuppressesRetries(t *testing.T) {
+	// 补充速率极低：测试期间只有初始的 1 个令牌可用（少于客户端配置的 2 次重试）
+	useRetryBudget(t, NewRetryBudget(0.001, 1))
+	server, hits := failingServer(t)
+	client := newHTTPClient(false, time.Second, []HTTPClientOption{fastRetry()})
+	shedBefore := testutil.ToFloat64(retryAttempts.WithLabelValues(retryShed))
+	allowedBefore := testutil.ToFloat64(retryAttempts.WithLabelValues(retryAllowed))
+
+	if _, err := client.R().SetContext(context.Background()).Get(server.URL); err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	if got := hits.Load(); got != 2 {
+		t.Errorf("预算为 1 时请求次数 = %d, want 2（首次请求 + 1 次重试）", got)
+	}
+
+	hits.Store(0)
+	resp, err := client.R().SetContext(context.Background()).Get(server.URL)
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", resp.StatusCode)
+	}
+	if got := hits.Load(); got != 1 {
+		t.Errorf("预算耗尽后请求次数 = %d, want 1（不再重试）", got)
+	}
+
+	if got := testutil.ToFloat64(retryAttempts.WithLabelValues(retryAllowed)) - allowedBefore; got != 1 {
+		t.Errorf("allowed 重试次数 = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(retryAttempts.WithLabelValues(retryShed)) - shedBefore; got != 2 {
+		t.Errorf("shed 重试次数 = %v, want 2（每个请求放弃一次后停止重试）", got)
+	}
+}
+
+func TestRetryBudgetRefills(t *testing.T) {
+	now := time.Unix(0, 0)
+	budget := NewRetryBudget(2, 3)
+	budget.last, budget.now = now, func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if !budget.Allow() {
+			t.Fatalf("第 %d 次重试应使用初始令牌", i+1)
+		}
+	}
+	if budget.Allow() {
+		t.Fatal("令牌耗尽后应放弃重试")
+	}
+
+	now = now.Add(time.Second)
+	if got := budget.Tokens(); got != 2 {
+		t.Errorf("1s 后令牌数 = %v, want 2", got)
+	}
+	now = now.Add(time.Minute)
+	if got := budget.Tokens(); got != 3 {
+		t.Errorf("令牌数 = %v, want 不超过上限 3", got)
+	}
+}
+
+func TestRetryBudgetDisabledAllowsRetries(t *testing.T) {
+	useRetryBudget(t, nil)
+	for i := 0; i < 100; i++ {
+		if !allowRetry() {
+			t.Fatal("未配置重试预算时不应限制重试")
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package pkg
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/imroc/req/v3"
+)
+
+// failingServer 始终返回 503 并统计请求次数的 HTTP 服务
+func failingServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+// fastRetry 重试 2 次、退避极短的客户端选项
+func fastRetry() HTTPClientOption {
+	return WithRetry(2, time.Millisecond, 2*time.Millisecond)
+}
+
+func TestRetryIsMethodAware(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		safe     bool
+		wantHits int32
+	}{
+		{"GET 默认重试", http.MethodGet, false, 3},
+		{"PUT 默认重试", http.MethodPut, false, 3},
+		{"POST 默认不重试", http.MethodPost, false, 1},
+		{"POST 显式标记后重试", http.MethodPost, true, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, hits := failingServer(t)
+			client := newHTTPClient(false, time.Second, []HTTPClientOption{fastRetry()})
+
+			ctx := context.Background()
+			if tt.safe {
+				ctx = WithRetrySafe(ctx)
+			}
+			resp, err := client.R().SetContext(ctx).Send(tt.method, server.URL)
+			if err != nil {
+				t.Fatalf("请求失败: %v", err)
+			}
+			if resp.StatusCode != http.StatusServiceUnavailable {
+				t.Fatalf("status = %d, want 503", resp.StatusCode)
+			}
+			if got := hits.Load(); got != tt.wantHits {
+				t.Errorf("请求次数 = %d, want %d", got, tt.wantHits)
+			}
+		})
+	}
+}
+
+func TestRetryBackoffIsPerClient(t *testing.T) {
+	server, hits := failingServer(t)
+	// 后创建的客户端使用不同的退避区间，不影响先创建的客户端
+	fast := newHTTPClient(false, time.Second, []HTTPClientOption{WithRetry(0, time.Millisecond, 2*time.Millisecond)})
+	slow := newHTTPClient(false, time.Second, []HTTPClientOption{WithRetry(0, 200*time.Millisecond, 200*time.Millisecond)})
+
+	// 请求级重试沿用客户端的退避区间
+	retry := func(client *req.Client, count int) time.Duration {
+		start := time.Now()
+		if _, err := client.R().SetRetryCount(count).SetRetryCondition(RetryCondition).Get(server.URL); err != nil {
+			t.Fatalf("请求失败: %v", err)
+		}
+		return time.Since(start)
+	}
+
+	if elapsed := retry(fast, 2); elapsed >= 100*time.Millisecond {
+		t.Errorf("退避 1-2ms 的客户端重试 2 次耗时 %v", elapsed)
+	}
+	if got := hits.Load(); got != 3 {
+		t.Errorf("请求次数 = %d, want 3", got)
+	}
+	// 退避带随机抖动，单次等待不少于区间的一半
+	if elapsed := retry(slow, 1); elapsed < 100*time.Millisecond {
+		t.Errorf("退避 200ms 的客户端重试 1 次耗时 %v，应不少于 100ms", elapsed)
+	}
+}
@@ -0,0 +1,41 @@
+package pkg
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// activeSpans 当前未结束的 span 数（仅统计通过 StartSpan 创建的 span）
+var activeSpans atomic.Int64
+
+// ActiveSpans 获取当前未结束的 span 数
+// 压测期间持续增长说明存在未调用 End() 的 span 泄漏
+func ActiveSpans() int64 {
+	return activeSpans.Load()
+}
+
+// StartSpan 创建 span 并计入活跃 span 数，span.End() 时扣减（重复调用 End 只扣减一次）
+// HTTP 中间件和 TraceServiceFunc 通过该函数创建 span
+func StartSpan(ctx context.Context, tracer trace.Tracer, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	ctx, span := tracer.Start(ctx, name, opts...)
+	activeSpans.Add(1)
+
+	counted := &countedSpan{Span: span}
+	return trace.ContextWithSpan(ctx, counted), counted
+}
+
+// countedSpan 结束时扣减活跃 span 数的 span 包装
+type countedSpan struct {
+	trace.Span
+	ended atomic.Bool
+}
+
+// End 结束 span 并扣减活跃 span 数
+func (s *countedSpan) End(opts ...trace.SpanEndOption) {
+	if s.ended.CompareAndSwap(false, true) {
+		activeSpans.Add(-1)
+	}
+	s.Span.End(opts...)
+}
@@ -0,0 +1,56 @@
+package pkg
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestActiveSpansReturnToZero(t *testing.T) {
+	recordedSpans(t)
+	base := ActiveSpans()
+
+	const n = 20
+	var wg sync.WaitGroup
+	spans := make(chan trace.Span, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, span := StartSpan(context.Background(), Tracer, "request")
+			// 子函数通过 context 取到的 span 结束时同样扣减计数
+			if trace.SpanFromContext(ctx) != span {
+				t.Error("context 中的 span 应为计数包装")
+			}
+			spans <- span
+		}()
+	}
+	wg.Wait()
+	close(spans)
+
+	if got := ActiveSpans() - base; got != n {
+		t.Fatalf("创建 %d 个 span 后活跃数增加 %d", n, got)
+	}
+	for span := range spans {
+		span.End()
+		span.End() // 重复 End 只扣减一次
+	}
+	if got := ActiveSpans(); got != base {
+		t.Errorf("全部结束后 ActiveSpans = %d, want %d", got, base)
+	}
+}
+
+func TestTraceServiceFuncEndsSpan(t *testing.T) {
+	recordedSpans(t)
+	base := ActiveSpans()
+
+	traced := TraceServiceFunc("op", func(_ context.Context, id int) (int, error) { return id, nil }, nil)
+	if _, err := traced(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+	if got := ActiveSpans(); got != base {
+		t.Errorf("TraceServiceFunc 返回后 ActiveSpans = %d, want %d", got, base)
+	}
+}
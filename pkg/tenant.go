@@ -0,0 +1,18 @@
+package pkg
+
+import "context"
+
+// tenantKey 租户ID在 context 中的键
+type tenantKey struct{}
+
+// WithTenant 将租户ID存入 context，由租户中间件调用
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantFromContext 获取当前请求的租户ID，未设置时 ok 为 false
+// 供逻辑层按租户过滤数据（行级隔离）
+func TenantFromContext(ctx context.Context) (tenantID string, ok bool) {
+	tenantID, _ = ctx.Value(tenantKey{}).(string)
+	return tenantID, tenantID != ""
+}
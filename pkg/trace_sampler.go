@@ -0,0 +1,106 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// ErrTracingDisabled 追踪未启用（tracing.enabled 为 false），无法调整采样率
+var ErrTracingDisabled = errors.New("追踪未启用")
+
+// RatioSampler 可在运行时调整采样率的按比例采样器
+// 采样率为 1 时等价于 AlwaysSample，否则使用 TraceIDRatioBased；调整后对新创建的 span 立即生效
+type RatioSampler struct {
+	mu      sync.RWMutex
+	rate    float64
+	sampler sdktrace.Sampler
+}
+
+// NewRatioSampler 创建按比例采样器，rate 取值 0.0-1.0
+func NewRatioSampler(rate float64) *RatioSampler {
+	s := &RatioSampler{}
+	s.SetRate(rate)
+	return s
+}
+
+// SetRate 调整采样率，超出 0.0-1.0 时截断到边界
+func (s *RatioSampler) SetRate(rate float64) {
+	if rate < 0 {
+		rate = 0
+	} else if rate > 1 {
+		rate = 1
+	}
+
+	sampler := sdktrace.AlwaysSample()
+	if rate < 1 {
+		sampler = sdktrace.TraceIDRatioBased(rate)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rate = rate
+	s.sampler = sampler
+}
+
+// Rate 获取当前采样率
+func (s *RatioSampler) Rate() float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.rate
+}
+
+// ShouldSample 使用当前采样率决策
+func (s *RatioSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	s.mu.RLock()
+	sampler := s.sampler
+	s.mu.RUnlock()
+	return sampler.ShouldSample(p)
+}
+
+// Description 采样器描述
+func (s *RatioSampler) Description() string {
+	return "RatioSampler{" + strconv.FormatFloat(s.Rate(), 'f', -1, 64) + "}"
+}
+
+// traceSampler 全局追踪采样器（追踪启用时由 SetTraceSampler 设置）
+var traceSampler struct {
+	sync.RWMutex
+	sampler *RatioSampler
+}
+
+// SetTraceSampler 设置全局追踪采样器，供管理接口在运行时调整采样率
+func SetTraceSampler(sampler *RatioSampler) {
+	traceSampler.Lock()
+	defer traceSampler.Unlock()
+	traceSampler.sampler = sampler
+}
+
+// TraceSampleRate 获取当前生效的追踪采样率，追踪未启用时返回 ErrTracingDisabled
+func TraceSampleRate() (float64, error) {
+	traceSampler.RLock()
+	defer traceSampler.RUnlock()
+	if traceSampler.sampler == nil {
+		return 0, ErrTracingDisabled
+	}
+	return traceSampler.sampler.Rate(), nil
+}
+
+// SetTraceSampleRate 在运行时调整追踪采样率（无需重启，对新请求立即生效），rate 取值 0.0-1.0
+// 追踪未启用时返回 ErrTracingDisabled
+func SetTraceSampleRate(rate float64) error {
+	if rate < 0 || rate > 1 {
+		return fmt.Errorf("采样率 %v 超出范围 0.0-1.0", rate)
+	}
+
+	traceSampler.RLock()
+	defer traceSampler.RUnlock()
+	if traceSampler.sampler == nil {
+		return ErrTracingDisabled
+	}
+	traceSampler.sampler.SetRate(rate)
+	return nil
+}
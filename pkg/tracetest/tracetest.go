@@ -0,0 +1,213 @@
+// Package tracetest 链路验证工具：使用内存 span 记录器替换全局 TracerProvider，
+// 断言一次请求产生了预期的 span 树（HTTP 服务端 span -> 服务层 span -> DB / HTTP 客户端 span）
+//
+// 使用示例（验证 POST /api/user/query 的链路）:
+//
+//	rec := tracetest.Install()
+//	defer rec.Close()
+//	// 需开启 tracing.enabled；DB/Redis 插件和 HTTP 客户端在初始化时读取全局 TracerProvider，必须在 Install 之后初始化
+//	database.InitMysql(config.Cfg)
+//	pkg.InitHTTPClient(true)
+//	r := router.SetupRouter()
+//
+//	req := httptest.NewRequest(http.MethodPost, "/api/user/query", strings.NewReader(`{"id":1}`))
+//	req.Header.Set("Content-Type", "application/json")
+//	r.ServeHTTP(httptest.NewRecorder(), req)
+//
+//	rec.AssertChain(t, tracetest.Kind(trace.SpanKindServer), tracetest.NamePrefix("gorm."))
+//	rec.AssertChain(t,
+//		tracetest.Kind(trace.SpanKindServer),
+//		tracetest.Name("ServiceC.Calculate"),
+//		tracetest.Kind(trace.SpanKindClient),
+//	)
+package tracetest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"gin-project/middleware"
+	"gin-project/pkg"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	sdktracetest "go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName 记录器安装到 HTTP 追踪中间件时使用的追踪器名称
+const tracerName = "tracetest"
+
+// Recorder 内存 span 记录器，记录安装后结束的所有 span（始终采样）
+type Recorder struct {
+	spans    *sdktracetest.SpanRecorder
+	provider *sdktrace.TracerProvider
+	previous trace.TracerProvider
+}
+
+// Install 创建记录器并设置为全局 TracerProvider（同时用于 HTTP 追踪中间件），Close 时恢复原来的全局 TracerProvider
+// 已创建追踪器的组件（如已执行 InitMysql 的 otelgorm）不受影响，应在初始化这些组件之前调用
+func Install() *Recorder {
+	r := &Recorder{
+		spans:    sdktracetest.NewSpanRecorder(),
+		previous: otel.GetTracerProvider(),
+	}
+	r.provider = sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithSpanProcessor(r.spans),
+	)
+
+	otel.SetTracerProvider(r.provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	middleware.UseTracerProvider(r.provider, tracerName)
+	return r
+}
+
+// Close 关闭记录器并恢复原来的全局 TracerProvider
+func (r *Recorder) Close() {
+	_ = r.provider.Shutdown(context.Background())
+	otel.SetTracerProvider(r.previous)
+}
+
+// Spans 返回已结束的 span（按结束顺序）
+func (r *Recorder) Spans() []sdktrace.ReadOnlySpan {
+	return r.spans.Ended()
+}
+
+// Reset 清空已记录的 span（同一个记录器验证多个请求时使用）
+func (r *Recorder) Reset() {
+	r.spans.Reset()
+}
+
+// Node span 树节点
+type Node struct {
+	Span     sdktrace.ReadOnlySpan
+	Children []*Node
+}
+
+// Tree 按父子关系构建 span 树，返回根节点（父 span 未被记录的 span 同样视为根节点）
+func (r *Recorder) Tree() []*Node {
+	spans := r.Spans()
+	nodes := make(map[trace.SpanID]*Node, len(spans))
+	for _, span := range spans {
+		nodes[span.SpanContext().SpanID()] = &Node{Span: span}
+	}
+
+	var roots []*Node
+	for _, span := range spans {
+		node := nodes[span.SpanContext().SpanID()]
+		if parent, ok := nodes[span.Parent().SpanID()]; ok && span.Parent().IsValid() {
+			parent.Children = append(parent.Children, node)
+			continue
+		}
+		roots = append(roots, node)
+	}
+	return roots
+}
+
+// String 以缩进文本输出 span 树（名称和类型），用于断言失败时排查
+func (r *Recorder) String() string {
+	var b strings.Builder
+	var walk func(nodes []*Node, depth int)
+	walk = func(nodes []*Node, depth int) {
+		for _, node := range nodes {
+			fmt.Fprintf(&b, "%s%s (%s)\n", strings.Repeat("  ", depth), node.Span.Name(), node.Span.SpanKind())
+			walk(node.Children, depth+1)
+		}
+	}
+	walk(r.Tree(), 0)
+	return b.String()
+}
+
+// Matcher span 匹配条件
+type Matcher struct {
+	desc  string
+	match func(sdktrace.ReadOnlySpan) bool
+}
+
+// Name 匹配 span 名称
+func Name(name string) Matcher {
+	return Matcher{
+		desc:  fmt.Sprintf("name=%q", name),
+		match: func(span sdktrace.ReadOnlySpan) bool { return span.Name() == name },
+	}
+}
+
+// NamePrefix 匹配 span 名称前缀（如 otelgorm 的 "gorm."）
+func NamePrefix(prefix string) Matcher {
+	return Matcher{
+		desc:  fmt.Sprintf("name^=%q", prefix),
+		match: func(span sdktrace.ReadOnlySpan) bool { return strings.HasPrefix(span.Name(), prefix) },
+	}
+}
+
+// Kind 匹配 span 类型（如 trace.SpanKindServer、trace.SpanKindClient）
+func Kind(kind trace.SpanKind) Matcher {
+	return Matcher{
+		desc:  "kind=" + kind.String(),
+		match: func(span sdktrace.ReadOnlySpan) bool { return span.SpanKind() == kind },
+	}
+}
+
+// FindChain 查找依次满足 matchers 的 span 链：每个 span 都是前一个 span 的后代（允许中间隔着其他 span）
+// 找到时返回链上的 span，否则返回 false
+func (r *Recorder) FindChain(matchers ...Matcher) ([]sdktrace.ReadOnlySpan, bool) {
+	if len(matchers) == 0 {
+		return nil, true
+	}
+	for _, root := range r.Tree() {
+		if chain, ok := findChain(root, matchers); ok {
+			return chain, true
+		}
+	}
+	return nil, false
+}
+
+// findChain 在以 node 为根的子树中查找 span 链
+func findChain(node *Node, matchers []Matcher) ([]sdktrace.ReadOnlySpan, bool) {
+	if matchers[0].match(node.Span) {
+		if len(matchers) == 1 {
+			return []sdktrace.ReadOnlySpan{node.Span}, true
+		}
+		for _, child := range node.Children {
+			if rest, ok := findChain(child, matchers[1:]); ok {
+				return append([]sdktrace.ReadOnlySpan{node.Span}, rest...), true
+			}
+		}
+	}
+	// 当前节点不匹配或后代无法满足剩余条件时，继续在子树中查找链的起点
+	for _, child := range node.Children {
+		if chain, ok := findChain(child, matchers); ok {
+			return chain, true
+		}
+	}
+	return nil, false
+}
+
+// AssertChain 断言记录的 span 中存在依次满足 matchers 的祖先-后代链，不满足时输出期望的链和实际的 span 树
+func (r *Recorder) AssertChain(t testing.TB, matchers ...Matcher) {
+	t.Helper()
+	if _, ok := r.FindChain(matchers...); ok {
+		return
+	}
+
+	descs := make([]string, len(matchers))
+	for i, m := range matchers {
+		descs[i] = m.desc
+	}
+	t.Errorf("未找到期望的 span 链: %s\n实际 span 树:\n%s", strings.Join(descs, " -> "), r.String())
+}
+
+// RequireSpan 断言 context 中有有效的 span 并返回，无效时立即终止测试（测试中 pkg.RequireSpan 的对应版本）
+// 与 pkg.RequireSpan 不同，不依赖 tracing.enabled 和严格模式配置
+func RequireSpan(t testing.TB, ctx context.Context) trace.Span {
+	t.Helper()
+	span, err := pkg.CheckSpan(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return span
+}
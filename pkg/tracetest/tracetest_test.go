@@ -0,0 +1,126 @@
+package tracetest_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"gin-project/config"
+	"gin-project/database/dbtest"
+	"gin-project/model"
+	"gin-project/pkg"
+	"gin-project/pkg/tracetest"
+	"gin-project/router"
+
+	"github.com/gin-gonic/gin"
+	"github.com/uptrace/opentelemetry-go-extra/otelgorm"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// rec 包内测试共用的记录器
+// 包级追踪器（如 pkg.Tracer）只会绑定到第一次设置的全局 TracerProvider，因此整个测试进程只安装一次，用例之间通过 Reset 清空
+var rec *tracetest.Recorder
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	gin.DefaultWriter = io.Discard
+	rec = tracetest.Install()
+	code := m.Run()
+	rec.Close()
+	os.Exit(code)
+}
+
+// stubServiceC 启动返回固定结果的服务C
+func stubServiceC(t *testing.T) string {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/calculate", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"code":0,"message":"ok","data":{"number":5,"result":25}}`))
+	})
+	mux.HandleFunc("/api/process", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"code":0,"message":"ok","data":{"content":"hello world","result":"HELLO WORLD"}}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+// TestGetUserByIDSpanTree POST /api/user/query 的参考链路：
+// HTTP 服务端 span -> DB span，以及 HTTP 服务端 span -> 服务层 span -> HTTP 客户端 span
+func TestGetUserByIDSpanTree(t *testing.T) {
+	previous := config.Cfg
+	config.Cfg = &config.Config{
+		Tracing:  config.Tracing{Enabled: true},
+		Services: map[string]config.Service{"serviceC": {BaseURL: stubServiceC(t)}},
+	}
+	t.Cleanup(func() { config.Cfg = previous })
+
+	// DB 插件和 HTTP 客户端在创建时读取全局 TracerProvider，必须在 Install 之后初始化
+	db := dbtest.Open(t, &model.User{})
+	dbtest.Redis(t)
+	if err := db.Use(otelgorm.NewPlugin()); err != nil {
+		t.Fatalf("注册 otelgorm 插件失败: %v", err)
+	}
+	if err := db.Create(&model.User{Name: "alice", Email: "alice@example.com"}).Error; err != nil {
+		t.Fatalf("创建用户失败: %v", err)
+	}
+	pkg.InitHTTPClient(true)
+	t.Cleanup(func() { pkg.InitHTTPClient(false) })
+	r := router.SetupRouter()
+	rec.Reset()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/user/query", strings.NewReader(`{"id":1}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+
+	rec.AssertChain(t, tracetest.Kind(trace.SpanKindServer), tracetest.NamePrefix("gorm."))
+	for _, name := range []string{"ServiceC.Calculate", "ServiceC.Process"} {
+		rec.AssertChain(t,
+			tracetest.Kind(trace.SpanKindServer),
+			tracetest.Name(name),
+			tracetest.Kind(trace.SpanKindClient),
+		)
+	}
+}
+
+func TestFindChainAllowsIntermediateSpans(t *testing.T) {
+	rec.Reset()
+	tracer := otel.Tracer("tracetest-test")
+	ctx, server := tracer.Start(context.Background(), "server", trace.WithSpanKind(trace.SpanKindServer))
+	ctx, service := tracer.Start(ctx, "service")
+	_, query := tracer.Start(ctx, "gorm.Query", trace.WithSpanKind(trace.SpanKindClient))
+	query.End()
+	service.End()
+	server.End()
+
+	chain, ok := rec.FindChain(tracetest.Kind(trace.SpanKindServer), tracetest.NamePrefix("gorm."))
+	if !ok || len(chain) != 2 || chain[0].Name() != "server" || chain[1].Name() != "gorm.Query" {
+		t.Errorf("FindChain = (%v, %v), want server -> gorm.Query（中间隔着 service）", chain, ok)
+	}
+	if _, ok := rec.FindChain(tracetest.Name("gorm.Query"), tracetest.Name("service")); ok {
+		t.Error("祖先-后代顺序颠倒时不应匹配")
+	}
+	if got := rec.String(); got != "server (server)\n  service (internal)\n    gorm.Query (client)\n" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestRequireSpan(t *testing.T) {
+	ctx, span := otel.Tracer("tracetest-test").Start(context.Background(), "request")
+	defer span.End()
+
+	if got := tracetest.RequireSpan(t, ctx); !got.SpanContext().Equal(span.SpanContext()) {
+		t.Errorf("RequireSpan 返回的 span = %v, want %v", got.SpanContext(), span.SpanContext())
+	}
+}
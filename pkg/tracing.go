@@ -43,7 +43,7 @@ func TraceServiceFunc[T any, R any](
 	attrFunc func(context.Context, T) []attribute.KeyValue,
 ) func(context.Context, T) (R, error) {
 	return func(ctx context.Context, arg T) (R, error) {
-		ctx, span := Tracer.Start(ctx, operationName)
+		ctx, span := StartSpan(ctx, Tracer, operationName)
 		defer span.End()
 
 		// 设置属性（可选）
@@ -0,0 +1,103 @@
+package pkg
+
+import (
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultWatchdogInterval 心跳默认间隔
+	DefaultWatchdogInterval = time.Second
+	// DefaultWatchdogThreshold 心跳默认超时阈值，超过后存活检查返回不健康
+	DefaultWatchdogThreshold = 10 * time.Second
+)
+
+// livenessWatchdog 全局存活看门狗（health.watchdog.enabled 未开启时为 nil）
+var livenessWatchdog *Watchdog
+
+// Watchdog 存活看门狗
+// 后台 goroutine 定期更新心跳时间，心跳超过阈值未更新说明进程被阻塞（如死锁、调度停滞）
+type Watchdog struct {
+	interval  time.Duration
+	threshold time.Duration
+	lastBeat  atomic.Int64 // 最近一次心跳时间（UnixNano）
+	stop      chan struct{}
+	stopOnce  sync.Once
+}
+
+// NewWatchdog 创建存活看门狗（未启动），参数非法时使用默认值
+func NewWatchdog(interval, threshold time.Duration) *Watchdog {
+	if interval <= 0 {
+		interval = DefaultWatchdogInterval
+	}
+	if threshold <= interval {
+		threshold = DefaultWatchdogThreshold
+		if threshold <= interval {
+			threshold = 10 * interval
+		}
+	}
+	w := &Watchdog{
+		interval:  interval,
+		threshold: threshold,
+		stop:      make(chan struct{}),
+	}
+	w.Beat()
+	return w
+}
+
+// Start 启动心跳 goroutine
+func (w *Watchdog) Start() {
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				w.Beat()
+			case <-w.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop 停止心跳（停止后将逐渐变为不健康）
+func (w *Watchdog) Stop() {
+	w.stopOnce.Do(func() { close(w.stop) })
+}
+
+// Beat 更新心跳时间
+func (w *Watchdog) Beat() {
+	w.lastBeat.Store(time.Now().UnixNano())
+}
+
+// Healthy 心跳是否在阈值内，同时返回距上次心跳的时间
+func (w *Watchdog) Healthy() (bool, time.Duration) {
+	since := time.Since(time.Unix(0, w.lastBeat.Load()))
+	return since <= w.threshold, since
+}
+
+// InitWatchdog 初始化并启动全局存活看门狗，未启用时不创建
+func InitWatchdog(enabled bool, interval, threshold time.Duration) {
+	if !enabled {
+		return
+	}
+	livenessWatchdog = NewWatchdog(interval, threshold)
+	livenessWatchdog.Start()
+	log.Printf("存活看门狗已启用：心跳间隔 %s，超时阈值 %s", livenessWatchdog.interval, livenessWatchdog.threshold)
+}
+
+// UseLivenessWatchdog 替换全局存活看门狗（nil 表示关闭检查），返回原看门狗
+// 供测试注入未启动的看门狗验证存活检查，生产环境通过 InitWatchdog 初始化
+func UseLivenessWatchdog(w *Watchdog) *Watchdog {
+	prev := livenessWatchdog
+	livenessWatchdog = w
+	return prev
+}
+
+// LivenessWatchdog 获取全局存活看门狗，未启用时返回 nil
+func LivenessWatchdog() *Watchdog {
+	return livenessWatchdog
+}
@@ -0,0 +1,36 @@
+package pkg
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWatchdogStopTurnsUnhealthy(t *testing.T) {
+	w := NewWatchdog(5*time.Millisecond, 40*time.Millisecond)
+	w.Start()
+	t.Cleanup(w.Stop)
+
+	// 心跳运行期间超过阈值仍保持健康
+	time.Sleep(80 * time.Millisecond)
+	if healthy, since := w.Healthy(); !healthy {
+		t.Fatalf("心跳运行中应健康，距上次心跳 %s", since)
+	}
+
+	w.Stop()
+	w.Stop() // 重复停止不 panic
+	time.Sleep(80 * time.Millisecond)
+	if healthy, since := w.Healthy(); healthy || since < 40*time.Millisecond {
+		t.Errorf("停止心跳后 Healthy = %v, since = %s", healthy, since)
+	}
+}
+
+func TestNewWatchdogDefaults(t *testing.T) {
+	w := NewWatchdog(0, 0)
+	if w.interval != DefaultWatchdogInterval || w.threshold != DefaultWatchdogThreshold {
+		t.Errorf("默认值 = %s/%s", w.interval, w.threshold)
+	}
+	// 阈值不大于间隔时按间隔放大
+	if w := NewWatchdog(time.Minute, time.Second); w.threshold != 10*time.Minute {
+		t.Errorf("阈值 = %s, want 10m", w.threshold)
+	}
+}
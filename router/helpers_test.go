@@ -0,0 +1,49 @@
+package router
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gin-project/config"
+	"gin-project/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+	// 未启用追踪：追踪中间件直接跳过
+	middleware.InitTracing(&config.Config{})
+}
+
+// useConfig 将全局配置替换为 cfg，测试结束时恢复
+func useConfig(t *testing.T, cfg *config.Config) {
+	t.Helper()
+	previous := config.Cfg
+	config.Cfg = cfg
+	t.Cleanup(func() { config.Cfg = previous })
+}
+
+// captureGinOutput 将 gin 默认输出（访问日志）重定向到缓冲区，测试结束时恢复；必须在创建中间件之前调用
+func captureGinOutput(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous := gin.DefaultWriter
+	gin.DefaultWriter = &buf
+	t.Cleanup(func() { gin.DefaultWriter = previous })
+	return &buf
+}
+
+// serve 使用 engine 处理一次请求并返回响应
+func serve(engine *gin.Engine, method, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(method, target, nil))
+	return w
+}
+
+// pingHandler 返回 200 的测试处理器
+func pingHandler(c *gin.Context) {
+	c.Status(http.StatusOK)
+}
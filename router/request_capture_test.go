@@ -0,0 +1,114 @@
+package router
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gin-project/config"
+	"gin-project/pkg"
+
+	"github.com/gin-gonic/gin"
+)
+
+// captureConfig 开启失败请求记录的调试模式配置
+func captureConfig(size, maxBodyBytes int) *config.Config {
+	return &config.Config{
+		App: config.App{Mode: "debug"},
+		Log: config.Log{RequestCapture: config.RequestCapture{Enabled: true, Size: size, MaxBodyBytes: maxBodyBytes}},
+	}
+}
+
+// failedRequests 通过 GET /debug/requests 读取已记录的失败请求
+func failedRequests(t *testing.T, r *gin.Engine) []pkg.FailedRequest {
+	t.Helper()
+	w := serve(r, http.MethodGet, "/debug/requests")
+	if w.Code != http.StatusOK {
+		t.Fatalf("/debug/requests status = %d, want 200", w.Code)
+	}
+	var resp struct {
+		Data struct {
+			Requests []pkg.FailedRequest `json:"requests"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析 /debug/requests 响应失败: %v", err)
+	}
+	return resp.Data.Requests
+}
+
+func TestFailedRequestReadBackFromDebugEndpoint(t *testing.T) {
+	captureGinOutput(t)
+	useConfig(t, captureConfig(10, 64))
+	r := SetupRouter()
+	r.POST("/fail", func(c *gin.Context) {
+		io.ReadAll(c.Request.Body)
+		c.Status(http.StatusBadRequest)
+	})
+	r.GET("/ok", pingHandler)
+
+	body := `{"password":"hunter2","name":"` + strings.Repeat("x", 100) + `"}`
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/fail?token=abc&page=2", strings.NewReader(body)))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("/fail status = %d, want 400", w.Code)
+	}
+	serve(r, http.MethodGet, "/ok")
+
+	requests := failedRequests(t, r)
+	if len(requests) != 1 {
+		t.Fatalf("记录了 %d 条失败请求，want 1（成功请求不记录）: %+v", len(requests), requests)
+	}
+	got := requests[0]
+	if got.Method != http.MethodPost || got.Path != "/fail" || got.Status != http.StatusBadRequest {
+		t.Errorf("记录 = %s %s %d, want POST /fail 400", got.Method, got.Path, got.Status)
+	}
+	if strings.Contains(got.Query, "abc") || !strings.Contains(got.Query, "page=2") {
+		t.Errorf("Query = %q, 敏感参数应脱敏、其他参数保留", got.Query)
+	}
+	if !got.BodyTruncated || len(got.Body) > 64+len("***") {
+		t.Errorf("请求体应截断到 64 字节: truncated = %v, len = %d", got.BodyTruncated, len(got.Body))
+	}
+	if strings.Contains(got.Body, "hunter2") || !strings.Contains(got.Body, `"password":"***"`) {
+		t.Errorf("请求体密码未脱敏: %s", got.Body)
+	}
+}
+
+func TestFailedRequestCaptureKeepsRecentAndPanics(t *testing.T) {
+	captureGinOutput(t)
+	useConfig(t, captureConfig(2, 0))
+	r := SetupRouter()
+	r.GET("/missing", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+	r.GET("/panic", func(*gin.Context) { panic("boom") })
+
+	serve(r, http.MethodGet, "/missing?n=1")
+	serve(r, http.MethodGet, "/missing?n=2")
+	if w := serve(r, http.MethodGet, "/panic"); w.Code != http.StatusInternalServerError {
+		t.Fatalf("/panic status = %d, want 500", w.Code)
+	}
+
+	requests := failedRequests(t, r)
+	if len(requests) != 2 {
+		t.Fatalf("缓冲区大小为 2，记录了 %d 条", len(requests))
+	}
+	if requests[0].Path != "/panic" || requests[0].Status != http.StatusInternalServerError {
+		t.Errorf("最新记录 = %s %d, want /panic 500", requests[0].Path, requests[0].Status)
+	}
+	if requests[1].Query != "n=2" {
+		t.Errorf("次新记录 Query = %q, want n=2（最早的记录被覆盖）", requests[1].Query)
+	}
+}
+
+func TestFailedRequestCaptureOnlyInDebugMode(t *testing.T) {
+	captureGinOutput(t)
+	cfg := captureConfig(10, 0)
+	cfg.App.Mode = "release"
+	useConfig(t, cfg)
+
+	if w := serve(SetupRouter(), http.MethodGet, "/debug/requests"); w.Code != http.StatusNotFound {
+		t.Errorf("release 模式下 /debug/requests status = %d, want 404", w.Code)
+	}
+}
@@ -1,15 +1,21 @@
 package router
 
 import (
+	"log"
 	"net/http"
 	"net/http/pprof"
 
 	"gin-project/config"
 	"gin-project/controller"
+	_ "gin-project/docs" // Swagger 文档（swag init 生成）
 	"gin-project/middleware"
+	"gin-project/pkg"
+	"gin-project/pkg/metrics"
 	"gin-project/service"
 
 	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
 // SetupRouter 配置路由信息
@@ -18,18 +24,38 @@ func SetupRouter() *gin.Engine {
 	r := gin.New()
 
 	// 添加全局中间件（注意顺序很重要）
-	r.Use(middleware.RecoveryMiddleware()) // 恢复中间件（最先添加，确保能捕获所有 panic）
-	r.Use(middleware.LoggerMiddleware())   // 日志中间件
-	r.Use(middleware.TracingMiddleware())  // 追踪中间件（在日志之后，确保日志能记录追踪信息）
+	r.Use(middleware.RecoveryMiddleware()) // 恢复中间件（始终最先注册，确保能捕获所有 panic）
+
+	// 限制 URL 和查询参数长度（超长请求直接返回 414，不进入后续中间件）
+	r.Use(urlLengthLimit())
+
+	// 调试模式下记录最近失败的请求（处理器 panic 时按 500 记录后交给恢复中间件）
+	if config.Cfg != nil && config.Cfg.App.Mode == "debug" && config.Cfg.Log.RequestCapture.Enabled {
+		capture := config.Cfg.Log.RequestCapture
+		r.Use(middleware.CaptureFailedRequests(pkg.InitRequestCapture(intOrDefault(capture.Size, DefaultRequestCaptureSize)), capture.MaxBodyBytes))
+	}
+
+	if config.Cfg != nil && config.Cfg.App.SecurityHeaders.Enabled {
+		r.Use(middleware.SecurityHeaders()) // 安全响应头（错误响应同样携带）
+	}
+	setupMiddlewares(r)
 
 	// 根据 app.Mode 决定是否开启 pprof（仅在 debug 模式下开启）
 	if config.Cfg != nil && config.Cfg.App.Mode == "debug" {
 		setupPprof(r)
+		setupSwagger(r)
+		setupDebugConfig(r)
+	}
+
+	// Prometheus 指标接口（仅在 metrics.enabled 时开启）
+	if metrics.Enabled() {
+		r.GET("/metrics", gin.WrapH(metrics.Handler()))
 	}
 
 	// 健康检查路由（不需要追踪）
 	healthCtrl := &controller.HealthController{}
 	r.GET("/health", healthCtrl.Health)
+	r.GET("/health/detailed", healthCtrl.Detailed)
 	r.GET("/readiness", healthCtrl.Readiness)
 	r.GET("/liveness", healthCtrl.Liveness)
 
@@ -40,25 +66,160 @@ func SetupRouter() *gin.Engine {
 	// 创建用户控制器（依赖注入服务工厂）
 	userCtrl := controller.NewUserController(serviceFactory)
 
-	// API 路由组
+	// 运维管理路由（需携带 app.adminToken，release 模式下未配置令牌时拒绝访问）
+	adminCtrl := &controller.AdminController{}
+	admin := r.Group("/admin")
+	admin.Use(adminAuth())
+	{
+		admin.GET("/maintenance", adminCtrl.GetMaintenance)
+		admin.PUT("/maintenance", adminCtrl.SetMaintenance)
+	}
+
+	// 运行时调整追踪采样率（release 模式同样注册，便于故障期间临时提高采样率，需携带 app.adminToken，未配置令牌时拒绝访问）
+	tracingAdmin := r.Group("/debug/tracing")
+	tracingAdmin.Use(adminAuth())
+	{
+		tracingAdmin.GET("/sample-rate", adminCtrl.GetSampleRate)
+		tracingAdmin.POST("/sample-rate", adminCtrl.SetSampleRate)
+	}
+
+	// API 路由组（维护模式下拒绝写请求；多租户部署时要求携带租户请求头）
 	api := r.Group("/api")
+	if config.Cfg != nil && config.Cfg.App.RequireTenant {
+		api.Use(middleware.TenantMiddleware())
+	}
+	api.Use(middleware.MaintenanceMode())
 	{
 		// 用户相关接口
 		// 注意：HTTP 请求追踪已由 TracingMiddleware 自动处理，无需装饰器
 		users := api.Group("/user")
+		users.Use(middleware.RequireJSON()) // 写操作要求 JSON 请求体，Content-Type 不符时返回 415
 		{
 			users.POST("/query", userCtrl.GetUserByID)
-			users.POST("/create", userCtrl.CreateUser)
+			users.POST("/create", createBulkhead(), userCtrl.CreateUser)
 			users.PUT("/update", userCtrl.UpdateUser)
+			users.GET("/list", userCtrl.ListUsers)
+			users.GET("/count", userCtrl.CountUsers)
+			users.GET("/exists", userCtrl.UserExists)
+			users.GET("/export", userCtrl.ExportUsers)
+			users.GET("/:id", userCtrl.GetUser)
+			users.PATCH("/:id", userCtrl.PatchUser)
 		}
+
+		// 用户缓存预热（管理接口，需携带 app.adminToken，release 模式下未配置令牌时拒绝访问）
+		api.POST("/user/cache/warm", adminAuth(), userCtrl.WarmUserCache)
 	}
 
 	return r
 }
 
+// defaultMiddlewares 默认启用的全局中间件（追踪在日志之后，确保日志能记录追踪信息；
+// 请求级日志在追踪之后，确保能绑定 trace_id）
+var defaultMiddlewares = []string{"logger", "tracing", "contextLogger", "requestTimeout"}
+
+// middlewareRegistry 可通过配置启用的全局中间件
+var middlewareRegistry = map[string]func() gin.HandlerFunc{
+	"logger":         middleware.LoggerMiddleware,         // 日志中间件
+	"tracing":        middleware.TracingMiddleware,        // 追踪中间件
+	"contextLogger":  middleware.ContextLoggerMiddleware,  // 请求级日志中间件（绑定 trace_id、request_id）
+	"requestTimeout": middleware.RequestTimeoutMiddleware, // 请求超时预算中间件（X-Request-Timeout）
+}
+
+// setupMiddlewares 根据 app.middlewares 配置按顺序注册全局中间件
+// 便于压测时关闭日志等中间件，未配置时使用默认顺序
+func setupMiddlewares(r *gin.Engine) {
+	names := defaultMiddlewares
+	if config.Cfg != nil && config.Cfg.App.Middlewares != nil {
+		names = config.Cfg.App.Middlewares
+	}
+
+	for _, name := range names {
+		newMiddleware, ok := middlewareRegistry[name]
+		if !ok {
+			log.Printf("未知的中间件: %s，已忽略", name)
+			continue
+		}
+		r.Use(newMiddleware())
+	}
+}
+
+// createBulkhead 创建用户接口的并发限制中间件（app.createBulkhead，未配置时不限制）
+func createBulkhead() gin.HandlerFunc {
+	var cfg config.Bulkhead
+	if config.Cfg != nil {
+		cfg = config.Cfg.App.CreateBulkhead
+	}
+	return middleware.ConcurrencyLimit(cfg.MaxConcurrent, middleware.WithConcurrencyWait(cfg.Wait))
+}
+
+// urlLengthLimit 创建 URL 长度限制中间件（app.server.maxURLBytes / maxQueryBytes，未配置时使用默认上限）
+func urlLengthLimit() gin.HandlerFunc {
+	var cfg config.Server
+	if config.Cfg != nil {
+		cfg = config.Cfg.App.Server
+	}
+	return middleware.URLLengthLimit(
+		limitOrDefault(cfg.MaxURLBytes, middleware.DefaultMaxURLBytes),
+		limitOrDefault(cfg.MaxQueryBytes, middleware.DefaultMaxQueryBytes),
+	)
+}
+
+// limitOrDefault 长度上限未配置（0）时使用默认值，负数表示不限制
+func limitOrDefault(value, def int) int {
+	if value == 0 {
+		return def
+	}
+	return value
+}
+
+// adminAuth 管理接口令牌校验（app.adminToken），未配置令牌时仅 debug 模式放行
+func adminAuth() gin.HandlerFunc {
+	return middleware.RequireAdminToken(adminToken())
+}
+
+// adminToken 管理接口令牌（app.adminToken），未加载配置时为空
+func adminToken() string {
+	if config.Cfg == nil {
+		return ""
+	}
+	return config.Cfg.App.AdminToken
+}
+
+// setupSwagger 配置 Swagger 接口文档路由（仅在 debug 模式下启用）
+// 文档由 swag init -g main.go -o docs 根据控制器注释生成
+func setupSwagger(r *gin.Engine) {
+	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+}
+
+// DefaultRequestCaptureSize 未配置 log.requestCapture.size 时保留的失败请求条数
+const DefaultRequestCaptureSize = 50
+
+// intOrDefault 配置值未设置（<=0）时使用默认值
+func intOrDefault(value, def int) int {
+	if value > 0 {
+		return value
+	}
+	return def
+}
+
+// setupDebugConfig 配置调试路由（仅在 debug 模式下启用）：生效配置（敏感字段已脱敏）、活跃 span 数、最近失败的请求
+// 与 pprof 相同，配置 app.adminToken 后需携带 Authorization: Bearer <token> 访问
+func setupDebugConfig(r *gin.Engine) {
+	debugCtrl := &controller.DebugController{}
+	debug := r.Group("/debug")
+	debug.Use(adminAuth())
+	{
+		debug.GET("/config", debugCtrl.Config)
+		debug.GET("/spans", debugCtrl.Spans)
+		debug.GET("/requests", debugCtrl.FailedRequests)
+	}
+}
+
 // setupPprof 配置 pprof 性能分析路由（仅在 debug 模式下启用）
+// 配置 app.adminToken 后需携带 Authorization: Bearer <token> 访问，未配置时不校验
 func setupPprof(r *gin.Engine) {
 	pprofGroup := r.Group("/debug/pprof")
+	pprofGroup.Use(middleware.RequireBearerToken(adminToken()))
 	{
 		pprofGroup.GET("/", gin.WrapH(http.HandlerFunc(pprof.Index)))
 		pprofGroup.GET("/cmdline", gin.WrapH(http.HandlerFunc(pprof.Cmdline)))
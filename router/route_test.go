@@ -0,0 +1,148 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gin-project/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSetupMiddlewaresDefaultLogsRequests(t *testing.T) {
+	useConfig(t, &config.Config{})
+	out := captureGinOutput(t)
+
+	r := gin.New()
+	setupMiddlewares(r)
+	r.GET("/ping", pingHandler)
+	serve(r, http.MethodGet, "/ping")
+
+	if out.Len() == 0 {
+		t.Error("默认中间件应输出访问日志")
+	}
+}
+
+func TestSetupMiddlewaresDisabledLoggerProducesNoOutput(t *testing.T) {
+	useConfig(t, &config.Config{App: config.App{Middlewares: []string{"tracing", "contextLogger"}}})
+	out := captureGinOutput(t)
+
+	r := gin.New()
+	setupMiddlewares(r)
+	r.GET("/ping", pingHandler)
+	if w := serve(r, http.MethodGet, "/ping"); w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+
+	if out.Len() != 0 {
+		t.Errorf("关闭日志中间件后不应输出访问日志，got %q", out.String())
+	}
+}
+
+func TestSetupRouterRecoversWithMiddlewaresDisabled(t *testing.T) {
+	useConfig(t, &config.Config{App: config.App{Middlewares: []string{}}})
+	captureGinOutput(t)
+
+	r := SetupRouter()
+	r.GET("/panic", func(*gin.Context) { panic("boom") })
+
+	if w := serve(r, http.MethodGet, "/panic"); w.Code != http.StatusInternalServerError {
+		t.Errorf("关闭全部可选中间件时恢复中间件仍应生效，status = %d", w.Code)
+	}
+}
+
+func TestSwaggerRouteOnlyInDebugMode(t *testing.T) {
+	captureGinOutput(t)
+
+	useConfig(t, &config.Config{App: config.App{Mode: "debug"}})
+	r := SetupRouter()
+	if w := serve(r, http.MethodGet, "/swagger/index.html"); w.Code != http.StatusOK {
+		t.Errorf("debug 模式下 /swagger/index.html status = %d, want 200", w.Code)
+	}
+	w := serve(r, http.MethodGet, "/swagger/doc.json")
+	if w.Code != http.StatusOK {
+		t.Fatalf("debug 模式下 /swagger/doc.json status = %d, want 200", w.Code)
+	}
+	for _, want := range []string{`"/api/user/query"`, `"controller.APIResponse"`, `"trace_id"`} {
+		if !strings.Contains(w.Body.String(), want) {
+			t.Errorf("Swagger 文档缺少 %s", want)
+		}
+	}
+
+	useConfig(t, &config.Config{App: config.App{Mode: "release"}})
+	if w := serve(SetupRouter(), http.MethodGet, "/swagger/index.html"); w.Code != http.StatusNotFound {
+		t.Errorf("release 模式下 /swagger/index.html status = %d, want 404", w.Code)
+	}
+}
+
+func TestDebugConfigRedactsPasswords(t *testing.T) {
+	captureGinOutput(t)
+
+	useConfig(t, &config.Config{
+		App:      config.App{Mode: "debug"},
+		Database: config.Database{Mysql: config.Mysql{Password: "mysql-secret"}},
+	})
+	w := serve(SetupRouter(), http.MethodGet, "/debug/config")
+	if w.Code != http.StatusOK {
+		t.Fatalf("debug 模式下 /debug/config status = %d, want 200", w.Code)
+	}
+	if strings.Contains(w.Body.String(), "mysql-secret") {
+		t.Errorf("响应包含未脱敏的密码: %s", w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"password":"`+config.RedactedValue+`"`) {
+		t.Errorf("密码字段未脱敏: %s", w.Body.String())
+	}
+
+	useConfig(t, &config.Config{App: config.App{Mode: "release"}})
+	if w := serve(SetupRouter(), http.MethodGet, "/debug/config"); w.Code != http.StatusNotFound {
+		t.Errorf("release 模式下 /debug/config status = %d, want 404", w.Code)
+	}
+}
+
+func TestPprofRequiresAdminToken(t *testing.T) {
+	captureGinOutput(t)
+	useConfig(t, &config.Config{App: config.App{Mode: "debug", AdminToken: "s3cret"}})
+	r := SetupRouter()
+
+	if w := serve(r, http.MethodGet, "/debug/pprof/cmdline"); w.Code != http.StatusUnauthorized {
+		t.Errorf("未携带令牌: status = %d, want 401", w.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/debug/pprof/cmdline", nil)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("携带正确令牌: status = %d, want 200", w.Code)
+	}
+}
+
+func TestSetupRouterLimitsQueryLength(t *testing.T) {
+	captureGinOutput(t)
+	useConfig(t, &config.Config{})
+	r := SetupRouter()
+
+	// 默认上限 4KB，超长查询参数在路由匹配和后续中间件之前被拒绝
+	if w := serve(r, http.MethodGet, "/api/user/list?q="+strings.Repeat("a", 5000)); w.Code != http.StatusRequestURITooLong {
+		t.Errorf("超长查询参数: status = %d, want 414", w.Code)
+	}
+	if w := serve(r, http.MethodGet, "/health?q=short"); w.Code == http.StatusRequestURITooLong {
+		t.Error("未超过上限的请求不应返回 414")
+	}
+}
+
+func TestSetupRouterWithoutConfig(t *testing.T) {
+	captureGinOutput(t)
+	useConfig(t, nil)
+
+	r := SetupRouter()
+	if w := serve(r, http.MethodGet, "/health"); w.Code != http.StatusOK {
+		t.Errorf("未加载配置: /health status = %d, want 200", w.Code)
+	}
+	// 未配置令牌且非 debug 模式，管理接口关闭
+	if w := serve(r, http.MethodGet, "/debug/tracing/sample-rate"); w.Code != http.StatusForbidden {
+		t.Errorf("未加载配置: 管理接口 status = %d, want 403", w.Code)
+	}
+}
@@ -0,0 +1,98 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gin-project/config"
+	"gin-project/pkg"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// useTraceSampler 将全局追踪采样器替换为 sampler，测试结束时清空
+func useTraceSampler(t *testing.T, sampler *pkg.RatioSampler) {
+	t.Helper()
+	pkg.SetTraceSampler(sampler)
+	t.Cleanup(func() { pkg.SetTraceSampler(nil) })
+}
+
+// postSampleRate 携带管理令牌调整采样率
+func postSampleRate(r http.Handler, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/debug/tracing/sample-rate", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// sampledSpans 使用 provider 创建 n 个根 span，返回被采样的个数
+func sampledSpans(provider *sdktrace.TracerProvider, n int) int {
+	sampled := 0
+	for i := 0; i < n; i++ {
+		_, span := provider.Tracer("sample-rate-test").Start(context.Background(), "request")
+		if span.SpanContext().IsSampled() {
+			sampled++
+		}
+		span.End()
+	}
+	return sampled
+}
+
+func TestSampleRateEndpointChangesEffectiveSampler(t *testing.T) {
+	captureGinOutput(t)
+	useConfig(t, &config.Config{App: config.App{Mode: "release", AdminToken: "s3cret"}})
+	sampler := pkg.NewRatioSampler(0)
+	useTraceSampler(t, sampler)
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSampler(sampler))
+	r := SetupRouter()
+
+	if got := sampledSpans(provider, 50); got != 0 {
+		t.Fatalf("采样率为 0 时采样了 %d 个 span", got)
+	}
+
+	w := postSampleRate(r, "s3cret", `{"rate":1}`)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"rate":1`) {
+		t.Fatalf("POST sample-rate = %d %s", w.Code, w.Body.String())
+	}
+	if got := sampledSpans(provider, 50); got != 50 {
+		t.Errorf("调整为 1 后采样了 %d/50 个 span，want 全部采样", got)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/debug/tracing/sample-rate", nil)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"rate":1`) {
+		t.Errorf("GET sample-rate = %d %s, want rate 1", w.Code, w.Body.String())
+	}
+}
+
+func TestSampleRateEndpointRejects(t *testing.T) {
+	captureGinOutput(t)
+	useConfig(t, &config.Config{App: config.App{Mode: "release", AdminToken: "s3cret"}})
+	sampler := pkg.NewRatioSampler(0.1)
+	useTraceSampler(t, sampler)
+	r := SetupRouter()
+
+	if w := postSampleRate(r, "", `{"rate":1}`); w.Code != http.StatusUnauthorized {
+		t.Errorf("未携带令牌: status = %d, want 401", w.Code)
+	}
+	if w := postSampleRate(r, "s3cret", `{"rate":1.5}`); w.Code != http.StatusBadRequest {
+		t.Errorf("超出范围: status = %d, want 400", w.Code)
+	}
+	if got := sampler.Rate(); got != 0.1 {
+		t.Errorf("被拒绝的请求不应修改采样率，rate = %v", got)
+	}
+
+	useTraceSampler(t, nil)
+	if w := postSampleRate(r, "s3cret", `{"rate":1}`); w.Code != http.StatusConflict {
+		t.Errorf("追踪未启用: status = %d, want 409", w.Code)
+	}
+}
@@ -1,22 +1,177 @@
 package service
 
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"gin-project/config"
+	"gin-project/database"
+	"gin-project/pkg/breaker"
+)
+
+// ServiceCName 服务C在工厂中的注册名称
+const ServiceCName = "serviceC"
+
+// Config 服务共享配置，所有服务构造函数共用
+type Config struct {
+	BaseURLs  map[string]string                 // 服务名 -> API 基础URL（未指定 Resolver 时使用）
+	Resolver  Resolver                          // 服务地址解析器，为 nil 时按 BaseURLs 静态解析
+	Retries   map[string]int                    // 服务名 -> 失败重试次数（0 表示使用全局客户端配置）
+	Timeouts  map[string]time.Duration          // 服务名 -> 单次调用超时（0 表示仅使用全局客户端超时）
+	Fallbacks map[string]config.ServiceFallback // 服务名 -> 降级配置
+	Memoizes  map[string]config.ServiceMemoize  // 服务名 -> 结果缓存配置
+}
+
+// Resolve 通过服务地址解析器获取指定服务的基础URL
+func (c Config) Resolve(ctx context.Context, name string) (string, error) {
+	if c.Resolver != nil {
+		return c.Resolver.Resolve(ctx, name)
+	}
+	return StaticResolver(c.BaseURLs).Resolve(ctx, name)
+}
+
+// Retry 获取指定服务的失败重试次数
+func (c Config) Retry(name string) int {
+	return c.Retries[name]
+}
+
+// Timeout 获取指定服务的单次调用超时
+func (c Config) Timeout(name string) time.Duration {
+	return c.Timeouts[name]
+}
+
+// Memoize 获取指定服务的结果缓存配置
+func (c Config) Memoize(name string) config.ServiceMemoize {
+	return c.Memoizes[name]
+}
+
+// Fallback 获取指定服务的降级配置
+func (c Config) Fallback(name string) config.ServiceFallback {
+	return c.Fallbacks[name]
+}
+
+// Constructor 服务构造函数，由工厂在首次获取时调用（懒加载），返回错误时不缓存实例
+type Constructor func(cfg Config) (interface{}, error)
+
 // Factory 服务工厂，统一管理服务的创建和依赖注入
-// 所有服务在这里统一初始化，便于管理和扩展
+// 服务按名称注册，首次获取时才创建，之后复用同一实例
 type Factory struct {
-	serviceC *ServiceCWithTrace
+	cfg       Config
+	mu        sync.Mutex
+	ctors     map[string]Constructor
+	instances map[string]interface{}
 }
 
 // NewFactory 创建服务工厂
-// 统一初始化所有服务，确保依赖注入和追踪配置正确
+// 读取全局配置中的 services（未配置时服务C默认使用 localhost:8081）并注册内置服务
 func NewFactory() *Factory {
-	// 创建服务C（带追踪，默认使用 localhost:8081）
-	serviceC := NewServiceCWithTrace("http://localhost:8081")
-	return &Factory{
-		serviceC: serviceC,
+	cfg := Config{
+		BaseURLs: map[string]string{
+			ServiceCName: "http://localhost:8081",
+		},
+		Retries:   map[string]int{},
+		Timeouts:  map[string]time.Duration{},
+		Fallbacks: map[string]config.ServiceFallback{},
+		Memoizes:  map[string]config.ServiceMemoize{},
+	}
+
+	if config.Cfg != nil {
+		for name, svc := range config.Cfg.Services {
+			if svc.BaseURL != "" {
+				cfg.BaseURLs[name] = svc.BaseURL
+			}
+			cfg.Timeouts[name] = svc.Timeout
+			cfg.Retries[name] = svc.Retries
+			cfg.Fallbacks[name] = svc.Fallback
+			cfg.Memoizes[name] = svc.Memoize
+		}
+	}
+
+	return NewFactoryWithConfig(cfg)
+}
+
+// NewFactoryWithConfig 使用指定的共享配置创建服务工厂，并注册内置服务
+func NewFactoryWithConfig(cfg Config) *Factory {
+	f := &Factory{
+		cfg:       cfg,
+		ctors:     make(map[string]Constructor),
+		instances: make(map[string]interface{}),
+	}
+
+	// 注册服务C（带追踪）
+	f.Register(ServiceCName, func(cfg Config) (interface{}, error) {
+		baseURL, err := cfg.Resolve(context.Background(), ServiceCName)
+		if err != nil {
+			return nil, err
+		}
+		return NewServiceCWithTrace(baseURL,
+			WithTimeout(cfg.Timeout(ServiceCName)),
+			WithRetries(cfg.Retry(ServiceCName)),
+			WithBreaker(breaker.Default().Get(ServiceCName, 0, 0)),
+			WithFallback(cfg.Fallback(ServiceCName)),
+			WithMemoize(database.RedisClient, cfg.Memoize(ServiceCName)),
+		), nil
+	})
+
+	return f
+}
+
+// Register 按名称注册服务构造函数
+// 重复注册会覆盖之前的构造函数，并丢弃已创建的实例
+func (f *Factory) Register(name string, ctor Constructor) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.ctors[name] = ctor
+	delete(f.instances, name)
+}
+
+// Get 按名称获取服务实例（首次获取时创建）
+func (f *Factory) Get(name string) (interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if instance, ok := f.instances[name]; ok {
+		return instance, nil
+	}
+
+	ctor, ok := f.ctors[name]
+	if !ok {
+		return nil, fmt.Errorf("服务 %s 未注册", name)
+	}
+
+	instance, err := ctor(f.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("创建服务 %s 失败: %w", name, err)
+	}
+	f.instances[name] = instance
+	return instance, nil
+}
+
+// GetAs 按名称获取服务实例并转换为指定类型
+//
+// 使用示例:
+//
+//	serviceA, err := service.GetAs[*ServiceA](factory, "serviceA")
+func GetAs[T any](f *Factory, name string) (T, error) {
+	var zero T
+
+	instance, err := f.Get(name)
+	if err != nil {
+		return zero, err
+	}
+
+	typed, ok := instance.(T)
+	if !ok {
+		return zero, fmt.Errorf("服务 %s 的类型为 %T，与期望类型 %T 不匹配", name, instance, zero)
 	}
+	return typed, nil
 }
 
 // GetServiceC 获取服务C实例（带追踪）
-func (f *Factory) GetServiceC() *ServiceCWithTrace {
-	return f.serviceC
+// 服务地址无法解析等导致创建失败时返回错误（不缓存实例，下次获取时重新创建）
+func (f *Factory) GetServiceC() (*ServiceCWithTrace, error) {
+	return GetAs[*ServiceCWithTrace](f, ServiceCName)
 }
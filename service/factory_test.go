@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gin-project/pkg"
+)
+
+func TestFactoryConstructsLazilyAndReuses(t *testing.T) {
+	f := NewFactoryWithConfig(Config{})
+	calls := 0
+	f.Register("counter", func(Config) (interface{}, error) {
+		calls++
+		return &calls, nil
+	})
+	if calls != 0 {
+		t.Fatalf("注册时不应创建实例，calls = %d", calls)
+	}
+
+	first, err := f.Get("counter")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	second, _ := f.Get("counter")
+	if calls != 1 || first != second {
+		t.Errorf("应只创建一次并复用实例，calls = %d", calls)
+	}
+
+	// 重新注册会丢弃已创建的实例
+	f.Register("counter", func(Config) (interface{}, error) { return "replaced", nil })
+	if got, _ := f.Get("counter"); got != "replaced" {
+		t.Errorf("重新注册后 Get = %v, want replaced", got)
+	}
+}
+
+func TestFactoryDoesNotCacheConstructorErrors(t *testing.T) {
+	f := NewFactoryWithConfig(Config{})
+	fail := errors.New("boom")
+	calls := 0
+	f.Register("flaky", func(Config) (interface{}, error) {
+		calls++
+		if calls == 1 {
+			return nil, fail
+		}
+		return "ok", nil
+	})
+
+	if _, err := f.Get("flaky"); !errors.Is(err, fail) {
+		t.Fatalf("首次 Get 应返回构造错误，got %v", err)
+	}
+	if got, err := f.Get("flaky"); err != nil || got != "ok" {
+		t.Errorf("构造失败后应重新创建，got %v, %v", got, err)
+	}
+}
+
+func TestGetAs(t *testing.T) {
+	f := NewFactoryWithConfig(Config{})
+	f.Register("name", func(Config) (interface{}, error) { return "value", nil })
+
+	if got, err := GetAs[string](f, "name"); err != nil || got != "value" {
+		t.Errorf("GetAs[string] = %q, %v", got, err)
+	}
+	if _, err := GetAs[int](f, "name"); err == nil {
+		t.Error("类型不匹配时应返回错误")
+	}
+	if _, err := GetAs[string](f, "missing"); err == nil {
+		t.Error("未注册的服务应返回错误")
+	}
+}
+
+func TestGetServiceC(t *testing.T) {
+	f := NewFactoryWithConfig(Config{})
+	if _, err := f.GetServiceC(); !errors.Is(err, ErrUnknownService) {
+		t.Fatalf("未配置服务C地址时应返回 ErrUnknownService，got %v", err)
+	}
+
+	f = NewFactoryWithConfig(Config{BaseURLs: map[string]string{ServiceCName: "http://127.0.0.1:1"}})
+	first, err := f.GetServiceC()
+	if err != nil {
+		t.Fatalf("GetServiceC: %v", err)
+	}
+	if second, _ := f.GetServiceC(); first != second {
+		t.Error("应复用同一个服务C实例")
+	}
+}
+
+func TestFactoryPerServiceTimeouts(t *testing.T) {
+	const otherName = "serviceD"
+	f := NewFactoryWithConfig(Config{
+		BaseURLs: map[string]string{ServiceCName: "http://127.0.0.1:1", otherName: "http://127.0.0.1:2"},
+		Timeouts: map[string]time.Duration{ServiceCName: 200 * time.Millisecond, otherName: 3 * time.Second},
+	})
+	f.Register(otherName, func(cfg Config) (interface{}, error) {
+		baseURL, err := cfg.Resolve(context.Background(), otherName)
+		if err != nil {
+			return nil, err
+		}
+		return NewServiceC(baseURL, WithTimeout(cfg.Timeout(otherName))), nil
+	})
+
+	serviceC, err := f.GetServiceC()
+	if err != nil {
+		t.Fatalf("GetServiceC: %v", err)
+	}
+	other, err := GetAs[*ServiceC](f, otherName)
+	if err != nil {
+		t.Fatalf("GetAs: %v", err)
+	}
+
+	if got := serviceC.httpClient().GetClient().Timeout; got != 200*time.Millisecond {
+		t.Errorf("服务C客户端超时 = %v, want 200ms", got)
+	}
+	if got := other.httpClient().GetClient().Timeout; got != 3*time.Second {
+		t.Errorf("%s 客户端超时 = %v, want 3s", otherName, got)
+	}
+	if serviceC.httpClient() == other.httpClient() {
+		t.Error("不同超时的服务应使用各自的专用客户端")
+	}
+
+	// 未配置超时时使用全局客户端
+	plain := NewServiceC("http://127.0.0.1:3")
+	if plain.httpClient() != pkg.HTTPClient() {
+		t.Error("未配置超时时应使用全局客户端")
+	}
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gin-project/config"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// cacheEvent 返回 span 上 cache 事件的结果（hit/miss），没有事件时返回空字符串
+func cacheEvent(span sdktrace.ReadOnlySpan) string {
+	for _, event := range span.Events() {
+		if event.Name != "cache" {
+			continue
+		}
+		for _, attr := range event.Attributes {
+			if attr.Key == "cache" {
+				return attr.Value.AsString()
+			}
+		}
+	}
+	return ""
+}
+
+func TestCalculateMemoize(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	var hits atomic.Int32
+	server := stubServiceC(t, func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		respondWith(http.StatusOK, `{"code":0,"message":"ok","data":{"number":3,"result":9}}`)(w, r)
+	})
+	serviceC := NewServiceC(server.URL, WithMemoize(client, config.ServiceMemoize{Enabled: true, TTL: 30 * time.Second}))
+
+	calculate := func(t *testing.T) string {
+		t.Helper()
+		return cacheEvent(recordedCall(t, func(ctx context.Context) {
+			if result, err := serviceC.CalculateTyped(ctx, 3); err != nil || result.Result != 9 {
+				t.Fatalf("CalculateTyped = %+v, %v", result, err)
+			}
+		}))
+	}
+
+	if got := calculate(t); got != "miss" || hits.Load() != 1 {
+		t.Fatalf("首次调用 cache = %q, 下游调用 %d 次", got, hits.Load())
+	}
+	key := fmt.Sprintf(CalculateMemoKey, 3)
+	if ttl := mr.TTL(key); ttl != 30*time.Second {
+		t.Errorf("结果缓存过期时间 = %s, want 30s", ttl)
+	}
+
+	if got := calculate(t); got != "hit" || hits.Load() != 1 {
+		t.Errorf("再次调用 cache = %q, 下游调用 %d 次, want hit 且不调用下游", got, hits.Load())
+	}
+
+	// 过期后重新调用下游
+	mr.FastForward(31 * time.Second)
+	if got := calculate(t); got != "miss" || hits.Load() != 2 {
+		t.Errorf("过期后 cache = %q, 下游调用 %d 次", got, hits.Load())
+	}
+}
+
+func TestCalculateMemoizeDisabled(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	server := stubServiceC(t, respondWith(http.StatusOK, `{"code":0,"message":"ok","data":{"number":3,"result":9}}`))
+	serviceC := NewServiceC(server.URL, WithMemoize(client, config.ServiceMemoize{}))
+
+	span := recordedCall(t, func(ctx context.Context) {
+		if _, err := serviceC.CalculateTyped(ctx, 3); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if got := cacheEvent(span); got != "" {
+		t.Errorf("未启用结果缓存时不应记录 cache 事件，got %q", got)
+	}
+	if len(mr.Keys()) != 0 {
+		t.Errorf("未启用结果缓存时不应写入 Redis，keys = %v", mr.Keys())
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"time"
+
+	"gin-project/pkg/breaker"
+	"gin-project/pkg/metrics"
+
+	"github.com/imroc/req/v3"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// 下游调用结果
+const (
+	outcomeSuccess  = "success"  // 调用成功
+	outcomeFailure  = "failure"  // 调用失败（非 2xx、响应格式错误、业务错误、网络错误）
+	outcomeTimeout  = "timeout"  // 调用超时
+	outcomeRejected = "rejected" // 熔断器打开，未发起调用
+)
+
+var (
+	// serviceCCalls 服务C调用次数（按接口、结果、HTTP 状态码）
+	serviceCCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "service_c",
+		Name:      "calls_total",
+		Help:      "服务C调用次数",
+	}, []string{"method", "outcome", "status"})
+
+	// serviceCDuration 服务C调用耗时（按接口、HTTP 状态码，含重试）
+	serviceCDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "service_c",
+		Name:      "call_duration_seconds",
+		Help:      "服务C调用耗时（秒，含重试）",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "status"})
+)
+
+// registerServiceCMetrics 注册服务C调用指标（仅在指标启用时注册）
+func registerServiceCMetrics() {
+	metrics.Register(serviceCCalls, serviceCDuration)
+}
+
+// observeCall 记录一次服务C调用的结果和耗时
+// resp 为 nil 时（网络错误、熔断拒绝）status 记为 none；熔断拒绝不记录耗时
+func observeCall(method string, start time.Time, resp *req.Response, err error) {
+	status := "none"
+	if resp != nil && resp.Response != nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+
+	outcome := callOutcome(err)
+	serviceCCalls.WithLabelValues(method, outcome, status).Inc()
+	if outcome != outcomeRejected {
+		serviceCDuration.WithLabelValues(method, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// callOutcome 根据错误判断调用结果
+func callOutcome(err error) string {
+	if err == nil {
+		return outcomeSuccess
+	}
+	if errors.Is(err, breaker.ErrOpen) {
+		return outcomeRejected
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return outcomeTimeout
+	}
+	return outcomeFailure
+}
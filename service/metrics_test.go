@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"gin-project/pkg/breaker"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// callCount 获取服务C调用计数
+func callCount(outcome, status string) float64 {
+	return testutil.ToFloat64(serviceCCalls.WithLabelValues("/api/calculate", outcome, status))
+}
+
+func TestServiceCCallMetrics(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc // 为 nil 时处理函数阻塞到用例结束（模拟超时）
+		opts    []ServiceCOption
+		outcome string
+		status  string
+	}{
+		{"成功", respondWith(http.StatusOK, `{"code":0,"message":"ok","data":{"number":3,"result":9}}`), nil, outcomeSuccess, "200"},
+		{"非 2xx", respondWith(http.StatusBadGateway, `bad gateway`), nil, outcomeFailure, "502"},
+		{"业务错误", respondWith(http.StatusOK, `{"code":4001,"message":"参数非法"}`), nil, outcomeFailure, "200"},
+		{"超时", nil, []ServiceCOption{WithTimeout(20 * time.Millisecond)}, outcomeTimeout, "none"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := make(chan struct{})
+			handler := tt.handler
+			if handler == nil {
+				handler = func(http.ResponseWriter, *http.Request) { <-release }
+			}
+			server := stubServiceC(t, handler)
+			// 在关闭模拟服务之前释放阻塞的处理函数（Cleanup 后注册的先执行）
+			t.Cleanup(func() { close(release) })
+			serviceC := NewServiceC(server.URL, tt.opts...)
+
+			before := callCount(tt.outcome, tt.status)
+			_, _ = serviceC.CalculateTyped(context.Background(), 3)
+			if got := callCount(tt.outcome, tt.status) - before; got != 1 {
+				t.Errorf("outcome=%s status=%s 计数增加 %v, want 1", tt.outcome, tt.status, got)
+			}
+		})
+	}
+}
+
+func TestServiceCRejectedCallMetrics(t *testing.T) {
+	b := breaker.New("metrics-test", 1, time.Minute)
+	b.Record(context.DeadlineExceeded)
+	serviceC := NewServiceC("http://127.0.0.1:1", WithBreaker(b))
+
+	before := callCount(outcomeRejected, "none")
+	if _, err := serviceC.CalculateTyped(context.Background(), 3); err == nil {
+		t.Fatal("熔断时应返回错误")
+	}
+	if got := callCount(outcomeRejected, "none") - before; got != 1 {
+		t.Errorf("熔断拒绝计数增加 %v, want 1", got)
+	}
+}
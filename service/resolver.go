@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownService 服务地址解析器中没有该服务
+var ErrUnknownService = errors.New("未知的服务")
+
+// Resolver 服务地址解析器：将服务名解析为 API 基础URL
+// 工厂创建服务时调用；目前提供基于配置的静态实现，接入 DNS、Consul 等服务发现时实现该接口即可
+type Resolver interface {
+	Resolve(ctx context.Context, name string) (string, error)
+}
+
+// StaticResolver 静态服务地址解析器（服务名 -> 基础URL），地址来自 services 配置
+type StaticResolver map[string]string
+
+// Resolve 返回配置的基础URL，服务未配置时返回 ErrUnknownService
+func (r StaticResolver) Resolve(_ context.Context, name string) (string, error) {
+	baseURL, ok := r[name]
+	if !ok || baseURL == "" {
+		return "", fmt.Errorf("%w: %s", ErrUnknownService, name)
+	}
+	return baseURL, nil
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// resolverFunc 函数形式的服务地址解析器
+type resolverFunc func(ctx context.Context, name string) (string, error)
+
+func (f resolverFunc) Resolve(ctx context.Context, name string) (string, error) {
+	return f(ctx, name)
+}
+
+func TestStaticResolver(t *testing.T) {
+	r := StaticResolver{ServiceCName: "http://servicec:8081", "empty": ""}
+
+	if got, err := r.Resolve(context.Background(), ServiceCName); err != nil || got != "http://servicec:8081" {
+		t.Errorf("Resolve(%s) = %q, %v", ServiceCName, got, err)
+	}
+	for _, name := range []string{"missing", "empty"} {
+		if _, err := r.Resolve(context.Background(), name); !errors.Is(err, ErrUnknownService) {
+			t.Errorf("Resolve(%s) err = %v, want ErrUnknownService", name, err)
+		}
+	}
+}
+
+func TestFactoryUsesResolver(t *testing.T) {
+	var resolved []string
+	f := NewFactoryWithConfig(Config{
+		// 配置了解析器时不使用静态地址
+		BaseURLs: map[string]string{ServiceCName: "http://static:1"},
+		Resolver: resolverFunc(func(_ context.Context, name string) (string, error) {
+			resolved = append(resolved, name)
+			return "http://discovered:8081", nil
+		}),
+	})
+
+	serviceC, err := f.GetServiceC()
+	if err != nil {
+		t.Fatalf("GetServiceC: %v", err)
+	}
+	if serviceC.baseURL != "http://discovered:8081" {
+		t.Errorf("baseURL = %q, want 解析器返回的地址", serviceC.baseURL)
+	}
+	if len(resolved) != 1 || resolved[0] != ServiceCName {
+		t.Errorf("解析的服务 = %v, want [%s]", resolved, ServiceCName)
+	}
+}
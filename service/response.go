@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/imroc/req/v3"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrDecodeResponse 下游响应体无法解析为 APIResponse
+var ErrDecodeResponse = errors.New("解析响应失败")
+
+// HTTPStatusError 下游返回非 2xx 的 HTTP 状态码
+type HTTPStatusError struct {
+	StatusCode int    // HTTP 状态码
+	Body       string // 响应体（截断），便于排查网关返回的 HTML 错误页等
+}
+
+func (e *HTTPStatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("HTTP 状态码异常: %d", e.StatusCode)
+	}
+	return fmt.Sprintf("HTTP 状态码异常: %d, 响应: %s", e.StatusCode, e.Body)
+}
+
+// BusinessError 下游返回业务错误（code != 0）
+type BusinessError struct {
+	Code    int    // 业务状态码
+	Message string // 错误消息
+}
+
+func (e *BusinessError) Error() string {
+	return fmt.Sprintf("业务错误 %d: %s", e.Code, e.Message)
+}
+
+// isDownstreamFailure 是否为下游故障（计入熔断）：网络错误、超时和 5xx
+// 4xx、响应格式错误和业务错误说明下游仍可用，调用方取消（客户端断开）或调用方截止时间已到也不计入
+func isDownstreamFailure(ctx context.Context, err error) bool {
+	if err == nil || isCallerDone(ctx, err) {
+		return false
+	}
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500
+	}
+	var bizErr *BusinessError
+	return !errors.As(err, &bizErr) && !errors.Is(err, ErrDecodeResponse)
+}
+
+// isCallerDone 调用是否因调用方取消或调用方上下文到期而失败（与下游状态无关）
+func isCallerDone(ctx context.Context, err error) bool {
+	return errors.Is(err, context.Canceled) || ctx.Err() != nil
+}
+
+// maxErrorBodyLen 错误中保留的响应体最大长度
+const maxErrorBodyLen = 256
+
+// recordHTTPStatus 将下游 HTTP 状态码记录到当前服务层 span
+func recordHTTPStatus(ctx context.Context, resp *req.Response) {
+	trace.SpanFromContext(ctx).SetAttributes(semconv.HTTPStatusCodeKey.Int(resp.StatusCode))
+}
+
+// APIResponseT 标准 API 响应格式（data 为具体类型）
+type APIResponseT[T any] struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    T      `json:"data"`
+}
+
+// parseAPIResponse 解析下游 APIResponse 响应，data 字段解析为 T
+// 依次检查 HTTP 状态码（非 2xx 返回 *HTTPStatusError）、响应体格式（返回 ErrDecodeResponse）
+// 和业务状态码（code != 0 返回 *BusinessError），成功时返回 data 字段
+func parseAPIResponse[T any](resp *req.Response) (T, error) {
+	var zero T
+
+	// 先检查 HTTP 状态码，避免把网关 502 等错误页误报为解析失败
+	if !resp.IsSuccessState() {
+		body := resp.String()
+		if len(body) > maxErrorBodyLen {
+			body = body[:maxErrorBodyLen]
+		}
+		return zero, &HTTPStatusError{StatusCode: resp.StatusCode, Body: body}
+	}
+
+	var apiResp APIResponseT[T]
+	if err := resp.UnmarshalJson(&apiResp); err != nil {
+		return zero, fmt.Errorf("%w: %v", ErrDecodeResponse, err)
+	}
+
+	// 检查业务状态码（code==0 表示成功）
+	if apiResp.Code != 0 {
+		return zero, &BusinessError{Code: apiResp.Code, Message: apiResp.Message}
+	}
+
+	return apiResp.Data, nil
+}
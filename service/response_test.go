@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/imroc/req/v3"
+)
+
+func TestParseAPIResponse(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		check  func(t *testing.T, result CalculateResult, err error)
+	}{
+		{"成功", http.StatusOK, `{"code":0,"message":"ok","data":{"number":3,"result":9}}`,
+			func(t *testing.T, result CalculateResult, err error) {
+				if err != nil || result.Result != 9 {
+					t.Errorf("result = %+v, err = %v", result, err)
+				}
+			}},
+		{"2xx 业务错误", http.StatusOK, `{"code":4001,"message":"参数非法"}`,
+			func(t *testing.T, _ CalculateResult, err error) {
+				var bizErr *BusinessError
+				if !errors.As(err, &bizErr) || bizErr.Code != 4001 || bizErr.Message != "参数非法" {
+					t.Errorf("err = %v, want BusinessError 4001", err)
+				}
+			}},
+		{"非 2xx 空响应体", http.StatusInternalServerError, ``,
+			func(t *testing.T, _ CalculateResult, err error) {
+				var statusErr *HTTPStatusError
+				if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusInternalServerError {
+					t.Errorf("err = %v, want HTTPStatusError 500", err)
+				}
+				if errors.Is(err, ErrDecodeResponse) {
+					t.Error("非 2xx 不应报告为解析失败")
+				}
+			}},
+		{"响应体格式错误", http.StatusOK, `not json`,
+			func(t *testing.T, _ CalculateResult, err error) {
+				if !errors.Is(err, ErrDecodeResponse) {
+					t.Errorf("err = %v, want ErrDecodeResponse", err)
+				}
+			}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := stubServiceC(t, respondWith(tt.status, tt.body))
+			resp, err := req.C().R().Get(server.URL)
+			if err != nil {
+				t.Fatalf("请求失败: %v", err)
+			}
+			result, err := parseAPIResponse[CalculateResult](resp)
+			tt.check(t, result, err)
+		})
+	}
+}
+
+func TestIsDownstreamFailure(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want bool
+	}{
+		{"成功", context.Background(), nil, false},
+		{"网络错误", context.Background(), errors.New("connection refused"), true},
+		{"下游超时", context.Background(), context.DeadlineExceeded, true},
+		{"5xx", context.Background(), &HTTPStatusError{StatusCode: http.StatusBadGateway}, true},
+		{"4xx", context.Background(), &HTTPStatusError{StatusCode: http.StatusNotFound}, false},
+		{"业务错误", context.Background(), &BusinessError{Code: 1001}, false},
+		{"响应格式错误", context.Background(), ErrDecodeResponse, false},
+		{"调用方取消", context.Background(), fmt.Errorf("发送请求: %w", context.Canceled), false},
+		{"调用方上下文已结束", canceled, errors.New("connection reset"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isDownstreamFailure(tt.ctx, tt.err); got != tt.want {
+				t.Errorf("isDownstreamFailure(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServiceCTypedResults(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/calculate", respondWith(http.StatusOK, `{"code":0,"message":"ok","data":{"number":4,"result":16}}`))
+	mux.HandleFunc("/api/process", respondWith(http.StatusOK, `{"code":0,"message":"ok","data":{"content":"abc","result":"ABC"}}`))
+	server := stubServiceC(t, mux.ServeHTTP)
+	serviceC := NewServiceC(server.URL)
+	ctx := context.Background()
+
+	calc, err := serviceC.CalculateTyped(ctx, 4)
+	if err != nil || *calc != (CalculateResult{Number: 4, Result: 16}) {
+		t.Errorf("CalculateTyped = %+v, %v", calc, err)
+	}
+	processed, err := serviceC.ProcessTyped(ctx, "abc")
+	if err != nil || *processed != (ProcessResult{Content: "abc", Result: "ABC"}) {
+		t.Errorf("ProcessTyped = %+v, %v", processed, err)
+	}
+
+	// 已弃用的字符串接口返回 data 的 JSON
+	if got, err := serviceC.Calculate(ctx, 4); err != nil || got != `{"number":4,"result":16}` {
+		t.Errorf("Calculate = %q, %v", got, err)
+	}
+}
@@ -3,11 +3,31 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
+	"gin-project/config"
 	"gin-project/pkg"
+	"gin-project/pkg/breaker"
+
+	"github.com/imroc/req/v3"
+	"github.com/redis/go-redis/v9"
 
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// DefaultFallbackSize 降级默认缓存的最近成功结果数
+	DefaultFallbackSize = 1000
+	// DefaultFallbackTTL 降级结果默认有效期
+	DefaultFallbackTTL = 10 * time.Minute
+
+	// CalculateMemoKey 计算接口结果缓存键格式（按输入数字）
+	CalculateMemoKey = "servicec:calculate:%d"
+	// DefaultMemoizeTTL 结果缓存默认过期时间
+	DefaultMemoizeTTL = time.Minute
 )
 
 // APIResponse 标准 API 响应格式
@@ -17,93 +37,312 @@ type APIResponse struct {
 	Data    map[string]interface{} `json:"data"`
 }
 
+// CalculateResult 计算接口返回数据
+type CalculateResult struct {
+	Number int `json:"number"` // 输入数字
+	Result int `json:"result"` // 计算结果
+}
+
+// ProcessResult 处理接口返回数据
+type ProcessResult struct {
+	Content string `json:"content"` // 输入内容
+	Result  string `json:"result"`  // 处理结果
+}
+
 // ServiceCInterface 服务C的接口定义
 type ServiceCInterface interface {
+	CalculateTyped(ctx context.Context, number int) (*CalculateResult, error)
+	ProcessTyped(ctx context.Context, content string) (*ProcessResult, error)
+	// Deprecated: 使用 CalculateTyped
 	Calculate(ctx context.Context, number int) (string, error)
+	// Deprecated: 使用 ProcessTyped
 	Process(ctx context.Context, content string) (string, error)
 }
 
 // ServiceC 服务C结构体
 type ServiceC struct {
-	baseURL string // API 基础URL
+	baseURL string           // API 基础URL
+	timeout time.Duration    // 单次调用超时（0 表示使用全局客户端）
+	retries int              // 失败重试次数（0 表示使用客户端配置）
+	breaker *breaker.Breaker // 熔断器（nil 表示不熔断）
+	client  *req.Client      // 专用 HTTP 客户端（配置了超时时创建，nil 表示使用全局客户端）
+	// lastGood 最近一次成功的结果（接口+参数 -> 结果），用于降级（nil 表示不降级）
+	lastGood *pkg.LRU[string, interface{}]
+	// memo 计算接口结果缓存（nil 表示不缓存），memoTTL 为缓存时间
+	memo    *redis.Client
+	memoTTL time.Duration
+}
+
+// ServiceCOption 服务C配置选项
+type ServiceCOption func(*ServiceC)
+
+// WithTimeout 设置单次调用超时
+// 配置后服务C使用携带该超时的专用 HTTP 客户端（保留追踪和重试配置），不受其他服务的超时设置影响；
+// 调用方上下文的截止时间同样生效，两者取较早者
+func WithTimeout(timeout time.Duration) ServiceCOption {
+	return func(s *ServiceC) {
+		s.timeout = timeout
+	}
+}
+
+// WithRetries 设置失败重试次数（使用 pkg.RetryCondition：仅对临时性失败重试并受全局重试预算限制），0 表示使用客户端配置
+func WithRetries(retries int) ServiceCOption {
+	return func(s *ServiceC) {
+		s.retries = retries
+	}
+}
+
+// WithBreaker 设置熔断器，下游连续故障时暂停调用
+// 熔断器应从注册表获取（breaker.Default().Get），以便健康检查汇总其状态
+func WithBreaker(b *breaker.Breaker) ServiceCOption {
+	return func(s *ServiceC) {
+		s.breaker = b
+	}
+}
+
+// WithFallback 设置降级：下游故障或熔断时返回相同接口和参数最近一次成功的结果
+// 未启用时不降级，直接返回错误
+func WithFallback(cfg config.ServiceFallback) ServiceCOption {
+	return func(s *ServiceC) {
+		if !cfg.Enabled {
+			return
+		}
+		size, ttl := cfg.Size, cfg.TTL
+		if size <= 0 {
+			size = DefaultFallbackSize
+		}
+		if ttl <= 0 {
+			ttl = DefaultFallbackTTL
+		}
+		s.lastGood = pkg.NewLRU[string, interface{}](size, ttl)
+	}
+}
+
+// WithMemoize 设置计算接口结果缓存：相同输入在 TTL 内直接返回 Redis 中的结果，不调用下游
+// 仅适用于结果只取决于输入的纯函数接口，未启用或 client 为 nil 时不缓存
+func WithMemoize(client *redis.Client, cfg config.ServiceMemoize) ServiceCOption {
+	return func(s *ServiceC) {
+		if !cfg.Enabled || client == nil {
+			return
+		}
+		s.memo = client
+		s.memoTTL = cfg.TTL
+		if s.memoTTL <= 0 {
+			s.memoTTL = DefaultMemoizeTTL
+		}
+	}
 }
 
 // NewServiceC 创建服务C实例
-func NewServiceC(baseURL string) *ServiceC {
-	return &ServiceC{
+func NewServiceC(baseURL string, opts ...ServiceCOption) *ServiceC {
+	s := &ServiceC{
 		baseURL: baseURL,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.timeout > 0 {
+		s.client = pkg.NewHTTPClient(s.timeout)
+	}
+	registerServiceCMetrics()
+	return s
 }
 
-// Calculate 计算接口 - 纯业务逻辑，无追踪代码
-// HTTP 请求追踪：由 pkg.HTTPClient 自动处理（零代码入侵）
-func (s *ServiceC) Calculate(ctx context.Context, number int) (string, error) {
-	// 构建请求体
-	reqBody := map[string]int{"number": number}
-
-	// 使用带追踪的 HTTP 客户端，自动注入 TraceID 到请求头
-	url := s.baseURL + "/api/calculate"
-	resp, err := pkg.HTTPClient().R().
-		SetContext(ctx).
-		SetBody(reqBody).
-		Post(url)
-	if err != nil {
-		return "", fmt.Errorf("调用计算接口失败: %v", err)
+// httpClient 获取调用使用的 HTTP 客户端（专用客户端优先，否则使用全局客户端）
+func (s *ServiceC) httpClient() *req.Client {
+	if s.client != nil {
+		return s.client
+	}
+	return pkg.HTTPClient()
+}
+
+// allow 熔断器是否允许调用（未配置熔断器时始终允许）
+func (s *ServiceC) allow() error {
+	if s.breaker == nil {
+		return nil
+	}
+	return s.breaker.Allow()
+}
+
+// record 向熔断器上报调用结果，仅下游故障计为失败
+// 调用方取消的调用无法说明下游状态，既不计为成功也不计为失败
+func (s *ServiceC) record(ctx context.Context, err error) {
+	if s.breaker == nil {
+		return
+	}
+	if err != nil && isCallerDone(ctx, err) {
+		s.breaker.Release()
+		return
 	}
+	if !isDownstreamFailure(ctx, err) {
+		err = nil
+	}
+	s.breaker.Record(err)
+}
 
-	// 解析响应
-	var apiResp APIResponse
-	if err := resp.UnmarshalJson(&apiResp); err != nil {
-		return "", fmt.Errorf("解析响应失败: %v", err)
+// fallbackKey 降级结果的缓存键（接口路径+请求参数）
+func fallbackKey(path string, body interface{}) string {
+	data, _ := json.Marshal(body)
+	return path + " " + string(data)
+}
+
+// fallback 下游故障或熔断时返回最近一次成功的结果，并在 span 上标记 fallback=true
+// 未启用降级、错误不属于下游故障或没有可用结果时返回 false
+func fallback[T any](ctx context.Context, s *ServiceC, key string, err error) (*T, bool) {
+	if s.lastGood == nil || !(errors.Is(err, breaker.ErrOpen) || isDownstreamFailure(ctx, err)) {
+		return nil, false
+	}
+	cached, ok := s.lastGood.Get(key)
+	if !ok {
+		return nil, false
 	}
+	result, ok := cached.(*T)
+	if !ok {
+		return nil, false
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.Bool("fallback", true),
+		attribute.String("fallback.reason", err.Error()),
+	)
+	pkg.LoggerFromContext(ctx).Warn("服务C调用失败，返回最近一次成功的结果", "key", key, "error", err)
+	return result, true
+}
 
-	// 检查业务状态码（code==0 表示成功）
-	if apiResp.Code != 0 {
-		return "", fmt.Errorf("计算接口返回错误: %s", apiResp.Message)
+// CalculateTyped 计算接口 - 纯业务逻辑，无追踪代码
+// HTTP 请求追踪：由 pkg.HTTPClient 自动处理（零代码入侵）
+// 启用结果缓存时优先从 Redis 获取相同输入的结果，并在 span 上记录 cache=hit/miss 事件
+func (s *ServiceC) CalculateTyped(ctx context.Context, number int) (*CalculateResult, error) {
+	memoKey := fmt.Sprintf(CalculateMemoKey, number)
+	if result, ok := memoGet[CalculateResult](ctx, s, memoKey); ok {
+		return result, nil
 	}
 
-	// 将 data 转换为 JSON 字符串返回
-	dataBytes, err := json.Marshal(apiResp.Data)
+	// 计算接口无副作用，标记为可安全重试（POST 默认不重试）
+	result, err := callServiceC[CalculateResult](ctx, s, "/api/calculate", "计算接口", map[string]int{"number": number})
 	if err != nil {
-		return "", fmt.Errorf("序列化响应数据失败: %v", err)
+		return nil, err
 	}
+	memoSet(ctx, s, memoKey, result)
+	return result, nil
+}
 
-	return string(dataBytes), nil
+// memoGet 从结果缓存获取，未启用缓存时返回 false（不记录事件）
+func memoGet[T any](ctx context.Context, s *ServiceC, key string) (*T, bool) {
+	if s.memo == nil {
+		return nil, false
+	}
+
+	span := trace.SpanFromContext(ctx)
+	data, err := s.memo.Get(ctx, key).Bytes()
+	if err == nil {
+		var result T
+		if json.Unmarshal(data, &result) == nil {
+			span.AddEvent("cache", trace.WithAttributes(attribute.String("cache", "hit"), attribute.String("cache.key", key)))
+			return &result, true
+		}
+	}
+	span.AddEvent("cache", trace.WithAttributes(attribute.String("cache", "miss"), attribute.String("cache.key", key)))
+	return nil, false
 }
 
-// Process 处理接口 - 纯业务逻辑，无追踪代码
+// memoSet 写入结果缓存（未启用缓存时忽略，写入失败不影响调用结果）
+func memoSet(ctx context.Context, s *ServiceC, key string, result interface{}) {
+	if s.memo == nil {
+		return
+	}
+	if data, err := json.Marshal(result); err == nil {
+		s.memo.Set(ctx, key, data, s.memoTTL)
+	}
+}
+
+// ProcessTyped 处理接口 - 纯业务逻辑，无追踪代码
 // HTTP 请求追踪：由 pkg.HTTPClient 自动处理（零代码入侵）
+func (s *ServiceC) ProcessTyped(ctx context.Context, content string) (*ProcessResult, error) {
+	// 处理接口无副作用，标记为可安全重试（POST 默认不重试）
+	return callServiceC[ProcessResult](ctx, s, "/api/process", "处理接口", map[string]string{"content": content})
+}
+
+// Calculate 计算接口，返回 data 的 JSON 字符串
+//
+// Deprecated: 使用 CalculateTyped 获取类型化结果
+func (s *ServiceC) Calculate(ctx context.Context, number int) (string, error) {
+	return marshalResult(s.CalculateTyped(ctx, number))
+}
+
+// Process 处理接口，返回 data 的 JSON 字符串
+//
+// Deprecated: 使用 ProcessTyped 获取类型化结果
 func (s *ServiceC) Process(ctx context.Context, content string) (string, error) {
-	// 构建请求体
-	reqBody := map[string]string{"content": content}
-
-	// 使用带追踪的 HTTP 客户端，自动注入 TraceID 到请求头
-	url := s.baseURL + "/api/process"
-	resp, err := pkg.HTTPClient().R().
-		SetContext(ctx).
-		SetBody(reqBody).
-		Post(url)
+	return marshalResult(s.ProcessTyped(ctx, content))
+}
+
+// callServiceC 调用服务C的无副作用 POST 接口，并将响应 data 解析为 T
+// 使用带追踪的 HTTP 客户端，自动注入 TraceID 到请求头；调用结果上报熔断器，
+// 启用降级时成功结果按接口+参数保存，失败时返回最近一次成功的结果
+func callServiceC[T any](ctx context.Context, s *ServiceC, path, name string, body interface{}) (*T, error) {
+	key := fallbackKey(path, body)
+	result, err := doCallServiceC[T](ctx, s, path, name, body)
 	if err != nil {
-		return "", fmt.Errorf("调用处理接口失败: %v", err)
+		if cached, ok := fallback[T](ctx, s, key, err); ok {
+			return cached, nil
+		}
+		return nil, err
 	}
+	if s.lastGood != nil {
+		s.lastGood.Set(key, result)
+	}
+	return result, nil
+}
 
-	// 解析响应
-	var apiResp APIResponse
-	if err := resp.UnmarshalJson(&apiResp); err != nil {
-		return "", fmt.Errorf("解析响应失败: %v", err)
+// doCallServiceC 执行一次服务C调用（不含降级），调用结果和耗时记录到 Prometheus 指标
+func doCallServiceC[T any](ctx context.Context, s *ServiceC, path, name string, body interface{}) (*T, error) {
+	start := time.Now()
+	if err := s.allow(); err != nil {
+		observeCall(path, start, nil, err)
+		return nil, fmt.Errorf("调用%s失败: %w", name, err)
 	}
 
-	// 检查业务状态码（code==0 表示成功）
-	if apiResp.Code != 0 {
-		return "", fmt.Errorf("处理接口返回错误: %s", apiResp.Message)
+	request := s.httpClient().R().
+		SetContext(pkg.WithRetrySafe(ctx)).
+		SetBody(body)
+	if s.retries > 0 {
+		// 请求级重试不继承客户端的重试条件：显式使用共享条件，保留方法检查和全局重试预算（退避区间沿用客户端配置）
+		request.SetRetryCount(s.retries).
+			SetRetryCondition(pkg.RetryCondition)
+	}
+	resp, err := request.Post(s.baseURL + path)
+	if err != nil {
+		s.record(ctx, err)
+		observeCall(path, start, resp, err)
+		return nil, fmt.Errorf("调用%s失败: %v", name, err)
 	}
 
-	// 将 data 转换为 JSON 字符串返回
-	dataBytes, err := json.Marshal(apiResp.Data)
+	// 记录 HTTP 状态码到 span，非 2xx 视为传输错误（不再尝试解析响应体）
+	recordHTTPStatus(ctx, resp)
+	data, err := parseAPIResponse[T](resp)
+	s.record(ctx, err)
+	observeCall(path, start, resp, err)
 	if err != nil {
-		return "", fmt.Errorf("序列化响应数据失败: %v", err)
+		var statusErr *HTTPStatusError
+		if errors.As(err, &statusErr) {
+			return nil, fmt.Errorf("调用%s失败: %w", name, err)
+		}
+		return nil, fmt.Errorf("%s返回错误: %w", name, err)
 	}
 
+	return &data, nil
+}
+
+// marshalResult 将类型化结果序列化为 JSON 字符串（兼容旧的字符串返回值）
+func marshalResult[T any](result *T, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	dataBytes, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("序列化响应数据失败: %v", err)
+	}
 	return string(dataBytes), nil
 }
 
@@ -112,17 +351,17 @@ func (s *ServiceC) Process(ctx context.Context, content string) (string, error)
 // 业务逻辑追踪：由 TraceServiceFunc 装饰器处理
 type ServiceCWithTrace struct {
 	*ServiceC
-	calculate func(context.Context, int) (string, error)
-	process   func(context.Context, string) (string, error)
+	calculate func(context.Context, int) (*CalculateResult, error)
+	process   func(context.Context, string) (*ProcessResult, error)
 }
 
 // NewServiceCWithTrace 创建带追踪的服务C实例
-func NewServiceCWithTrace(baseURL string) *ServiceCWithTrace {
-	serviceC := NewServiceC(baseURL)
+func NewServiceCWithTrace(baseURL string, opts ...ServiceCOption) *ServiceCWithTrace {
+	serviceC := NewServiceC(baseURL, opts...)
 	return &ServiceCWithTrace{
 		ServiceC: serviceC,
 		// 追踪业务逻辑层（HTTP 请求已由 pkg.HTTPClient 自动追踪）
-		calculate: pkg.TraceServiceFunc("ServiceC.Calculate", serviceC.Calculate,
+		calculate: pkg.TraceServiceFunc("ServiceC.Calculate", serviceC.CalculateTyped,
 			func(ctx context.Context, number int) []attribute.KeyValue {
 				return []attribute.KeyValue{
 					attribute.String("service.name", "ServiceC"),
@@ -130,7 +369,7 @@ func NewServiceCWithTrace(baseURL string) *ServiceCWithTrace {
 					attribute.Int("input.number", number),
 				}
 			}),
-		process: pkg.TraceServiceFunc("ServiceC.Process", serviceC.Process,
+		process: pkg.TraceServiceFunc("ServiceC.Process", serviceC.ProcessTyped,
 			func(ctx context.Context, content string) []attribute.KeyValue {
 				return []attribute.KeyValue{
 					attribute.String("service.name", "ServiceC"),
@@ -141,12 +380,26 @@ func NewServiceCWithTrace(baseURL string) *ServiceCWithTrace {
 	}
 }
 
-// Calculate 带追踪的计算方法
-func (s *ServiceCWithTrace) Calculate(ctx context.Context, number int) (string, error) {
+// CalculateTyped 带追踪的计算方法
+func (s *ServiceCWithTrace) CalculateTyped(ctx context.Context, number int) (*CalculateResult, error) {
 	return s.calculate(ctx, number)
 }
 
-// Process 带追踪的处理方法
-func (s *ServiceCWithTrace) Process(ctx context.Context, content string) (string, error) {
+// ProcessTyped 带追踪的处理方法
+func (s *ServiceCWithTrace) ProcessTyped(ctx context.Context, content string) (*ProcessResult, error) {
 	return s.process(ctx, content)
 }
+
+// Calculate 带追踪的计算方法，返回 data 的 JSON 字符串
+//
+// Deprecated: 使用 CalculateTyped 获取类型化结果
+func (s *ServiceCWithTrace) Calculate(ctx context.Context, number int) (string, error) {
+	return marshalResult(s.CalculateTyped(ctx, number))
+}
+
+// Process 带追踪的处理方法，返回 data 的 JSON 字符串
+//
+// Deprecated: 使用 ProcessTyped 获取类型化结果
+func (s *ServiceCWithTrace) Process(ctx context.Context, content string) (string, error) {
+	return marshalResult(s.ProcessTyped(ctx, content))
+}
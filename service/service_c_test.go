@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gin-project/config"
+	"gin-project/pkg/breaker"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	sdktracetest "go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+)
+
+// recordedCall 在内存记录器的 span 中执行 fn，返回结束后的 span
+func recordedCall(t *testing.T, fn func(ctx context.Context)) sdktrace.ReadOnlySpan {
+	t.Helper()
+	recorder := sdktracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	ctx, span := provider.Tracer("service-test").Start(context.Background(), "call")
+	fn(ctx)
+	span.End()
+	for _, ended := range recorder.Ended() {
+		if ended.Name() == "call" {
+			return ended
+		}
+	}
+	t.Fatal("未记录到调用方 span")
+	return nil
+}
+
+// spanAttr 查找 span 上指定键的属性值
+func spanAttr(span sdktrace.ReadOnlySpan, key attribute.Key) (attribute.Value, bool) {
+	for _, attr := range span.Attributes() {
+		if attr.Key == key {
+			return attr.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestServiceCReportsNon2xxStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		body   string
+	}{
+		{http.StatusBadGateway, "<html><body>502 Bad Gateway</body></html>"},
+		{http.StatusNotFound, `{"code":404,"message":"not found"}`},
+	}
+	for _, tt := range tests {
+		server := stubServiceC(t, respondWith(tt.status, tt.body))
+		s := NewServiceC(server.URL)
+
+		var err error
+		span := recordedCall(t, func(ctx context.Context) {
+			_, err = s.CalculateTyped(ctx, 3)
+		})
+
+		var statusErr *HTTPStatusError
+		if !errors.As(err, &statusErr) || statusErr.StatusCode != tt.status {
+			t.Fatalf("status %d: err = %v, want HTTPStatusError", tt.status, err)
+		}
+		if errors.Is(err, ErrDecodeResponse) {
+			t.Errorf("status %d: 不应报告为解析失败", tt.status)
+		}
+		if !strings.Contains(err.Error(), tt.body[:10]) {
+			t.Errorf("status %d: 错误中缺少响应体: %v", tt.status, err)
+		}
+
+		var recorded bool
+		for _, attr := range span.Attributes() {
+			if attr.Key == semconv.HTTPStatusCodeKey && attr.Value.AsInt64() == int64(tt.status) {
+				recorded = true
+			}
+		}
+		if !recorded {
+			t.Errorf("status %d: span 上缺少 http.status_code, attrs = %v", tt.status, span.Attributes())
+		}
+	}
+}
+
+func TestServiceCTimeoutWhicheverIsSooner(t *testing.T) {
+	tests := []struct {
+		name          string
+		clientTimeout time.Duration
+		ctxTimeout    time.Duration
+	}{
+		{"客户端超时较早", 50 * time.Millisecond, 5 * time.Second},
+		{"上下文截止时间较早", 5 * time.Second, 50 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// release 在关闭服务前释放仍在等待的请求
+			release := make(chan struct{})
+			server := stubServiceC(t, func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-r.Context().Done():
+				case <-release:
+				}
+			})
+			t.Cleanup(func() { close(release) })
+			s := NewServiceC(server.URL, WithTimeout(tt.clientTimeout))
+			ctx, cancel := context.WithTimeout(context.Background(), tt.ctxTimeout)
+			defer cancel()
+
+			start := time.Now()
+			_, err := s.CalculateTyped(ctx, 1)
+			if err == nil {
+				t.Fatal("超时应返回错误")
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("耗时 %v，应在较早的超时后返回", elapsed)
+			}
+		})
+	}
+}
+
+func TestServiceCCallerCancelDoesNotTripBreaker(t *testing.T) {
+	tests := []struct {
+		name          string
+		clientTimeout time.Duration
+		callerCtx     func() (context.Context, context.CancelFunc)
+		want          breaker.State
+	}{
+		{"调用方取消", 0, func() (context.Context, context.CancelFunc) {
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(20*time.Millisecond, cancel)
+			return ctx, cancel
+		}, breaker.StateClosed},
+		{"调用方截止时间到达", 0, func() (context.Context, context.CancelFunc) {
+			return context.WithTimeout(context.Background(), 20*time.Millisecond)
+		}, breaker.StateClosed},
+		{"客户端超时（下游过慢）", 20 * time.Millisecond, func() (context.Context, context.CancelFunc) {
+			return context.WithCancel(context.Background())
+		}, breaker.StateOpen},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := make(chan struct{})
+			server := stubServiceC(t, func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-r.Context().Done():
+				case <-release:
+				}
+			})
+			t.Cleanup(func() { close(release) })
+			b := breaker.New("caller-cancel-test", 1, time.Minute)
+			s := NewServiceC(server.URL, WithTimeout(tt.clientTimeout), WithBreaker(b))
+			ctx, cancel := tt.callerCtx()
+			defer cancel()
+
+			if _, err := s.CalculateTyped(ctx, 1); err == nil {
+				t.Fatal("调用应失败")
+			}
+			if got := b.State(); got != tt.want {
+				t.Errorf("熔断器状态 = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServiceCFallsBackToLastGoodResult(t *testing.T) {
+	var failing atomic.Bool
+	var hits atomic.Int32
+	server := stubServiceC(t, func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if failing.Load() {
+			respondWith(http.StatusInternalServerError, `{"code":500,"message":"boom"}`)(w, r)
+			return
+		}
+		respondWith(http.StatusOK, `{"code":0,"message":"ok","data":{"number":3,"result":9}}`)(w, r)
+	})
+	serviceC := NewServiceC(server.URL,
+		WithBreaker(breaker.New("fallback-test", 1, time.Minute)),
+		WithFallback(config.ServiceFallback{Enabled: true}),
+	)
+
+	if result, err := serviceC.CalculateTyped(context.Background(), 3); err != nil || result.Result != 9 {
+		t.Fatalf("首次调用 = %+v, %v", result, err)
+	}
+
+	failing.Store(true)
+	// 下游返回 5xx：返回最近一次成功的结果，并在 span 上标记 fallback
+	span := recordedCall(t, func(ctx context.Context) {
+		result, err := serviceC.CalculateTyped(ctx, 3)
+		if err != nil || result.Result != 9 {
+			t.Errorf("下游故障时应降级，got %+v, %v", result, err)
+		}
+	})
+	if fallback, ok := spanAttr(span, "fallback"); !ok || !fallback.AsBool() {
+		t.Error("降级时 span 应记录 fallback=true")
+	}
+
+	// 熔断器已打开：不再请求下游，同样返回最近一次成功的结果
+	before := hits.Load()
+	if result, err := serviceC.CalculateTyped(context.Background(), 3); err != nil || result.Result != 9 {
+		t.Errorf("熔断时应降级，got %+v, %v", result, err)
+	}
+	if hits.Load() != before {
+		t.Error("熔断期间不应请求下游")
+	}
+
+	// 没有成功结果的参数无法降级
+	if _, err := serviceC.CalculateTyped(context.Background(), 4); !errors.Is(err, breaker.ErrOpen) {
+		t.Errorf("无可用降级结果时应返回熔断错误，got %v", err)
+	}
+}
+
+func TestServiceCWithoutFallbackReturnsError(t *testing.T) {
+	server := stubServiceC(t, respondWith(http.StatusInternalServerError, `{"code":500,"message":"boom"}`))
+	serviceC := NewServiceC(server.URL)
+
+	span := recordedCall(t, func(ctx context.Context) {
+		if _, err := serviceC.CalculateTyped(ctx, 3); err == nil {
+			t.Error("未启用降级时应返回错误")
+		}
+	})
+	if _, ok := spanAttr(span, "fallback"); ok {
+		t.Error("未降级时不应记录 fallback 属性")
+	}
+}